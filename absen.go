@@ -0,0 +1,164 @@
+package gibrun
+
+import (
+	"context"
+	"math/bits"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BitOperation is a bitwise operation combining bitmaps, see BitOp.
+type BitOperation string
+
+const (
+	BitAnd BitOperation = "AND"
+	BitOr  BitOperation = "OR"
+	BitXor BitOperation = "XOR"
+)
+
+// AbsenBuilder provides a fluent API for Redis bitmaps.
+// "Absen" is the attendance sheet: one bit per user or per day, so a
+// million flags take 128KB. Bit offsets count from the most significant
+// bit of the first byte, as in SETBIT.
+type AbsenBuilder struct {
+	ctx    context.Context
+	client *Client
+	key    string
+}
+
+// Absen starts a bitmap operation.
+//
+// Example:
+//
+//	// per-day key, one bit per user
+//	app.Absen(ctx, "active:2024-06-01").SetBit(userID, true)
+//	dau, err := app.Absen(ctx, "active:2024-06-01").Count()
+//
+//	// per-user key, one bit per day
+//	app.Absen(ctx, "active:user:42").MarkDay(time.Now())
+//	streak, err := app.Absen(ctx, "active:user:42").Streak(time.Now())
+func (c *Client) Absen(ctx context.Context, key string) *AbsenBuilder {
+	return &AbsenBuilder{
+		ctx:    ctx,
+		client: c,
+		key:    key,
+	}
+}
+
+// SetBit sets or clears the bit at offset and returns its previous value.
+func (b *AbsenBuilder) SetBit(offset int64, on bool) (bool, error) {
+	value := 0
+	if on {
+		value = 1
+	}
+	prev, err := b.client.rdb.SetBit(b.ctx, b.key, offset, value).Result()
+	return prev == 1, err
+}
+
+// GetBit returns the bit at offset; bits past the end are false.
+func (b *AbsenBuilder) GetBit(offset int64) (bool, error) {
+	bit, err := b.client.rdb.GetBit(b.ctx, b.key, offset).Result()
+	return bit == 1, err
+}
+
+// Count returns the number of set bits.
+func (b *AbsenBuilder) Count() (int64, error) {
+	return b.client.rdb.BitCount(b.ctx, b.key, nil).Result()
+}
+
+// CountRange returns the number of set bits between bit offsets start
+// and end, inclusive. Uses BITCOUNT BIT on Redis 7+ and counts a
+// GETRANGE of the covering bytes on older servers.
+func (b *AbsenBuilder) CountRange(start, end int64) (int64, error) {
+	if start > end || end < 0 {
+		return 0, nil
+	}
+	start = max(start, 0)
+	if b.client.requireVersion(b.ctx, "BITCOUNT BIT", Version{Major: 7}) == nil {
+		return b.client.rdb.BitCount(b.ctx, b.key, &redis.BitCount{
+			Start: start, End: end, Unit: redis.BitCountIndexBit,
+		}).Result()
+	}
+
+	data, err := b.client.rdb.GetRange(b.ctx, b.key, start/8, end/8).Bytes()
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	for i, c := range data {
+		offset := (start/8 + int64(i)) * 8
+		// Mask off bits outside [start, end] in the edge bytes
+		if offset < start {
+			c &= 0xff >> (start - offset)
+		}
+		if offset+7 > end {
+			c &= 0xff << (offset + 7 - end)
+		}
+		n += int64(bits.OnesCount8(c))
+	}
+	return n, nil
+}
+
+// BitOp stores op applied to this bitmap and others at dest, and
+// returns the size of dest in bytes.
+//
+// Example:
+//
+//	// users active on both days
+//	_, err := app.Absen(ctx, "active:2024-06-01").BitOp(gibrun.BitAnd, "active:both", "active:2024-06-02")
+func (b *AbsenBuilder) BitOp(op BitOperation, dest string, others ...string) (int64, error) {
+	keys := append([]string{b.key}, others...)
+	switch op {
+	case BitAnd:
+		return b.client.rdb.BitOpAnd(b.ctx, dest, keys...).Result()
+	case BitOr:
+		return b.client.rdb.BitOpOr(b.ctx, dest, keys...).Result()
+	case BitXor:
+		return b.client.rdb.BitOpXor(b.ctx, dest, keys...).Result()
+	}
+	return 0, ErrInvalidBitOp
+}
+
+// DayIndex returns the bit offset used for t by the day helpers: whole
+// days since the Unix epoch, in UTC.
+func DayIndex(t time.Time) int64 {
+	return t.UTC().Unix() / 86400
+}
+
+// MarkDay sets the bit for the day of t and reports whether it was
+// already set.
+func (b *AbsenBuilder) MarkDay(t time.Time) (bool, error) {
+	return b.SetBit(DayIndex(t), true)
+}
+
+// OnDay reports whether the day of t is set.
+func (b *AbsenBuilder) OnDay(t time.Time) (bool, error) {
+	return b.GetBit(DayIndex(t))
+}
+
+// CountDays returns the number of set days from the day of from to the
+// day of to, inclusive.
+func (b *AbsenBuilder) CountDays(from, to time.Time) (int64, error) {
+	return b.CountRange(DayIndex(from), DayIndex(to))
+}
+
+// Streak returns the number of consecutive set days ending on the day
+// of asOf, 0 if that day isn't set.
+func (b *AbsenBuilder) Streak(asOf time.Time) (int, error) {
+	day := DayIndex(asOf)
+	data, err := b.client.rdb.GetRange(b.ctx, b.key, 0, day/8).Bytes()
+	if err != nil {
+		return 0, err
+	}
+
+	streak := 0
+	for d := day; d >= 0; d-- {
+		i := d / 8
+		if i >= int64(len(data)) || data[i]&(0x80>>(d%8)) == 0 {
+			break
+		}
+		streak++
+	}
+	return streak, nil
+}
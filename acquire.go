@@ -0,0 +1,36 @@
+package gibrun
+
+// IfNotExists only writes the value when the key doesn't exist yet
+// (SET NX). Exec silently skips the write when the key is taken; use
+// ExecAcquired to learn which happened. Not supported together with
+// AsHash, Append or IfVersion, and values are never chunked.
+//
+// Example:
+//
+//	err := app.Gib(ctx, "config:defaults").Value(defaults).IfNotExists().Exec()
+func (b *GibBuilder) IfNotExists() *GibBuilder {
+	b.ifNotExists = true
+	return b
+}
+
+// ExecAcquired executes the write and reports whether it took place.
+// Combined with IfNotExists and a TTL it is a simple lease lock: the
+// value is a token identifying the holder, and the TTL bounds how long a
+// crashed holder can keep the lease. Releasing early with Del is only
+// safe while the holder is sure its lease hasn't expired. Without
+// IfNotExists it always reports true on success.
+//
+// Example:
+//
+//	token := uuid.NewString()
+//	ok, err := app.Gib(ctx, "lease:report-job").Value(token).TTL(30*time.Second).IfNotExists().ExecAcquired()
+//	if err == nil && ok {
+//	    runReport() // lease lapses after 30s
+//	}
+func (b *GibBuilder) ExecAcquired() (bool, error) {
+	b.acquired = !b.ifNotExists
+	if err := b.Exec(); err != nil {
+		return false, err
+	}
+	return b.acquired, nil
+}
@@ -0,0 +1,176 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AntriBuilder provides a fluent API for Redis lists.
+// "Antri" means to queue up: items pushed on the right are popped from
+// the left, FIFO. Items are marshalled exactly like Gib.
+type AntriBuilder struct {
+	ctx    context.Context
+	client *Client
+	key    string
+	codec  Codec
+}
+
+// AntriRange is a range of list items waiting to be bound, see Range.
+type AntriRange struct {
+	b           *AntriBuilder
+	start, stop int64
+}
+
+// Antri starts a list operation, for simple work handoff between
+// processes and recent-items lists.
+//
+// Example:
+//
+//	jobs := app.Antri(ctx, "jobs:email")
+//	jobs.Push(Email{To: "a@example.com"})
+//
+//	var job Email
+//	found, err := jobs.BPopLeft(5*time.Second, &job)
+//
+//	var recent []Event
+//	err = app.Antri(ctx, "events:recent").Range(0, -1).Bind(&recent)
+func (c *Client) Antri(ctx context.Context, key string) *AntriBuilder {
+	return &AntriBuilder{
+		ctx:    ctx,
+		client: c,
+		key:    key,
+	}
+}
+
+// Codec overrides the client codec for this operation.
+func (b *AntriBuilder) Codec(c Codec) *AntriBuilder {
+	b.codec = c
+	return b
+}
+
+// Push appends values to the right of the list and returns its new length.
+func (b *AntriBuilder) Push(values ...any) (int64, error) {
+	items, err := b.marshal(values)
+	if err != nil {
+		return 0, err
+	}
+	return b.client.rdb.RPush(b.ctx, b.key, items...).Result()
+}
+
+// PushLeft prepends values to the left of the list, so they are popped
+// next, and returns its new length.
+func (b *AntriBuilder) PushLeft(values ...any) (int64, error) {
+	items, err := b.marshal(values)
+	if err != nil {
+		return 0, err
+	}
+	return b.client.rdb.LPush(b.ctx, b.key, items...).Result()
+}
+
+// PopLeft removes the oldest item and unmarshals it into dest.
+// Returns (false, nil) if the list is empty.
+func (b *AntriBuilder) PopLeft(dest any) (bool, error) {
+	if dest == nil {
+		return false, ErrNilPointer
+	}
+	data, err := b.client.rdb.LPop(b.ctx, b.key).Bytes()
+	return b.decode(data, err, dest)
+}
+
+// BPopLeft is PopLeft that waits up to timeout for an item to arrive.
+// A zero timeout blocks until an item arrives or ctx is done.
+// Returns (false, nil) if the timeout passes with the list still empty.
+func (b *AntriBuilder) BPopLeft(timeout time.Duration, dest any) (bool, error) {
+	if dest == nil {
+		return false, ErrNilPointer
+	}
+	res, err := b.client.rdb.BLPop(b.ctx, timeout, b.key).Result()
+	if err != nil {
+		return b.decode(nil, err, dest)
+	}
+	return b.decode([]byte(res[1]), nil, dest)
+}
+
+// Range selects items from start to stop, inclusive; negative indexes
+// count from the end, so Range(0, -1) is the whole list.
+func (b *AntriBuilder) Range(start, stop int64) *AntriRange {
+	return &AntriRange{b: b, start: start, stop: stop}
+}
+
+// Bind unmarshals the selected items into dest, a pointer to a slice.
+// An empty or missing list yields an empty slice.
+func (r *AntriRange) Bind(dest any) error {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrNilPointer
+	}
+	s := rv.Elem()
+	if s.Kind() != reflect.Slice {
+		return fmt.Errorf("gibrun: Range Bind needs a pointer to a slice, got %T", dest)
+	}
+
+	items, err := r.b.client.rdb.LRange(r.b.ctx, r.b.key, r.start, r.stop).Result()
+	if err != nil {
+		return err
+	}
+	out := reflect.MakeSlice(s.Type(), 0, len(items))
+	for i, item := range items {
+		elem := reflect.New(s.Type().Elem())
+		if err := r.b.client.enc.unmarshal([]byte(item), elem.Interface(), r.b.codec); err != nil {
+			return fmt.Errorf("gibrun: decode %s[%d]: %w", r.b.key, r.start+int64(i), err)
+		}
+		out = reflect.Append(out, elem.Elem())
+	}
+	s.Set(out)
+	return nil
+}
+
+// Trim keeps only the n most recently pushed items (the right end).
+func (b *AntriBuilder) Trim(n int64) error {
+	if n <= 0 {
+		return b.client.rdb.Del(b.ctx, b.key).Err()
+	}
+	return b.client.rdb.LTrim(b.ctx, b.key, -n, -1).Err()
+}
+
+// Len returns the number of items in the list.
+func (b *AntriBuilder) Len() (int64, error) {
+	return b.client.rdb.LLen(b.ctx, b.key).Result()
+}
+
+// marshal encodes values for a push.
+func (b *AntriBuilder) marshal(values []any) ([]any, error) {
+	if len(values) == 0 {
+		return nil, ErrNilValue
+	}
+	items := make([]any, len(values))
+	for i, v := range values {
+		if v == nil {
+			return nil, ErrNilValue
+		}
+		data, err := b.client.enc.marshal(v, b.codec)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = data
+	}
+	return items, nil
+}
+
+// decode unmarshals a popped item into dest, treating redis.Nil as empty.
+func (b *AntriBuilder) decode(data []byte, err error, dest any) (bool, error) {
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := b.client.enc.unmarshal(data, dest, b.codec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,153 @@
+package gibrun
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AsyncConfig configures the background writer used by ExecAsync.
+type AsyncConfig struct {
+	// Workers is the number of goroutines flushing writes. Default is 4.
+	Workers int
+
+	// QueueSize bounds pending writes. ExecAsync fails fast with
+	// ErrAsyncQueueFull beyond it. Default is 1024.
+	QueueSize int
+
+	// Retries is how many times a failed write is retried. Default is 2;
+	// set a negative value to disable retries.
+	Retries int
+
+	// Backoff is the delay before the first retry, doubled on each
+	// following one. Default is 100ms.
+	Backoff time.Duration
+
+	// OnError is called when a write still fails after all retries.
+	OnError func(key string, err error)
+}
+
+// asyncWriter drains queued builders on a fixed worker pool.
+type asyncWriter struct {
+	cfg   AsyncConfig
+	clock Clock
+	queue chan *GibBuilder
+	wg    sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+func newAsyncWriter(cfg AsyncConfig, clock Clock) *asyncWriter {
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	} else if cfg.Retries == 0 {
+		cfg.Retries = 2
+	}
+	if cfg.Backoff <= 0 {
+		cfg.Backoff = 100 * time.Millisecond
+	}
+
+	w := &asyncWriter{cfg: cfg, clock: clock, queue: make(chan *GibBuilder, cfg.QueueSize)}
+	w.wg.Add(cfg.Workers)
+	for i := 0; i < cfg.Workers; i++ {
+		go w.run()
+	}
+	return w
+}
+
+func (w *asyncWriter) run() {
+	defer w.wg.Done()
+	for b := range w.queue {
+		backoff := w.cfg.Backoff
+		err := b.Exec()
+		for attempt := 0; err != nil && attempt < w.cfg.Retries; attempt++ {
+			w.clock.Sleep(backoff)
+			backoff *= 2
+			err = b.Exec()
+		}
+		if err != nil && w.cfg.OnError != nil {
+			w.cfg.OnError(b.key, err)
+		}
+	}
+}
+
+// enqueue hands a builder to the workers without blocking.
+func (w *asyncWriter) enqueue(b *GibBuilder) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return redis.ErrClosed
+	}
+	select {
+	case w.queue <- b:
+		return nil
+	default:
+		return ErrAsyncQueueFull
+	}
+}
+
+// close stops accepting writes and waits for queued ones to flush.
+func (w *asyncWriter) close() {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+	w.wg.Wait()
+}
+
+// ExecAsync queues the write for a background worker and returns
+// immediately, so hot paths don't pay a Redis round trip when
+// best-effort caching is acceptable. Failed writes are retried and then
+// reported to Config.Async.OnError. Returns ErrAsyncQueueFull when the
+// queue is saturated and redis.ErrClosed after Client.Close. The
+// builder's context is detached from cancellation, so writes survive the
+// request that queued them. Client.Close flushes pending writes.
+//
+// Example:
+//
+//	if err := app.Gib(ctx, "user:123").Value(user).TTL(time.Hour).ExecAsync(); err != nil {
+//	    metrics.Inc("cache.async.dropped")
+//	}
+func (b *GibBuilder) ExecAsync() error {
+	if b.value == nil {
+		return ErrNilValue
+	}
+	if err := b.client.validate(b.key, b.value); err != nil {
+		return err
+	}
+
+	queued := *b
+	queued.ctx = context.WithoutCancel(b.ctx)
+	queued.detail = nil
+	w, err := b.client.asyncWriter()
+	if err != nil {
+		return err
+	}
+	return w.enqueue(&queued)
+}
+
+// asyncWriter starts the background writer on first use. Returns
+// redis.ErrClosed once the client is closed, including when Close ran
+// before any ExecAsync.
+func (c *Client) asyncWriter() (*asyncWriter, error) {
+	c.asyncOnce.Do(func() {
+		c.async = newAsyncWriter(c.asyncCfg, c.clock)
+	})
+	if c.async == nil {
+		return nil, redis.ErrClosed
+	}
+	return c.async, nil
+}
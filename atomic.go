@@ -0,0 +1,212 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AtomicBuilder composes a handful of operations into a single generated
+// Lua script, executed atomically by Redis. Covers invalidate-and-update
+// patterns without hand-writing Lua.
+type AtomicBuilder struct {
+	ctx    context.Context
+	client *Client
+	ops    []atomicOp
+	err    error
+}
+
+// atomicOp is a single step of an atomic script.
+type atomicOp struct {
+	cmd  string
+	keys []string
+	args []any
+}
+
+// atomicScripts caches compiled scripts by source so repeated shapes
+// reuse the same EVALSHA.
+var atomicScripts sync.Map
+
+// Atomic starts an atomic multi-key operation.
+//
+// Example:
+//
+//	results, err := app.Atomic(ctx).
+//	    Gib("user:123", user, 10*time.Minute).
+//	    Del("user:123:profile-page").
+//	    Incr("stats:user-updates").
+//	    Exec()
+func (c *Client) Atomic(ctx context.Context) *AtomicBuilder {
+	return &AtomicBuilder{
+		ctx:    ctx,
+		client: c,
+	}
+}
+
+// Gib queues storing value under key with an optional TTL.
+// Values are marshalled exactly like Gib.
+func (b *AtomicBuilder) Gib(key string, value any, ttl time.Duration) *AtomicBuilder {
+	if value == nil {
+		b.err = ErrNilValue
+		return b
+	}
+	if err := b.client.validate(key, value); err != nil {
+		b.err = err
+		return b
+	}
+	if err := b.client.guardWrite(key); err != nil {
+		b.err = err
+		return b
+	}
+	data, err := b.client.enc.marshal(value, nil)
+	if err != nil {
+		b.err = err
+		return b
+	}
+
+	ttl = b.client.ttl.apply(key, ttl)
+	op := atomicOp{cmd: "SET", keys: []string{key}, args: []any{data}}
+	if ttl > 0 {
+		op.cmd = "SETPX"
+		op.args = append(op.args, ttl.Milliseconds())
+	}
+	b.ops = append(b.ops, op)
+	return b
+}
+
+// Del queues deleting one or more keys.
+func (b *AtomicBuilder) Del(keys ...string) *AtomicBuilder {
+	if len(keys) > 0 {
+		b.ops = append(b.ops, atomicOp{cmd: "DEL", keys: keys})
+	}
+	return b
+}
+
+// Incr queues incrementing a counter by 1.
+func (b *AtomicBuilder) Incr(key string) *AtomicBuilder {
+	return b.IncrBy(key, 1)
+}
+
+// IncrBy queues incrementing a counter by n.
+func (b *AtomicBuilder) IncrBy(key string, n int64) *AtomicBuilder {
+	b.ops = append(b.ops, atomicOp{cmd: "INCRBY", keys: []string{key}, args: []any{n}})
+	return b
+}
+
+// Expire queues setting a TTL on an existing key.
+func (b *AtomicBuilder) Expire(key string, ttl time.Duration) *AtomicBuilder {
+	b.ops = append(b.ops, atomicOp{cmd: "PEXPIRE", keys: []string{key}, args: []any{ttl.Milliseconds()}})
+	return b
+}
+
+// Exec runs all queued operations atomically.
+// Returns one result per operation, in order: 1 for Gib, the number of
+// deleted keys for Del, the new value for Incr/IncrBy and 1/0 for Expire.
+//
+// Redis doesn't roll back on error: if a step fails (e.g. INCRBY on a
+// non-integer) the remaining steps are skipped but earlier ones stay applied.
+// When scripting is disabled the steps run in MULTI/EXEC instead, where a
+// failing step doesn't stop the ones after it.
+func (b *AtomicBuilder) Exec() ([]int64, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if len(b.ops) == 0 {
+		return nil, nil
+	}
+	if !b.client.scripting(b.ctx) {
+		return b.execMulti()
+	}
+
+	src, keys, args := b.compile()
+
+	var script *redis.Script
+	if cached, ok := atomicScripts.Load(src); ok {
+		script = cached.(*redis.Script)
+	} else {
+		script = redis.NewScript(src)
+		atomicScripts.Store(src, script)
+	}
+
+	return script.Run(b.ctx, b.client.rdb, keys, args...).Int64Slice()
+}
+
+// execMulti runs the queued operations in MULTI/EXEC.
+func (b *AtomicBuilder) execMulti() ([]int64, error) {
+	cmds := make([]redis.Cmder, len(b.ops))
+	_, err := b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		for i, op := range b.ops {
+			switch op.cmd {
+			case "SET":
+				cmds[i] = pipe.Set(b.ctx, op.keys[0], op.args[0], 0)
+			case "SETPX":
+				cmds[i] = pipe.Set(b.ctx, op.keys[0], op.args[0], time.Duration(op.args[1].(int64))*time.Millisecond)
+			case "DEL":
+				cmds[i] = pipe.Del(b.ctx, op.keys...)
+			case "INCRBY":
+				cmds[i] = pipe.IncrBy(b.ctx, op.keys[0], op.args[0].(int64))
+			case "PEXPIRE":
+				cmds[i] = pipe.PExpire(b.ctx, op.keys[0], time.Duration(op.args[0].(int64))*time.Millisecond)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]int64, len(cmds))
+	for i, cmd := range cmds {
+		switch c := cmd.(type) {
+		case *redis.StatusCmd:
+			results[i] = 1
+		case *redis.IntCmd:
+			results[i] = c.Val()
+		case *redis.BoolCmd:
+			if c.Val() {
+				results[i] = 1
+			}
+		}
+	}
+	return results, nil
+}
+
+// compile generates the Lua source plus flattened KEYS and ARGV.
+// The source only depends on the shape of the operations, not their values.
+func (b *AtomicBuilder) compile() (string, []string, []any) {
+	var (
+		src  strings.Builder
+		keys []string
+		args []any
+	)
+
+	src.WriteString("local r = {}\n")
+	for i, op := range b.ops {
+		k := len(keys) + 1
+		a := len(args) + 1
+		keys = append(keys, op.keys...)
+		args = append(args, op.args...)
+
+		switch op.cmd {
+		case "SET":
+			fmt.Fprintf(&src, "redis.call('SET', KEYS[%d], ARGV[%d])\nr[%d] = 1\n", k, a, i+1)
+		case "SETPX":
+			fmt.Fprintf(&src, "redis.call('SET', KEYS[%d], ARGV[%d], 'PX', ARGV[%d])\nr[%d] = 1\n", k, a, a+1, i+1)
+		case "DEL":
+			refs := make([]string, len(op.keys))
+			for j := range op.keys {
+				refs[j] = fmt.Sprintf("KEYS[%d]", k+j)
+			}
+			fmt.Fprintf(&src, "r[%d] = redis.call('DEL', %s)\n", i+1, strings.Join(refs, ", "))
+		case "INCRBY", "PEXPIRE":
+			fmt.Fprintf(&src, "r[%d] = redis.call('%s', KEYS[%d], ARGV[%d])\n", i+1, op.cmd, k, a)
+		}
+	}
+	src.WriteString("return r\n")
+
+	return src.String(), keys, args
+}
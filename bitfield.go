@@ -0,0 +1,130 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// BitfieldType is an integer type for BITFIELD, e.g. "u8" or "i16".
+// Build one with U or I.
+type BitfieldType string
+
+// U returns the unsigned integer type of the given width (1 to 63 bits).
+func U(bits int) BitfieldType {
+	return BitfieldType("u" + strconv.Itoa(bits))
+}
+
+// I returns the signed integer type of the given width (1 to 64 bits).
+func I(bits int) BitfieldType {
+	return BitfieldType("i" + strconv.Itoa(bits))
+}
+
+// Offset returns the bit offset of the i-th value of this type when
+// values are packed back to back, for counter arrays.
+func (t BitfieldType) Offset(i int64) int64 {
+	bits, _ := strconv.ParseInt(string(t[1:]), 10, 64)
+	return i * bits
+}
+
+// BitfieldOverflow controls what IncrBy and Set do past the type range.
+type BitfieldOverflow string
+
+const (
+	// OverflowWrap wraps around, the Redis default.
+	OverflowWrap BitfieldOverflow = "WRAP"
+	// OverflowSat saturates at the minimum or maximum value.
+	OverflowSat BitfieldOverflow = "SAT"
+	// OverflowFail skips the operation, see ErrBitfieldOverflow.
+	OverflowFail BitfieldOverflow = "FAIL"
+)
+
+// BitfieldBuilder queues BITFIELD operations on a key and runs them in
+// one atomic command.
+type BitfieldBuilder struct {
+	ctx    context.Context
+	client *Client
+	key    string
+	args   []any
+	ops    int
+}
+
+// Bitfield starts a BITFIELD operation, for packing thousands of small
+// counters into a single key.
+//
+// Example:
+//
+//	// 1000 saturating 8-bit counters in 1000 bytes
+//	hits := gibrun.U(8)
+//	vals, err := app.Bitfield(ctx, "hits:packed").
+//	    Overflow(gibrun.OverflowSat).
+//	    IncrBy(hits, hits.Offset(42), 1).
+//	    Get(hits, hits.Offset(7)).
+//	    Exec()
+func (c *Client) Bitfield(ctx context.Context, key string) *BitfieldBuilder {
+	return &BitfieldBuilder{
+		ctx:    ctx,
+		client: c,
+		key:    key,
+	}
+}
+
+// Get queues reading the value of type t at bit offset.
+func (b *BitfieldBuilder) Get(t BitfieldType, offset int64) *BitfieldBuilder {
+	b.args = append(b.args, "GET", string(t), offset)
+	b.ops++
+	return b
+}
+
+// Set queues writing v at bit offset; its result is the previous value.
+func (b *BitfieldBuilder) Set(t BitfieldType, offset, v int64) *BitfieldBuilder {
+	b.args = append(b.args, "SET", string(t), offset, v)
+	b.ops++
+	return b
+}
+
+// IncrBy queues adding n at bit offset; its result is the new value.
+func (b *BitfieldBuilder) IncrBy(t BitfieldType, offset, n int64) *BitfieldBuilder {
+	b.args = append(b.args, "INCRBY", string(t), offset, n)
+	b.ops++
+	return b
+}
+
+// Overflow sets the overflow behavior for the Set and IncrBy queued
+// after it.
+func (b *BitfieldBuilder) Overflow(o BitfieldOverflow) *BitfieldBuilder {
+	b.args = append(b.args, "OVERFLOW", string(o))
+	return b
+}
+
+// Exec runs the queued operations and returns one value per Get, Set
+// and IncrBy, in order. If an operation was skipped under OverflowFail,
+// its value is 0 and the error wraps ErrBitfieldOverflow.
+func (b *BitfieldBuilder) Exec() ([]int64, error) {
+	if b.ops == 0 {
+		return nil, nil
+	}
+
+	args := append([]any{"BITFIELD", b.key}, b.args...)
+	replies, err := b.client.rdb.Do(b.ctx, args...).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]int64, len(replies))
+	var failed []int
+	for i, r := range replies {
+		switch v := r.(type) {
+		case int64:
+			vals[i] = v
+		case nil:
+			failed = append(failed, i)
+		default:
+			return nil, fmt.Errorf("gibrun: unexpected BITFIELD reply %T", r)
+		}
+	}
+	if len(failed) > 0 {
+		return vals, fmt.Errorf("%w: operations %v", ErrBitfieldOverflow, failed)
+	}
+	return vals, nil
+}
@@ -0,0 +1,321 @@
+package gibrun
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BloomBuilder provides a fluent API for Bloom filters: memory-cheap
+// "have we seen this item" checks that may report false positives but
+// never false negatives. Uses RedisBloom (BF.*) when the module is
+// loaded and a plain Redis bitmap otherwise, so every client talking to
+// the same server must agree on the module being there. Items are
+// encoded like Koalisi members.
+type BloomBuilder struct {
+	ctx       context.Context
+	client    *Client
+	key       string
+	capacity  int64
+	errorRate float64
+}
+
+// Bloom starts a Bloom filter operation.
+//
+// Example:
+//
+//	seen := app.Bloom(ctx, "seen:urls").Capacity(10_000_000).ErrorRate(0.001)
+//	added, err := seen.Add(url)
+//	if !added[0] {
+//	    // probably crawled already
+//	}
+func (c *Client) Bloom(ctx context.Context, key string) *BloomBuilder {
+	return &BloomBuilder{
+		ctx:       ctx,
+		client:    c,
+		key:       key,
+		capacity:  100000,
+		errorRate: 0.01,
+	}
+}
+
+// Capacity sets the expected number of items. Default is 100000. Like
+// ErrorRate, it only applies when the filter is created by the first
+// write; later values are ignored.
+func (b *BloomBuilder) Capacity(n int64) *BloomBuilder {
+	b.capacity = n
+	return b
+}
+
+// ErrorRate sets the target false positive rate. Default is 0.01.
+func (b *BloomBuilder) ErrorRate(p float64) *BloomBuilder {
+	b.errorRate = p
+	return b
+}
+
+// Add records items and reports, per item, whether it was new. A false
+// may be a false positive.
+func (b *BloomBuilder) Add(items ...any) ([]bool, error) {
+	vals, err := b.client.enc.members(items, nil)
+	if err != nil {
+		return nil, err
+	}
+	module, err := b.client.hasModule(b.ctx, "bf")
+	if err != nil {
+		return nil, err
+	}
+	if !module {
+		return b.bitmapAdd(vals)
+	}
+
+	args := []any{"BF.INSERT", b.key, "CAPACITY", b.capacity, "ERROR", b.errorRate, "ITEMS"}
+	res, err := b.client.rdb.Do(b.ctx, append(args, vals...)...).BoolSlice()
+	return res, moduleError(err, "bf")
+}
+
+// Exists reports whether item was probably added.
+func (b *BloomBuilder) Exists(item any) (bool, error) {
+	v, err := b.client.enc.member(item, nil)
+	if err != nil {
+		return false, err
+	}
+	module, err := b.client.hasModule(b.ctx, "bf")
+	if err != nil {
+		return false, err
+	}
+	if !module {
+		return b.bitmapExists(v)
+	}
+	res, err := b.client.rdb.Do(b.ctx, "BF.EXISTS", b.key, v).Bool()
+	return res, moduleError(err, "bf")
+}
+
+// bitmapParams returns the bitmap size and hash count of the fallback
+// filter, pinning them in "<key>:bloom" on first use so later callers
+// with other settings still probe the same bits. Returns a ConfigError
+// unless capacity is positive and 0 < error rate < 1.
+func (b *BloomBuilder) bitmapParams() (m, k uint64, err error) {
+	if b.capacity <= 0 {
+		return 0, 0, &ConfigError{Field: "Capacity", Problem: "must be positive"}
+	}
+	if !(b.errorRate > 0 && b.errorRate < 1) {
+		return 0, 0, &ConfigError{Field: "ErrorRate", Problem: "must be between 0 and 1, exclusive"}
+	}
+	n := float64(b.capacity)
+	bits := math.Ceil(-n * math.Log(b.errorRate) / (math.Ln2 * math.Ln2))
+	m = uint64(min(max(bits, 64), math.MaxUint32))
+	k = uint64(max(math.Round(float64(m)/n*math.Ln2), 1))
+
+	params := b.key + ":bloom"
+	var get *redis.SliceCmd
+	_, err = b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSetNX(b.ctx, params, "m", m)
+		pipe.HSetNX(b.ctx, params, "k", k)
+		get = pipe.HMGet(b.ctx, params, "m", "k")
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	vals := get.Val()
+	ms, _ := vals[0].(string)
+	ks, _ := vals[1].(string)
+	if m, err = strconv.ParseUint(ms, 10, 64); err != nil {
+		return 0, 0, err
+	}
+	if k, err = strconv.ParseUint(ks, 10, 64); err != nil {
+		return 0, 0, err
+	}
+	return m, k, nil
+}
+
+// bitmapAdd is Add on the bitmap fallback.
+func (b *BloomBuilder) bitmapAdd(items []any) ([]bool, error) {
+	m, k, err := b.bitmapParams()
+	if err != nil {
+		return nil, err
+	}
+	cmds := make([][]*redis.IntCmd, len(items))
+	_, err = b.client.rdb.Pipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		for i, item := range items {
+			for _, offset := range bloomOffsets(item.(string), m, k) {
+				cmds[i] = append(cmds[i], pipe.SetBit(b.ctx, b.key, int64(offset), 1))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	added := make([]bool, len(items))
+	for i := range items {
+		for _, cmd := range cmds[i] {
+			if cmd.Val() == 0 {
+				added[i] = true
+			}
+		}
+	}
+	return added, nil
+}
+
+// bitmapExists is Exists on the bitmap fallback.
+func (b *BloomBuilder) bitmapExists(item string) (bool, error) {
+	m, k, err := b.bitmapParams()
+	if err != nil {
+		return false, err
+	}
+	var cmds []*redis.IntCmd
+	_, err = b.client.rdb.Pipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		for _, offset := range bloomOffsets(item, m, k) {
+			cmds = append(cmds, pipe.GetBit(b.ctx, b.key, int64(offset)))
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, cmd := range cmds {
+		if cmd.Val() == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// bloomOffsets returns the k bit offsets of item in an m-bit filter,
+// derived from one 128-bit hash by double hashing.
+func bloomOffsets(item string, m, k uint64) []uint64 {
+	h := fnv.New128a()
+	h.Write([]byte(item))
+	sum := h.Sum(nil)
+	h1 := binary.BigEndian.Uint64(sum[:8])
+	h2 := binary.BigEndian.Uint64(sum[8:]) | 1
+
+	offsets := make([]uint64, k)
+	for i := range offsets {
+		offsets[i] = (h1 + uint64(i)*h2) % m
+	}
+	return offsets
+}
+
+// CuckooBuilder provides a fluent API for cuckoo filters: like Bloom,
+// but items can be deleted. Uses RedisBloom (CF.*) when the module is
+// loaded and a set of 64-bit item fingerprints otherwise, which uses
+// more memory but keeps the same behavior.
+type CuckooBuilder struct {
+	ctx      context.Context
+	client   *Client
+	key      string
+	capacity int64
+}
+
+// Cuckoo starts a cuckoo filter operation.
+//
+// Example:
+//
+//	active := app.Cuckoo(ctx, "sessions:active")
+//	active.Add(sessionID)
+//	ok, err := active.Exists(sessionID)
+//	active.Delete(sessionID)
+func (c *Client) Cuckoo(ctx context.Context, key string) *CuckooBuilder {
+	return &CuckooBuilder{
+		ctx:      ctx,
+		client:   c,
+		key:      key,
+		capacity: 100000,
+	}
+}
+
+// Capacity sets the expected number of items when the module creates the
+// filter. Default is 100000.
+func (b *CuckooBuilder) Capacity(n int64) *CuckooBuilder {
+	b.capacity = n
+	return b
+}
+
+// Add records item and reports whether it was new. A false may be a
+// false positive.
+func (b *CuckooBuilder) Add(item any) (bool, error) {
+	v, err := b.client.enc.member(item, nil)
+	if err != nil {
+		return false, err
+	}
+	module, err := b.client.hasModule(b.ctx, "bf")
+	if err != nil {
+		return false, err
+	}
+	if !module {
+		n, err := b.client.rdb.SAdd(b.ctx, b.key, fingerprint(v)).Result()
+		return n == 1, err
+	}
+
+	res, err := b.client.rdb.Do(b.ctx, "CF.INSERTNX", b.key, "CAPACITY", b.capacity, "ITEMS", v).Int64Slice()
+	if err != nil {
+		return false, moduleError(err, "bf")
+	}
+	return len(res) == 1 && res[0] == 1, nil
+}
+
+// Exists reports whether item was probably added and not deleted.
+func (b *CuckooBuilder) Exists(item any) (bool, error) {
+	v, err := b.client.enc.member(item, nil)
+	if err != nil {
+		return false, err
+	}
+	module, err := b.client.hasModule(b.ctx, "bf")
+	if err != nil {
+		return false, err
+	}
+	if !module {
+		return b.client.rdb.SIsMember(b.ctx, b.key, fingerprint(v)).Result()
+	}
+	res, err := b.client.rdb.Do(b.ctx, "CF.EXISTS", b.key, v).Bool()
+	return res, moduleError(err, "bf")
+}
+
+// Delete removes item and reports whether it was found. Only delete
+// items that were added, or another item sharing its fingerprint may
+// be removed instead.
+func (b *CuckooBuilder) Delete(item any) (bool, error) {
+	v, err := b.client.enc.member(item, nil)
+	if err != nil {
+		return false, err
+	}
+	module, err := b.client.hasModule(b.ctx, "bf")
+	if err != nil {
+		return false, err
+	}
+	if !module {
+		n, err := b.client.rdb.SRem(b.ctx, b.key, fingerprint(v)).Result()
+		return n == 1, err
+	}
+	res, err := b.client.rdb.Do(b.ctx, "CF.DEL", b.key, v).Bool()
+	if err != nil && strings.Contains(err.Error(), "Not found") {
+		return false, nil
+	}
+	return res, moduleError(err, "bf")
+}
+
+// fingerprint returns the 64-bit fingerprint of item used by the cuckoo
+// fallback.
+func fingerprint(item string) string {
+	h := fnv.New64a()
+	h.Write([]byte(item))
+	return strconv.FormatUint(h.Sum64(), 36)
+}
+
+// hasModule reports whether the probed server has the module loaded.
+// Servers hiding MODULE LIST count as not having it.
+func (c *Client) hasModule(ctx context.Context, name string) (bool, error) {
+	info, err := c.serverInfo(ctx)
+	if err != nil {
+		return false, err
+	}
+	return info.HasModule(name), nil
+}
@@ -0,0 +1,122 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CacheOptions configures a typed Cache.
+type CacheOptions[T any] struct {
+	// Prefix is prepended to every ID to build the key (e.g., "user:").
+	Prefix string
+
+	// TTL applied to loaded values written back to the cache.
+	// Client TTL policies still apply.
+	TTL time.Duration
+
+	// LoadMany loads the given missing IDs from the backing store in a
+	// single call. IDs absent from the returned map are treated as not
+	// found and are not cached.
+	LoadMany func(ctx context.Context, ids []string) (map[string]T, error)
+}
+
+// Cache is a typed read-through view over keys sharing a prefix.
+type Cache[T any] struct {
+	client *Client
+	opts   CacheOptions[T]
+}
+
+// NewCache creates a typed cache on top of the client.
+//
+// Example:
+//
+//	users := gibrun.NewCache(app, gibrun.CacheOptions[User]{
+//	    Prefix:   "user:",
+//	    TTL:      time.Hour,
+//	    LoadMany: db.UsersByID,
+//	})
+func NewCache[T any](client *Client, opts CacheOptions[T]) *Cache[T] {
+	return &Cache[T]{client: client, opts: opts}
+}
+
+// Key returns the Redis key for an ID.
+func (c *Cache[T]) Key(id string) string {
+	return c.opts.Prefix + id
+}
+
+// GetMany fetches all IDs with one MGET, loads every miss with a single
+// LoadMany call, writes the loaded values back in one pipelined batch and
+// returns the combined map. IDs the loader doesn't return are absent from
+// the result. This is the N+1 killer for list endpoints.
+//
+// Example:
+//
+//	byID, err := users.GetMany(ctx, []string{"1", "2", "3"})
+func (c *Cache[T]) GetMany(ctx context.Context, ids []string) (map[string]T, error) {
+	result := make(map[string]T, len(ids))
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = c.Key(id)
+	}
+
+	vals, err := c.client.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for i, raw := range vals {
+		s, ok := raw.(string)
+		if !ok {
+			missing = append(missing, ids[i])
+			continue
+		}
+
+		data := []byte(s)
+		if isNegative(data) {
+			// Confirmed miss, don't ask the loader again
+			continue
+		}
+		if envelopeKind(data) == envelopeChunked {
+			if data, err = c.client.reassemble(ctx, keys[i], data); err != nil {
+				return nil, err
+			}
+		}
+
+		var v T
+		if err := c.client.enc.unmarshal(data, &v, nil); err != nil {
+			return nil, fmt.Errorf("gibrun: decode %s: %w", keys[i], err)
+		}
+		result[ids[i]] = v
+	}
+
+	if len(missing) == 0 || c.opts.LoadMany == nil {
+		return result, nil
+	}
+
+	loaded, err := c.opts.LoadMany(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := c.client.MGib(ctx)
+	for _, id := range missing {
+		v, ok := loaded[id]
+		if !ok {
+			continue
+		}
+		result[id] = v
+		batch.Add(c.Key(id), v, c.opts.TTL)
+	}
+	if batch.Len() > 0 {
+		if err := batch.Exec(); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,39 @@
+package gibrun
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+// checksumLen is the size of the CRC32 stored after the envelope header.
+const checksumLen = 4
+
+// addChecksum wraps data in a checksum envelope:
+// envelope header | CRC32 (IEEE, big-endian) | payload.
+func addChecksum(data []byte) []byte {
+	out := make([]byte, 0, envelopeHeaderLen+checksumLen+len(data))
+	out = append(out, envelopeMagic...)
+	out = append(out, envelopeChecksum)
+	out = binary.BigEndian.AppendUint32(out, crc32.ChecksumIEEE(data))
+	return append(out, data...)
+}
+
+// verifyChecksum strips a checksum envelope, returning ErrCorruptValue if
+// the payload doesn't match. Data without the envelope is returned as is,
+// so values written before checksums were enabled still read.
+func verifyChecksum(data []byte) ([]byte, error) {
+	if envelopeKind(data) != envelopeChecksum {
+		return data, nil
+	}
+	if len(data) < envelopeHeaderLen+checksumLen {
+		return nil, fmt.Errorf("%w: truncated checksum envelope", ErrCorruptValue)
+	}
+	want := binary.BigEndian.Uint32(data[envelopeHeaderLen:])
+	payload := data[envelopeHeaderLen+checksumLen:]
+	if got := crc32.ChecksumIEEE(payload); got != want {
+		return nil, fmt.Errorf("%w: checksum mismatch (stored %08x, computed %08x, %d bytes)",
+			ErrCorruptValue, want, got, len(payload))
+	}
+	return payload, nil
+}
@@ -0,0 +1,197 @@
+package gibrun
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Chunked values are stored as a manifest at the key itself plus the
+// payload split across "key:chunk:N" keys. Manifest layout:
+// envelope header | chunk count | ":" | total length | "\n".
+
+// chunkKey returns the key holding chunk i of key.
+func chunkKey(key string, i int) string {
+	return key + ":chunk:" + strconv.Itoa(i)
+}
+
+// execChunked stores data split into ChunkSize pieces, atomically with
+// the manifest so readers never see a half-written value. Chunks of a
+// previous, longer value are dropped in the same MULTI.
+func (b *GibBuilder) execChunked(data []byte, ttl time.Duration) error {
+	size := b.client.chunkSize
+	count := (len(data) + size - 1) / size
+
+	manifest := make([]byte, 0, envelopeHeaderLen+24)
+	manifest = append(manifest, envelopeMagic...)
+	manifest = append(manifest, envelopeChunked)
+	manifest = append(manifest, fmt.Sprintf("%d:%d\n", count, len(data))...)
+
+	return b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		stale, err := storedChunks(b.ctx, tx, []string{b.key}, count)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			for i := 0; i < count; i++ {
+				end := (i + 1) * size
+				if end > len(data) {
+					end = len(data)
+				}
+				pipe.Set(b.ctx, chunkKey(b.key, i), data[i*size:end], ttl)
+			}
+			pipe.Set(b.ctx, b.key, manifest, ttl)
+			if len(stale) > 0 {
+				pipe.Del(b.ctx, stale...)
+			}
+			return nil
+		})
+		return err
+	}, b.key)
+}
+
+// setOverChunks is set for clients with chunking enabled, where the
+// value being replaced may be chunked: its chunks are dropped in the
+// same MULTI. Clients that never chunk skip the extra round trip.
+func (b *GibBuilder) setOverChunks(data []byte, ttl time.Duration) error {
+	return b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		stale, err := storedChunks(b.ctx, tx, []string{b.key}, 0)
+		if err != nil {
+			return err
+		}
+		var exists *redis.IntCmd
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			exists = pipe.Exists(b.ctx, b.key)
+			pipe.Set(b.ctx, b.key, data, ttl)
+			if len(stale) > 0 {
+				pipe.Del(b.ctx, stale...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if b.detail != nil {
+			existed := exists.Val() > 0
+			b.detail.Created, b.detail.Replaced = !existed, existed
+		}
+		return nil
+	}, b.key)
+}
+
+// manifestPeek is enough leading bytes to hold any chunk manifest.
+const manifestPeek = 64
+
+// manifestPrefix is the leading bytes of every chunk manifest.
+func manifestPrefix() string {
+	return string(envelopeMagic) + string(envelopeChunked)
+}
+
+// delScript deletes keys and the chunks behind any chunk manifests among
+// them in one round trip. Chunk keys are derived from their manifest key
+// as on the client, so they are not declared; Client talks to a single
+// node, where that is safe.
+//
+// KEYS = keys to delete
+// ARGV[1] = manifest prefix
+var delScript = redis.NewScript(`
+local prefix = ARGV[1]
+local deleted = 0
+for _, key in ipairs(KEYS) do
+  if redis.call('TYPE', key).ok == 'string' then
+    local head = redis.call('GETRANGE', key, 0, 63)
+    if string.sub(head, 1, #prefix) == prefix then
+      local count = tonumber(string.match(head, '^(%d+):', #prefix + 1)) or 0
+      for i = 0, count - 1 do
+        redis.call('DEL', key .. ':chunk:' .. i)
+      end
+    end
+  end
+  deleted = deleted + redis.call('DEL', key)
+end
+return deleted
+`)
+
+// storedChunks lists the chunk keys, from index from on, behind the
+// manifests stored at keys. Only the first bytes of each value are read;
+// keys holding other values or other types are skipped.
+func storedChunks(ctx context.Context, rdb redis.Cmdable, keys []string, from int) ([]string, error) {
+	heads := make([]*redis.StringCmd, len(keys))
+	// Per-command errors are checked below
+	rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			heads[i] = pipe.GetRange(ctx, key, 0, manifestPeek-1)
+		}
+		return nil
+	})
+
+	var chunks []string
+	for i, head := range heads {
+		data, err := head.Bytes()
+		if err != nil {
+			if isWrongType(err) {
+				continue
+			}
+			return nil, err
+		}
+		if envelopeKind(data) != envelopeChunked {
+			continue
+		}
+		count, _, err := parseManifest(keys[i], data)
+		if err != nil {
+			// Nothing reliable to drop
+			continue
+		}
+		for n := from; n < count; n++ {
+			chunks = append(chunks, chunkKey(keys[i], n))
+		}
+	}
+	return chunks, nil
+}
+
+// parseManifest returns the chunk count and total length of a manifest.
+func parseManifest(key string, manifest []byte) (count, total int, err error) {
+	line := bytes.TrimSuffix(manifest[envelopeHeaderLen:], []byte("\n"))
+	countStr, totalStr, ok := bytes.Cut(line, []byte(":"))
+	count, err1 := strconv.Atoi(string(countStr))
+	total, err2 := strconv.Atoi(string(totalStr))
+	if !ok || err1 != nil || err2 != nil {
+		return 0, 0, fmt.Errorf("%w: bad chunk manifest for %s", ErrCorruptValue, key)
+	}
+	return count, total, nil
+}
+
+// reassemble fetches and joins the chunks described by a manifest.
+// Missing chunks (e.g. evicted) are reported as ErrCorruptValue.
+func (c *Client) reassemble(ctx context.Context, key string, manifest []byte) ([]byte, error) {
+	count, total, err := parseManifest(key, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = chunkKey(key, i)
+	}
+	parts, err := c.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, 0, total)
+	for i, part := range parts {
+		s, ok := part.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: chunk %d of %s is missing", ErrCorruptValue, i, key)
+		}
+		data = append(data, s...)
+	}
+	if len(data) != total {
+		return nil, fmt.Errorf("%w: %s reassembled to %d bytes, expected %d", ErrCorruptValue, key, len(data), total)
+	}
+	return data, nil
+}
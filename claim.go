@@ -0,0 +1,171 @@
+package gibrun
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// inflightSuffix names the in-progress set paired with a pending set.
+const inflightSuffix = ":inflight"
+
+// claimScript returns expired reservations to the pending set, then moves
+// up to n due members into the in-progress set with a deadline.
+//
+// KEYS[1] = pending set, KEYS[2] = in-progress set
+// ARGV[1] = now in milliseconds, ARGV[2] = n, ARGV[3] = visibility in milliseconds
+var claimScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local expired = redis.call('ZRANGEBYSCORE', KEYS[2], '-inf', now)
+for _, m in ipairs(expired) do
+  redis.call('ZREM', KEYS[2], m)
+  redis.call('ZADD', KEYS[1], now, m)
+end
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', now, 'LIMIT', 0, tonumber(ARGV[2]))
+local deadline = now + tonumber(ARGV[3])
+for _, m in ipairs(due) do
+  redis.call('ZREM', KEYS[1], m)
+  redis.call('ZADD', KEYS[2], deadline, m)
+end
+return due
+`)
+
+// nackScript moves a reserved member back to the pending set, only if it
+// is still reserved (an expired reservation may already be reclaimed).
+//
+// KEYS[1] = pending set, KEYS[2] = in-progress set
+// ARGV[1] = member, ARGV[2] = due time in milliseconds
+var nackScript = redis.NewScript(`
+if redis.call('ZREM', KEYS[2], ARGV[1]) == 1 then
+  redis.call('ZADD', KEYS[1], ARGV[2], ARGV[1])
+  return 1
+end
+return 0
+`)
+
+// Schedule adds member to the pending sorted set, due at the given time.
+// Rescheduling an existing member moves its due time.
+// In a cluster, wrap the set name in a hash tag (e.g. "{jobs}") so the
+// pending and in-progress sets share a slot.
+//
+// Example:
+//
+//	err := app.Schedule(ctx, "{jobs}", "report:42", time.Now().Add(time.Hour))
+func (c *Client) Schedule(ctx context.Context, set, member string, at time.Time) error {
+	return c.rdb.ZAdd(ctx, set, redis.Z{Score: float64(at.UnixMilli()), Member: member}).Err()
+}
+
+// Claim atomically reserves up to n due members of the pending set,
+// moving them to set + ":inflight" with a deadline of now + visibility.
+// Reservations that passed their deadline without Ack or Nack are
+// returned to the pending set first, so crashed workers don't lose work.
+//
+// Example:
+//
+//	ids, err := app.Claim(ctx, "{jobs}", 10, 30*time.Second)
+//	for _, id := range ids {
+//	    if err := process(id); err != nil {
+//	        app.Nack(ctx, "{jobs}", id, time.Minute)
+//	        continue
+//	    }
+//	    app.Ack(ctx, "{jobs}", id)
+//	}
+func (c *Client) Claim(ctx context.Context, set string, n int, visibility time.Duration) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	now := c.clock.Now().UnixMilli()
+	if !c.scripting(ctx) {
+		return c.claimFallback(ctx, set, n, now, visibility.Milliseconds())
+	}
+	return claimScript.Run(ctx, c.rdb, []string{set, set + inflightSuffix},
+		now, n, visibility.Milliseconds()).StringSlice()
+}
+
+// claimFallback mirrors claimScript with WATCH/MULTI.
+func (c *Client) claimFallback(ctx context.Context, set string, n int, now, visibility int64) ([]string, error) {
+	inflight := set + inflightSuffix
+	var due []string
+	err := c.watchRetry(ctx, func(tx *redis.Tx) error {
+		nowStr := strconv.FormatInt(now, 10)
+		expired, err := tx.ZRangeByScore(ctx, inflight, &redis.ZRangeBy{Min: "-inf", Max: nowStr}).Result()
+		if err != nil {
+			return err
+		}
+		pending, err := tx.ZRangeByScore(ctx, set, &redis.ZRangeBy{Min: "-inf", Max: nowStr, Count: int64(n)}).Result()
+		if err != nil {
+			return err
+		}
+
+		// Reclaimed members are due at now, so they sort after the rest
+		due = append(pending, expired...)
+		if len(due) > n {
+			due = due[:n]
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, m := range expired {
+				pipe.ZRem(ctx, inflight, m)
+				pipe.ZAdd(ctx, set, redis.Z{Score: float64(now), Member: m})
+			}
+			for _, m := range due {
+				pipe.ZRem(ctx, set, m)
+				pipe.ZAdd(ctx, inflight, redis.Z{Score: float64(now + visibility), Member: m})
+			}
+			return nil
+		})
+		return err
+	}, set, inflight)
+	if err != nil {
+		return nil, err
+	}
+	return due, nil
+}
+
+// Ack completes reserved members, removing them for good.
+func (c *Client) Ack(ctx context.Context, set string, members ...string) error {
+	if len(members) == 0 {
+		return nil
+	}
+	args := make([]any, len(members))
+	for i, m := range members {
+		args[i] = m
+	}
+	return c.rdb.ZRem(ctx, set+inflightSuffix, args...).Err()
+}
+
+// Nack releases a reserved member back to the pending set, due again
+// after delay. Returns false if the member was no longer reserved.
+func (c *Client) Nack(ctx context.Context, set, member string, delay time.Duration) (bool, error) {
+	at := c.clock.Now().Add(delay).UnixMilli()
+	if !c.scripting(ctx) {
+		return c.nackFallback(ctx, set, member, at)
+	}
+	n, err := nackScript.Run(ctx, c.rdb, []string{set, set + inflightSuffix}, member, at).Int()
+	return n == 1, err
+}
+
+// nackFallback mirrors nackScript with WATCH/MULTI.
+func (c *Client) nackFallback(ctx context.Context, set, member string, at int64) (bool, error) {
+	inflight := set + inflightSuffix
+	released := false
+	err := c.watchRetry(ctx, func(tx *redis.Tx) error {
+		released = false
+		if err := tx.ZScore(ctx, inflight, member).Err(); err != nil {
+			if err == redis.Nil {
+				return nil
+			}
+			return err
+		}
+		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZRem(ctx, inflight, member)
+			pipe.ZAdd(ctx, set, redis.Z{Score: float64(at), Member: member})
+			return nil
+		})
+		released = err == nil
+		return err
+	}, inflight)
+	return released, err
+}
@@ -0,0 +1,101 @@
+package gibrun
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock is the time source for client-side time math: ExpireAt, rate
+// limit windows, Claim/Schedule deadlines, polls, progress staleness and
+// the delays between scan batches and async retries. Inject a
+// ManualClock in tests to verify time-dependent behavior without real
+// sleeps. Expiry inside Redis itself still follows the server clock.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+}
+
+// systemClock is the default Clock backed by the time package.
+type systemClock struct{}
+
+func (systemClock) Now() time.Time        { return time.Now() }
+func (systemClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+// SystemClock returns the real wall clock, the default for clients.
+func SystemClock() Clock {
+	return systemClock{}
+}
+
+// sleepCtx sleeps d on clock, returning early when ctx is canceled.
+func sleepCtx(ctx context.Context, clock Clock, d time.Duration) {
+	if ctx.Err() != nil {
+		return
+	}
+	if _, ok := clock.(systemClock); ok {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+		}
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		clock.Sleep(d)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+	}
+}
+
+// ManualClock is a Clock that only moves when told to. Sleep advances
+// it instead of blocking, so code that waits finishes instantly.
+//
+// Example:
+//
+//	clock := gibrun.NewManualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+//	app := gibrun.New(gibrun.Config{Addr: "localhost:6379", Clock: clock})
+//	limiter := gibrun.NewRateLimiter(app, gibrun.RateLimitConfig{Rate: 1, Window: time.Minute})
+//	limiter.Allow(ctx, "user:1") // allowed
+//	limiter.Allow(ctx, "user:1") // denied
+//	clock.Advance(time.Minute)
+//	limiter.Allow(ctx, "user:1") // allowed again, new window
+type ManualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewManualClock creates a manual clock set to start.
+func NewManualClock(start time.Time) *ManualClock {
+	return &ManualClock{now: start}
+}
+
+// Now returns the current manual time.
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep advances the clock by d and returns immediately.
+func (c *ManualClock) Sleep(d time.Duration) {
+	c.Advance(d)
+}
+
+// Advance moves the clock forward by d.
+func (c *ManualClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the clock to t.
+func (c *ManualClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+}
@@ -2,6 +2,7 @@ package gibrun
 
 import (
 	"context"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -29,12 +30,38 @@ type ClusterConfig struct {
 
 	// RouteRandomly routes commands randomly across nodes.
 	RouteRandomly bool
+
+	// Codec serializes non-string values. Default is JSONCodec.
+	Codec Codec
+
+	// Encryption enables AES-GCM encryption of stored values.
+	// Gib encrypts and Run decrypts transparently.
+	Encryption KeyProvider
+
+	// Clock is the time source for client-side time math.
+	// Default is SystemClock.
+	Clock Clock
+
+	// Checksum stores a CRC32 of every value written by Gib and verifies
+	// it on Run, returning ErrCorruptValue on mismatch instead of failing
+	// later with an unmarshal error. Values with checksums are verified
+	// even when this is off.
+	Checksum bool
 }
 
 // ClusterClient is the gibrun client for Redis Cluster mode.
 // Provides the same Gib/Run/Sprint API as the single-node Client.
 type ClusterClient struct {
-	rdb *redis.ClusterClient
+	rdb   *redis.ClusterClient
+	clock Clock
+	enc   encoding
+
+	// replicas serves FromReplica reads: the same cluster with reads
+	// spread over replicas. It is connected on the first FromReplica
+	// read, see replicaClient.
+	replicaOpts  redis.ClusterOptions
+	replicasOnce sync.Once
+	replicas     *redis.ClusterClient
 }
 
 // NewCluster creates a new gibrun ClusterClient for Redis Cluster mode.
@@ -51,20 +78,46 @@ func NewCluster(cfg ClusterConfig) *ClusterClient {
 		maxRedirects = 3
 	}
 
-	rdb := redis.NewClusterClient(&redis.ClusterOptions{
+	opts := &redis.ClusterOptions{
 		Addrs:          cfg.Addrs,
 		Password:       cfg.Password,
 		MaxRedirects:   maxRedirects,
 		ReadOnly:       cfg.ReadOnly,
 		RouteByLatency: cfg.RouteByLatency,
 		RouteRandomly:  cfg.RouteRandomly,
-	})
+	}
+	rdb := redis.NewClusterClient(opts)
+
+	replicaOpts := *opts
+	replicaOpts.ReadOnly, replicaOpts.RouteByLatency, replicaOpts.RouteRandomly = true, false, true
+
+	clock := cfg.Clock
+	if clock == nil {
+		clock = SystemClock()
+	}
 
 	return &ClusterClient{
-		rdb: rdb,
+		rdb:   rdb,
+		clock: clock,
+		enc:   newEncoding(cfg.Codec, cfg.Encryption, cfg.Checksum),
+
+		replicaOpts: replicaOpts,
 	}
 }
 
+// replicaClient connects the replica-reading client on first use. After
+// Close it returns the closed main client, so reads fail with
+// redis.ErrClosed.
+func (c *ClusterClient) replicaClient() *redis.ClusterClient {
+	c.replicasOnce.Do(func() {
+		c.replicas = redis.NewClusterClient(&c.replicaOpts)
+	})
+	if c.replicas == nil {
+		return c.rdb
+	}
+	return c.replicas
+}
+
 // Ping checks the connection to the Redis Cluster.
 func (c *ClusterClient) Ping(ctx context.Context) error {
 	return c.rdb.Ping(ctx).Err()
@@ -72,6 +125,11 @@ func (c *ClusterClient) Ping(ctx context.Context) error {
 
 // Close closes the Redis Cluster connection.
 func (c *ClusterClient) Close() error {
+	// Keep a later FromReplica read from connecting
+	c.replicasOnce.Do(func() {})
+	if c.replicas != nil {
+		c.replicas.Close()
+	}
 	return c.rdb.Close()
 }
 
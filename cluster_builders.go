@@ -2,7 +2,6 @@ package gibrun
 
 import (
 	"context"
-	"encoding/json"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -15,6 +14,7 @@ type ClusterGibBuilder struct {
 	key    string
 	value  any
 	ttl    time.Duration
+	codec  Codec
 }
 
 // Value sets the data to be stored.
@@ -29,13 +29,19 @@ func (b *ClusterGibBuilder) TTL(d time.Duration) *ClusterGibBuilder {
 	return b
 }
 
+// Codec overrides the client codec for this operation.
+func (b *ClusterGibBuilder) Codec(c Codec) *ClusterGibBuilder {
+	b.codec = c
+	return b
+}
+
 // Exec executes the storage operation.
 func (b *ClusterGibBuilder) Exec() error {
 	if b.value == nil {
 		return ErrNilValue
 	}
 
-	data, err := b.marshal(b.value)
+	data, err := b.client.enc.marshal(b.value, b.codec)
 	if err != nil {
 		return err
 	}
@@ -46,22 +52,21 @@ func (b *ClusterGibBuilder) Exec() error {
 	return b.client.rdb.Set(b.ctx, b.key, data, 0).Err()
 }
 
-func (b *ClusterGibBuilder) marshal(v any) ([]byte, error) {
-	switch val := v.(type) {
-	case string:
-		return []byte(val), nil
-	case []byte:
-		return val, nil
-	default:
-		return json.Marshal(val)
-	}
-}
-
 // ClusterRunBuilder provides a fluent API for retrieving data from Redis Cluster.
 type ClusterRunBuilder struct {
 	ctx    context.Context
 	client *ClusterClient
 	key    string
+	codec  Codec
+
+	// replica routes the read to a replica, see FromReplica.
+	replica bool
+}
+
+// Codec overrides the client codec for this operation.
+func (b *ClusterRunBuilder) Codec(c Codec) *ClusterRunBuilder {
+	b.codec = c
+	return b
 }
 
 // Bind retrieves the data and unmarshals it into the provided pointer.
@@ -70,7 +75,7 @@ func (b *ClusterRunBuilder) Bind(dest any) (bool, error) {
 		return false, ErrNilPointer
 	}
 
-	data, err := b.client.rdb.Get(b.ctx, b.key).Bytes()
+	data, err := b.get()
 	if err != nil {
 		if err == redis.Nil {
 			return false, nil
@@ -78,7 +83,7 @@ func (b *ClusterRunBuilder) Bind(dest any) (bool, error) {
 		return false, err
 	}
 
-	if err := b.unmarshal(data, dest); err != nil {
+	if err := b.client.enc.unmarshal(data, dest, b.codec); err != nil {
 		return false, err
 	}
 
@@ -87,38 +92,28 @@ func (b *ClusterRunBuilder) Bind(dest any) (bool, error) {
 
 // Raw retrieves the raw string value without unmarshalling.
 func (b *ClusterRunBuilder) Raw() (string, bool, error) {
-	val, err := b.client.rdb.Get(b.ctx, b.key).Result()
-	if err != nil {
-		if err == redis.Nil {
-			return "", false, nil
-		}
+	val, found, err := b.Bytes()
+	if err != nil || !found {
 		return "", false, err
 	}
-	return val, true, nil
+	return string(val), true, nil
 }
 
 // Bytes retrieves the raw byte slice without unmarshalling.
 func (b *ClusterRunBuilder) Bytes() ([]byte, bool, error) {
-	val, err := b.client.rdb.Get(b.ctx, b.key).Bytes()
+	val, err := b.get()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, false, nil
 		}
 		return nil, false, err
 	}
-	return val, true, nil
-}
 
-func (b *ClusterRunBuilder) unmarshal(data []byte, dest any) error {
-	if strPtr, ok := dest.(*string); ok {
-		*strPtr = string(data)
-		return nil
-	}
-	if bytesPtr, ok := dest.(*[]byte); ok {
-		*bytesPtr = data
-		return nil
+	val, err = b.client.enc.open(val)
+	if err != nil {
+		return nil, false, err
 	}
-	return json.Unmarshal(data, dest)
+	return val, true, nil
 }
 
 // ClusterSprintBuilder provides a fluent API for atomic Redis Cluster operations.
@@ -154,12 +149,22 @@ func (b *ClusterSprintBuilder) IncrByFloat(n float64) (float64, error) {
 }
 
 // Get returns the current value as int64.
+// Returns 0 if the key doesn't exist; connection and parse errors are returned.
 func (b *ClusterSprintBuilder) Get() (int64, error) {
-	val, err := b.client.rdb.Get(b.ctx, b.key).Int64()
-	if err != nil {
-		return 0, nil
-	}
-	return val, nil
+	val, _, err := b.GetOrErr()
+	return val, err
+}
+
+// GetOrErr returns the current value and whether the key exists.
+// Returns (0, false, nil) if the key doesn't exist.
+func (b *ClusterSprintBuilder) GetOrErr() (int64, bool, error) {
+	return counterValue(b.client.rdb.Get(b.ctx, b.key))
+}
+
+// GetFloat returns the current value of a float counter.
+// Returns 0 if the key doesn't exist.
+func (b *ClusterSprintBuilder) GetFloat() (float64, error) {
+	return floatCounterValue(b.client.rdb.Get(b.ctx, b.key))
 }
 
 // SetWithTTL sets the counter to a specific value with TTL.
@@ -0,0 +1,30 @@
+package gibrun
+
+import (
+	"github.com/redis/go-redis/v9"
+)
+
+// FromReplica routes this read to a replica of the key's shard, offloading
+// the masters for latency-insensitive reads without enabling
+// ClusterConfig.ReadOnly globally. Replicas may lag behind the master.
+// Falls back to the master when the replica can't serve the key.
+//
+// Example:
+//
+//	found, err := cluster.Run(ctx, "report:monthly").FromReplica().Bind(&report)
+func (b *ClusterRunBuilder) FromReplica() *ClusterRunBuilder {
+	b.replica = true
+	return b
+}
+
+// get fetches the stored bytes, from a replica when requested.
+// Returns redis.Nil on a cache miss.
+func (b *ClusterRunBuilder) get() ([]byte, error) {
+	if b.replica {
+		data, err := b.client.replicaClient().Get(b.ctx, b.key).Bytes()
+		if err == nil || err == redis.Nil {
+			return data, err
+		}
+	}
+	return b.client.rdb.Get(b.ctx, b.key).Bytes()
+}
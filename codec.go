@@ -0,0 +1,213 @@
+package gibrun
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Codec converts values to and from their stored byte form.
+// Strings and byte slices are always stored as-is; the codec handles
+// everything else (structs, slices, maps, numbers).
+//
+// Example:
+//
+//	app := gibrun.New(gibrun.Config{
+//	    Addr:  "localhost:6379",
+//	    Codec: myMsgpackCodec{},
+//	})
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec is the default codec, backed by encoding/json.
+type JSONCodec struct{}
+
+// Marshal encodes v as JSON.
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes JSON data into v.
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// GobCodec stores values with encoding/gob for Go-to-Go caching.
+// It round-trips types that JSON handles poorly: maps with interface
+// values, full time.Time precision and types with GobEncoder/GobDecoder.
+// Concrete types stored behind interfaces must be registered with gob.Register.
+type GobCodec struct{}
+
+// Marshal encodes v with encoding/gob.
+func (GobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes gob data into v.
+func (GobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// encoding holds the value encoding settings shared by Client and ClusterClient.
+type encoding struct {
+	codec   Codec
+	keys    KeyProvider
+	schemas *schemaRegistry
+
+	// checksum adds a CRC32 envelope to written values.
+	checksum bool
+
+	// stamp records the write time in an envelope, using now.
+	stamp bool
+	now   func() time.Time
+}
+
+// opened is stored data with all envelopes removed.
+type opened struct {
+	data []byte
+	// schema is true if a schema envelope was found (and upgraded).
+	schema bool
+	// storedAt is the write time, zero unless stamped.
+	storedAt time.Time
+}
+
+// newEncoding creates the encoding settings, defaulting to JSON.
+func newEncoding(codec Codec, keys KeyProvider, checksum bool) encoding {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	return encoding{codec: codec, keys: keys, schemas: newSchemaRegistry(), checksum: checksum}
+}
+
+// codecFor returns the per-operation override if set, else the client codec.
+func (e *encoding) codecFor(override Codec) Codec {
+	if override != nil {
+		return override
+	}
+	return e.codec
+}
+
+// marshal converts the value to its stored bytes, encrypting if configured.
+func (e *encoding) marshal(v any, override Codec) ([]byte, error) {
+	return e.marshalSchema(v, override, "")
+}
+
+// marshalSchema is marshal with an explicit schema name; empty derives
+// it from the value type.
+func (e *encoding) marshalSchema(v any, override Codec, schema string) ([]byte, error) {
+	data, err := marshal(v, e.codecFor(override))
+	if err != nil {
+		return nil, err
+	}
+	return e.wrap(e.schemas.wrap(v, schema, data))
+}
+
+// unmarshal converts stored bytes back into dest, upgrading old schema
+// versions on the way.
+func (e *encoding) unmarshal(data []byte, dest any, override Codec) error {
+	o, err := e.openAll(data)
+	if err != nil {
+		return err
+	}
+	return e.decode(o, dest, override)
+}
+
+// decode unmarshals opened data into dest, treating payloads without a
+// schema envelope as version 1 of dest's type.
+func (e *encoding) decode(o opened, dest any, override Codec) error {
+	data := o.data
+	if !o.schema {
+		var err error
+		// Written before the schema was registered: version 1
+		if data, err = e.schemas.upgrade(typeName(dest), 1, data); err != nil {
+			return err
+		}
+	}
+	return unmarshal(data, dest, e.codecFor(override))
+}
+
+// wrap applies the configured envelopes to encoded bytes.
+func (e *encoding) wrap(data []byte) ([]byte, error) {
+	if e.keys != nil {
+		var err error
+		if data, err = seal(e.keys, data); err != nil {
+			return nil, err
+		}
+	}
+	if e.stamp {
+		data = addTimestamp(data, e.now())
+	}
+	// Outermost, so truncation of the stored bytes is caught first
+	if e.checksum {
+		data = addChecksum(data)
+	}
+	return data, nil
+}
+
+// open strips envelopes from stored bytes, returning the encoded payload.
+func (e *encoding) open(data []byte) ([]byte, error) {
+	o, err := e.openAll(data)
+	return o.data, err
+}
+
+// openAll strips every envelope, keeping what they carried. Envelopes are
+// detected by their headers rather than by client settings, so keyspaces
+// mixing plain and wrapped values (e.g. written before Checksum or
+// Encryption was turned on) decode transparently.
+func (e *encoding) openAll(data []byte) (opened, error) {
+	var o opened
+	for {
+		var err error
+		before := len(data)
+
+		switch kind := envelopeKind(data); kind {
+		case 0:
+			o.data = data
+			return o, nil
+
+		case envelopeVersioned:
+			// Written by IfVersion regardless of client settings
+			_, data = splitVersion(data)
+
+		case envelopeChecksum:
+			data, err = verifyChecksum(data)
+
+		case envelopeStamped:
+			o.storedAt, data = splitTimestamp(data)
+
+		case envelopeEncrypted:
+			if e.keys == nil {
+				return o, fmt.Errorf("%w: value is encrypted but the client has no Encryption configured", ErrDecryptionKeyMissing)
+			}
+			data, err = unseal(e.keys, data)
+
+		case envelopeSchema:
+			name, version, payload, ok := splitSchema(data)
+			if !ok {
+				o.data = data
+				return o, nil
+			}
+			o.schema = true
+			o.data, err = e.schemas.upgrade(name, version, payload)
+			return o, err
+
+		default:
+			return o, fmt.Errorf("%w: unknown envelope kind %q", ErrCorruptValue, kind)
+		}
+
+		if err != nil {
+			return o, err
+		}
+		if len(data) >= before {
+			return o, fmt.Errorf("%w: malformed envelope", ErrCorruptValue)
+		}
+	}
+}
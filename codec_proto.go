@@ -0,0 +1,104 @@
+package gibrun
+
+import "reflect"
+
+// ProtoCodec stores protobuf messages in their binary wire format and
+// delegates every other value to Fallback (JSONCodec by default).
+// gRPC-heavy services skip the double conversion through JSON.
+//
+// gibrun doesn't depend on google.golang.org/protobuf, so wire it in once:
+//
+//	codec := gibrun.ProtoCodec{
+//	    MarshalFunc: func(m any) ([]byte, error) {
+//	        return proto.Marshal(m.(proto.Message))
+//	    },
+//	    UnmarshalFunc: func(data []byte, m any) error {
+//	        return proto.Unmarshal(data, m.(proto.Message))
+//	    },
+//	}
+//	app := gibrun.New(gibrun.Config{Addr: "localhost:6379", Codec: codec})
+//
+// Messages generated by vtprotobuf (MarshalVT/UnmarshalVT) or gogoproto
+// (Marshal/Unmarshal methods) are handled without any configuration.
+type ProtoCodec struct {
+	// MarshalFunc encodes a protobuf message, typically proto.Marshal.
+	MarshalFunc func(m any) ([]byte, error)
+
+	// UnmarshalFunc decodes into a protobuf message, typically proto.Unmarshal.
+	UnmarshalFunc func(data []byte, m any) error
+
+	// Fallback handles non-protobuf values. Default is JSONCodec.
+	Fallback Codec
+}
+
+// Method sets generated by common protobuf toolchains.
+type (
+	vtMarshaler interface {
+		MarshalVT() ([]byte, error)
+	}
+	vtUnmarshaler interface {
+		UnmarshalVT([]byte) error
+	}
+	gogoMarshaler interface {
+		Marshal() ([]byte, error)
+	}
+	gogoUnmarshaler interface {
+		Unmarshal([]byte) error
+	}
+)
+
+// Marshal encodes protobuf messages in wire format, other values via Fallback.
+func (c ProtoCodec) Marshal(v any) ([]byte, error) {
+	if !isProtoMessage(v) {
+		return c.fallback().Marshal(v)
+	}
+	if m, ok := v.(vtMarshaler); ok {
+		return m.MarshalVT()
+	}
+	if c.MarshalFunc != nil {
+		return c.MarshalFunc(v)
+	}
+	if m, ok := v.(gogoMarshaler); ok {
+		return m.Marshal()
+	}
+	return nil, ErrProtoNotConfigured
+}
+
+// Unmarshal decodes wire format into protobuf messages, other values via Fallback.
+func (c ProtoCodec) Unmarshal(data []byte, v any) error {
+	if !isProtoMessage(v) {
+		return c.fallback().Unmarshal(data, v)
+	}
+	if m, ok := v.(vtUnmarshaler); ok {
+		return m.UnmarshalVT(data)
+	}
+	if c.UnmarshalFunc != nil {
+		return c.UnmarshalFunc(data, v)
+	}
+	if m, ok := v.(gogoUnmarshaler); ok {
+		return m.Unmarshal(data)
+	}
+	return ErrProtoNotConfigured
+}
+
+func (c ProtoCodec) fallback() Codec {
+	if c.Fallback != nil {
+		return c.Fallback
+	}
+	return JSONCodec{}
+}
+
+// isProtoMessage detects generated protobuf messages without importing
+// the protobuf runtime: APIv2 messages have ProtoReflect, APIv1 and
+// gogoproto messages have ProtoMessage.
+func isProtoMessage(v any) bool {
+	if v == nil {
+		return false
+	}
+	t := reflect.TypeOf(v)
+	if _, ok := t.MethodByName("ProtoReflect"); ok {
+		return true
+	}
+	_, ok := t.MethodByName("ProtoMessage")
+	return ok
+}
@@ -0,0 +1,215 @@
+package gibrun
+
+import (
+	"github.com/redis/go-redis/v9"
+)
+
+// incrIfBelowScript increments the counter only if the result stays at or
+// under the limit. Returns {allowed, value}.
+//
+// KEYS[1] = counter
+// ARGV[1] = increment, ARGV[2] = limit
+var incrIfBelowScript = redis.NewScript(`
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+if cur + tonumber(ARGV[1]) > tonumber(ARGV[2]) then
+  return {0, cur}
+end
+return {1, redis.call('INCRBY', KEYS[1], ARGV[1])}
+`)
+
+// IncrIfBelow increments the value by 1 only if it is below limit, so the
+// counter never exceeds it, even under concurrency. Returns the new value
+// and true, or the unchanged value and false when the limit was reached.
+//
+// Example:
+//
+//	seats, ok, err := app.Sprint(ctx, "event:42:booked").IncrIfBelow(100)
+//	if !ok {
+//	    // sold out
+//	}
+func (b *SprintBuilder) IncrIfBelow(limit int64) (int64, bool, error) {
+	return b.IncrByIfBelow(1, limit)
+}
+
+// IncrByIfBelow increments the value by n only if the result is at most
+// limit. Returns the new value and true, or the unchanged value and false.
+func (b *SprintBuilder) IncrByIfBelow(n, limit int64) (int64, bool, error) {
+	if !b.client.scripting(b.ctx) {
+		return b.incrIfBelowFallback(n, limit)
+	}
+	vals, err := incrIfBelowScript.Run(b.ctx, b.client.rdb, []string{b.key}, n, limit).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+	return vals[1], vals[0] == 1, nil
+}
+
+// incrIfBelowFallback mirrors incrIfBelowScript with WATCH/MULTI.
+func (b *SprintBuilder) incrIfBelowFallback(n, limit int64) (int64, bool, error) {
+	var val int64
+	var allowed bool
+	err := b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		cur, _, err := counterValue(tx.Get(b.ctx, b.key))
+		if err != nil {
+			return err
+		}
+		if cur+n > limit {
+			val, allowed = cur, false
+			return nil
+		}
+		var incr *redis.IntCmd
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			incr = pipe.IncrBy(b.ctx, b.key, n)
+			return nil
+		})
+		val, allowed = incr.Val(), err == nil
+		return err
+	}, b.key)
+	return val, allowed, err
+}
+
+// casCounterScript sets the counter to new only if it currently equals
+// old. INCRBY by the difference keeps any TTL. Missing keys count as 0.
+//
+// KEYS[1] = counter
+// ARGV[1] = old, ARGV[2] = new
+var casCounterScript = redis.NewScript(`
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+if cur ~= tonumber(ARGV[1]) then
+  return 0
+end
+redis.call('INCRBY', KEYS[1], tonumber(ARGV[2]) - cur)
+return 1
+`)
+
+// CompareAndSwap atomically sets the value to new if it currently equals
+// old, for numeric state machines that shouldn't need WATCH boilerplate.
+// A missing key counts as 0 and the TTL is kept. Returns false if the
+// value was something else.
+//
+// Example:
+//
+//	const pending, running = 0, 1
+//	ok, err := app.Sprint(ctx, "job:42:state").CompareAndSwap(pending, running)
+//	if ok {
+//	    // this worker owns the job
+//	}
+func (b *SprintBuilder) CompareAndSwap(old, new int64) (bool, error) {
+	if !b.client.scripting(b.ctx) {
+		return b.compareAndSwapFallback(old, new)
+	}
+	n, err := casCounterScript.Run(b.ctx, b.client.rdb, []string{b.key}, old, new).Int()
+	return n == 1, err
+}
+
+// compareAndSwapFallback mirrors casCounterScript with WATCH/MULTI.
+func (b *SprintBuilder) compareAndSwapFallback(old, new int64) (bool, error) {
+	swapped := false
+	err := b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		swapped = false
+		cur, _, err := counterValue(tx.Get(b.ctx, b.key))
+		if err != nil || cur != old {
+			return err
+		}
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			pipe.IncrBy(b.ctx, b.key, new-cur)
+			return nil
+		})
+		swapped = err == nil
+		return err
+	}, b.key)
+	return swapped, err
+}
+
+// boundedIncrScript adds to the counter, clamping the result into
+// [min, max]. INCRBY by the difference keeps any TTL. Returns {value, clamped}.
+//
+// KEYS[1] = counter
+// ARGV[1] = increment, ARGV[2] = min, ARGV[3] = max
+var boundedIncrScript = redis.NewScript(`
+local cur = tonumber(redis.call('GET', KEYS[1]) or '0')
+local v = cur + tonumber(ARGV[1])
+local clamped = 0
+if v < tonumber(ARGV[2]) then
+  v = tonumber(ARGV[2])
+  clamped = 1
+elseif v > tonumber(ARGV[3]) then
+  v = tonumber(ARGV[3])
+  clamped = 1
+end
+redis.call('INCRBY', KEYS[1], v - cur)
+return {v, clamped}
+`)
+
+// BoundedSprint updates a counter that must stay within [min, max].
+type BoundedSprint struct {
+	b        *SprintBuilder
+	min, max int64
+}
+
+// Bounded clamps counter updates into [min, max] atomically, for stock
+// levels and balances that must never go negative or past a cap.
+//
+// Example:
+//
+//	stock, clamped, err := app.Sprint(ctx, "sku:42:stock").Bounded(0, 500).DecrBy(3)
+//	if clamped {
+//	    // fewer than 3 were left, stock is now 0
+//	}
+func (b *SprintBuilder) Bounded(min, max int64) *BoundedSprint {
+	return &BoundedSprint{b: b, min: min, max: max}
+}
+
+// Incr adds 1, clamped. Returns the new value and whether it was clamped.
+func (s *BoundedSprint) Incr() (int64, bool, error) {
+	return s.IncrBy(1)
+}
+
+// Decr subtracts 1, clamped. Returns the new value and whether it was clamped.
+func (s *BoundedSprint) Decr() (int64, bool, error) {
+	return s.IncrBy(-1)
+}
+
+// DecrBy subtracts n, clamped. Returns the new value and whether it was clamped.
+func (s *BoundedSprint) DecrBy(n int64) (int64, bool, error) {
+	return s.IncrBy(-n)
+}
+
+// IncrBy adds n, clamped. Returns the new value and whether it was clamped.
+// A missing key counts as 0 and the TTL is kept.
+func (s *BoundedSprint) IncrBy(n int64) (int64, bool, error) {
+	b := s.b
+	if !b.client.scripting(b.ctx) {
+		return s.incrByFallback(n)
+	}
+	vals, err := boundedIncrScript.Run(b.ctx, b.client.rdb, []string{b.key}, n, s.min, s.max).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+	return vals[0], vals[1] == 1, nil
+}
+
+// incrByFallback mirrors boundedIncrScript with WATCH/MULTI.
+func (s *BoundedSprint) incrByFallback(n int64) (int64, bool, error) {
+	b := s.b
+	var val int64
+	var clamped bool
+	err := b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		cur, _, err := counterValue(tx.Get(b.ctx, b.key))
+		if err != nil {
+			return err
+		}
+		val, clamped = cur+n, false
+		if val < s.min {
+			val, clamped = s.min, true
+		} else if val > s.max {
+			val, clamped = s.max, true
+		}
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			pipe.IncrBy(b.ctx, b.key, val-cur)
+			return nil
+		})
+		return err
+	}, b.key)
+	return val, clamped, err
+}
@@ -0,0 +1,204 @@
+package gibrun
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// dedupPrefix namespaces applied dedup tokens.
+const dedupPrefix = "gibrun:dedup:"
+
+// defaultDedupWindow is how long an applied token is remembered.
+const defaultDedupWindow = 24 * time.Hour
+
+// dedupKey returns the marker recording token as applied to key. Tokens
+// are scoped per key, and the marker shares key's hash slot: key's own
+// hash tag is kept, otherwise the whole key becomes the tag.
+func dedupKey(key, token string) string {
+	if open := strings.IndexByte(key, '{'); open >= 0 {
+		if end := strings.IndexByte(key[open+1:], '}'); end > 0 {
+			return dedupPrefix + key + ":" + token
+		}
+	}
+	return dedupPrefix + "{" + key + "}:" + token
+}
+
+// dedupWindowOrDefault returns d, or the default window when d is unset.
+func dedupWindowOrDefault(d time.Duration) time.Duration {
+	if d <= 0 {
+		return defaultDedupWindow
+	}
+	return d
+}
+
+// dedupSetScript writes the value unless the token was already applied.
+//
+// KEYS[1] = key, KEYS[2] = token key
+// ARGV[1] = payload, ARGV[2] = TTL in milliseconds (0 = none),
+// ARGV[3] = dedup window in milliseconds
+// Returns 1 written, 0 deduplicated.
+var dedupSetScript = redis.NewScript(`
+if redis.call('SET', KEYS[2], 1, 'NX', 'PX', ARGV[3]) == false then
+  return 0
+end
+if tonumber(ARGV[2]) > 0 then
+  redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+else
+  redis.call('SET', KEYS[1], ARGV[1])
+end
+return 1
+`)
+
+// dedupIncrScript increments the counter unless the token was already
+// applied, returning {applied, value}.
+//
+// KEYS[1] = key, KEYS[2] = token key
+// ARGV[1] = increment, ARGV[2] = dedup window in milliseconds
+var dedupIncrScript = redis.NewScript(`
+if redis.call('SET', KEYS[2], 1, 'NX', 'PX', ARGV[2]) == false then
+  return {0, tonumber(redis.call('GET', KEYS[1]) or '0')}
+end
+return {1, redis.call('INCRBY', KEYS[1], ARGV[1])}
+`)
+
+// DedupToken makes the write idempotent: if the same token was applied
+// to this key within the dedup window (default 24 hours), the write is
+// skipped.
+// Exec still returns nil; ExecDetail reports it as Deduplicated. Use the
+// request or webhook delivery ID so retries don't double-write.
+// Not supported together with AsHash, Append, IfVersion or IfNotExists,
+// and values are never chunked.
+//
+// Example:
+//
+//	err := app.Gib(ctx, "order:42:status").Value("paid").DedupToken(event.ID).Exec()
+func (b *GibBuilder) DedupToken(token string) *GibBuilder {
+	b.dedupToken = token
+	return b
+}
+
+// DedupWindow sets how long DedupToken remembers applied tokens.
+func (b *GibBuilder) DedupWindow(d time.Duration) *GibBuilder {
+	b.dedupWindow = d
+	return b
+}
+
+// execDedup writes data unless the token was already applied.
+func (b *GibBuilder) execDedup(data []byte, ttl time.Duration) (bool, error) {
+	if b.asHash || b.appendMode || b.ifVersion != nil || b.ifNotExists {
+		return false, fmt.Errorf("%w: DedupToken can't be combined with AsHash, Append, IfVersion or IfNotExists", ErrUnsupported)
+	}
+
+	window := dedupWindowOrDefault(b.dedupWindow)
+	var n int
+	var err error
+	if b.client.scripting(b.ctx) {
+		n, err = dedupSetScript.Run(b.ctx, b.client.rdb, []string{b.key, dedupKey(b.key, b.dedupToken)},
+			data, ttl.Milliseconds(), window.Milliseconds()).Int()
+	} else {
+		n, err = b.dedupSetFallback(data, ttl, window)
+	}
+	if err != nil {
+		return false, err
+	}
+	if b.detail != nil {
+		b.detail.Deduplicated = n == 0
+	}
+	return n == 1, nil
+}
+
+// dedupSetFallback mirrors dedupSetScript with WATCH/MULTI.
+func (b *GibBuilder) dedupSetFallback(data []byte, ttl, window time.Duration) (int, error) {
+	token := dedupKey(b.key, b.dedupToken)
+	n := 0
+	err := b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		n = 0
+		seen, err := tx.Exists(b.ctx, token).Result()
+		if err != nil || seen > 0 {
+			return err
+		}
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(b.ctx, token, 1, window)
+			pipe.Set(b.ctx, b.key, data, ttl)
+			return nil
+		})
+		if err == nil {
+			n = 1
+		}
+		return err
+	}, token)
+	return n, err
+}
+
+// DedupToken makes the next counter operation idempotent: if the token
+// was applied to this counter within the dedup window (default 24 hours),
+// the counter is left unchanged and its current value returned.
+//
+// Example:
+//
+//	total, err := app.Sprint(ctx, "stats:payments").DedupToken(webhook.ID).IncrBy(amount)
+func (b *SprintBuilder) DedupToken(token string) *SprintBuilder {
+	b.dedupToken = token
+	return b
+}
+
+// DedupWindow sets how long DedupToken remembers applied tokens.
+//
+// Example:
+//
+//	total, err := app.Sprint(ctx, "stats:payments").
+//	    DedupToken(webhook.ID).
+//	    DedupWindow(72 * time.Hour).
+//	    IncrBy(amount)
+func (b *SprintBuilder) DedupWindow(d time.Duration) *SprintBuilder {
+	b.dedupWindow = d
+	return b
+}
+
+// dedupIncrBy increments by n unless the token was already applied.
+func (b *SprintBuilder) dedupIncrBy(n int64) (int64, error) {
+	if !b.client.scripting(b.ctx) {
+		return b.dedupIncrFallback(n)
+	}
+	vals, err := dedupIncrScript.Run(b.ctx, b.client.rdb, []string{b.key, dedupKey(b.key, b.dedupToken)},
+		n, dedupWindowOrDefault(b.dedupWindow).Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, err
+	}
+	return vals[1], nil
+}
+
+// dedupIncrFallback mirrors dedupIncrScript with WATCH/MULTI.
+func (b *SprintBuilder) dedupIncrFallback(n int64) (int64, error) {
+	token := dedupKey(b.key, b.dedupToken)
+	var val int64
+	err := b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		seen, err := tx.Exists(b.ctx, token).Result()
+		if err != nil {
+			return err
+		}
+		if seen > 0 {
+			val, err = tx.Get(b.ctx, b.key).Int64()
+			if err == redis.Nil {
+				val, err = 0, nil
+			}
+			return err
+		}
+
+		var incr *redis.IntCmd
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(b.ctx, token, 1, dedupWindowOrDefault(b.dedupWindow))
+			incr = pipe.IncrBy(b.ctx, b.key, n)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		val = incr.Val()
+		return nil
+	}, token, b.key)
+	return val, err
+}
@@ -0,0 +1,182 @@
+package gibrun
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DegradationConfig monitors rolling operation latency and flags the
+// client as degraded when p99 exceeds a threshold, so applications can
+// shed cache usage gracefully during incidents.
+type DegradationConfig struct {
+	// Threshold is the p99 latency above which the client is degraded.
+	Threshold time.Duration
+
+	// Window is the number of recent operations in the rolling sample.
+	// Default is 1000.
+	Window int
+
+	// MinSamples is the number of samples needed before p99 is trusted.
+	// Default is 100.
+	MinSamples int
+
+	// OnChange is called when the degraded state flips.
+	OnChange func(degraded bool, p99 time.Duration)
+}
+
+// latencyMonitor is a go-redis hook recording command latencies.
+type latencyMonitor struct {
+	cfg DegradationConfig
+
+	mu      sync.Mutex
+	samples []time.Duration
+	pos     int
+	filled  bool
+	since   int
+
+	degraded atomic.Bool
+	p99      atomic.Int64
+}
+
+// recomputeEvery bounds how often p99 is recalculated.
+const recomputeEvery = 50
+
+func newLatencyMonitor(cfg DegradationConfig) *latencyMonitor {
+	if cfg.Window <= 0 {
+		cfg.Window = 1000
+	}
+	if cfg.MinSamples <= 0 {
+		cfg.MinSamples = 100
+	}
+	if cfg.MinSamples > cfg.Window {
+		cfg.MinSamples = cfg.Window
+	}
+	return &latencyMonitor{
+		cfg:     cfg,
+		samples: make([]time.Duration, cfg.Window),
+	}
+}
+
+func (m *latencyMonitor) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (m *latencyMonitor) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if isBlocking(cmd) {
+			return next(ctx, cmd)
+		}
+		start := time.Now()
+		err := next(ctx, cmd)
+		m.record(time.Since(start))
+		return err
+	}
+}
+
+func (m *latencyMonitor) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if isBlocking(cmd) {
+				return next(ctx, cmds)
+			}
+		}
+		start := time.Now()
+		err := next(ctx, cmds)
+		m.record(time.Since(start))
+		return err
+	}
+}
+
+// blockingCommands wait server-side by design, so their duration says
+// nothing about Redis health.
+var blockingCommands = map[string]bool{
+	"blpop": true, "brpop": true, "brpoplpush": true, "blmove": true, "blmpop": true,
+	"bzpopmin": true, "bzpopmax": true, "bzmpop": true,
+	"wait": true, "waitaof": true,
+	"subscribe": true, "psubscribe": true, "ssubscribe": true,
+}
+
+// isBlocking reports whether cmd may wait server-side: the blocking
+// list and sorted-set pops, and XREAD/XREADGROUP with BLOCK.
+func isBlocking(cmd redis.Cmder) bool {
+	name := cmd.Name()
+	if blockingCommands[name] {
+		return true
+	}
+	if name != "xread" && name != "xreadgroup" {
+		return false
+	}
+	for _, arg := range cmd.Args() {
+		if s, ok := arg.(string); ok && strings.EqualFold(s, "block") {
+			return true
+		}
+	}
+	return false
+}
+
+// record adds a sample and periodically re-evaluates the degraded state.
+func (m *latencyMonitor) record(d time.Duration) {
+	m.mu.Lock()
+	m.samples[m.pos] = d
+	m.pos = (m.pos + 1) % len(m.samples)
+	if m.pos == 0 {
+		m.filled = true
+	}
+	m.since++
+
+	n := m.pos
+	if m.filled {
+		n = len(m.samples)
+	}
+	if m.since < recomputeEvery || n < m.cfg.MinSamples {
+		m.mu.Unlock()
+		return
+	}
+	m.since = 0
+
+	sorted := make([]time.Duration, n)
+	copy(sorted, m.samples[:n])
+	m.mu.Unlock()
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	p99 := sorted[(len(sorted)*99)/100]
+	m.p99.Store(int64(p99))
+
+	degraded := p99 > m.cfg.Threshold
+	if m.degraded.Swap(degraded) != degraded && m.cfg.OnChange != nil {
+		m.cfg.OnChange(degraded, p99)
+	}
+}
+
+// Degraded reports whether rolling p99 latency currently exceeds
+// Config.Degradation.Threshold. Always false when monitoring is off.
+// Blocking reads (BLPOP, XREADGROUP BLOCK and the like) aren't sampled.
+// While degraded, the L1 cache can keep serving expired entries, see
+// L1Config.StaleWhileDegraded.
+//
+// Example:
+//
+//	if app.Degraded() {
+//	    return loadFromDatabase(ctx, id) // skip the cache during incidents
+//	}
+func (c *Client) Degraded() bool {
+	return c.latency != nil && c.latency.degraded.Load()
+}
+
+// LatencyP99 returns the last computed rolling p99 latency.
+// Zero when monitoring is off or not enough samples were collected.
+func (c *Client) LatencyP99() time.Duration {
+	if c.latency == nil {
+		return 0
+	}
+	return time.Duration(c.latency.p99.Load())
+}
+
+// Compile-time check that latencyMonitor is a go-redis hook.
+var _ redis.Hook = (*latencyMonitor)(nil)
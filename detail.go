@@ -0,0 +1,101 @@
+package gibrun
+
+import (
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// GibResult describes a completed write.
+type GibResult struct {
+	// Size is the number of bytes written after encoding. For AsHash it
+	// is the sum of the field values, for Append the appended data.
+	Size int
+
+	// Created is true if the key didn't exist before the write.
+	Created bool
+
+	// Replaced is true if the write overwrote or extended an existing key.
+	Replaced bool
+
+	// TTL is the effective TTL after TTL policies, 0 for none.
+	TTL time.Duration
+
+	// Deduplicated is true if DedupToken skipped the write.
+	Deduplicated bool
+}
+
+// ExecDetail executes the write like Exec and reports what happened,
+// so callers can emit size metrics and detect unexpected overwrites.
+// For plain values the existence check and SET run in one transaction;
+// for AsHash, Append, IfVersion and chunked writes it is checked just
+// before the write.
+//
+// Example:
+//
+//	res, err := app.Gib(ctx, "user:123").Value(user).TTL(time.Hour).ExecDetail()
+//	if err == nil && res.Replaced {
+//	    log.Printf("overwrote user:123 (%d bytes)", res.Size)
+//	}
+func (b *GibBuilder) ExecDetail() (*GibResult, error) {
+	b.detail = &GibResult{}
+	if err := b.Exec(); err != nil {
+		return nil, err
+	}
+	return b.detail, nil
+}
+
+// set runs the plain SET, checking existence in the same transaction
+// when ExecDetail is collecting details.
+func (b *GibBuilder) set(data []byte, ttl time.Duration) error {
+	if b.client.chunkSize > 0 && !b.ifNotExists {
+		return b.setOverChunks(data, ttl)
+	}
+
+	args := redis.SetArgs{TTL: ttl}
+	if b.ifNotExists {
+		args.Mode = "NX"
+	}
+
+	if b.detail == nil {
+		err := b.client.rdb.SetArgs(b.ctx, b.key, data, args).Err()
+		if b.ifNotExists {
+			b.acquired = err == nil
+			if err == redis.Nil {
+				return nil
+			}
+		}
+		return err
+	}
+
+	var exists *redis.IntCmd
+	var set *redis.StatusCmd
+	_, err := b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		exists = pipe.Exists(b.ctx, b.key)
+		set = pipe.SetArgs(b.ctx, b.key, data, args)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	existed := exists.Val() > 0
+	b.acquired = set.Err() == nil
+	b.detail.Created = !existed
+	b.detail.Replaced = existed && !b.ifNotExists
+	return nil
+}
+
+// noteExisting records whether the key exists ahead of a write that
+// can't check it atomically.
+func (b *GibBuilder) noteExisting() error {
+	if b.detail == nil {
+		return nil
+	}
+	n, err := b.client.rdb.Exists(b.ctx, b.key).Result()
+	if err != nil {
+		return err
+	}
+	b.detail.Replaced = n > 0
+	b.detail.Created = !b.detail.Replaced
+	return nil
+}
@@ -0,0 +1,262 @@
+package gibrun
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DiffKind classifies a difference between two keyspaces.
+type DiffKind int
+
+const (
+	// DiffOnlyA means the key exists only in A.
+	DiffOnlyA DiffKind = iota
+	// DiffOnlyB means the key exists only in B.
+	DiffOnlyB
+	// DiffValue means the key exists in both with different values.
+	DiffValue
+	// DiffTTL means the values match but the TTLs differ beyond tolerance.
+	DiffTTL
+)
+
+// String returns a readable name for the kind.
+func (k DiffKind) String() string {
+	switch k {
+	case DiffOnlyA:
+		return "only-in-a"
+	case DiffOnlyB:
+		return "only-in-b"
+	case DiffValue:
+		return "value"
+	case DiffTTL:
+		return "ttl"
+	}
+	return "unknown"
+}
+
+// DiffEntry is a single key that differs between A and B.
+type DiffEntry struct {
+	Key  string
+	Kind DiffKind
+	// TTLA and TTLB are the remaining TTLs, -1 for keys without expiry.
+	TTLA time.Duration
+	TTLB time.Duration
+}
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	// Pattern is the key pattern to compare. Default is "*".
+	Pattern string
+
+	// BatchSize is the SCAN count and pipeline size. Default is 100.
+	BatchSize int64
+
+	// CompareTTL also reports keys whose TTLs differ.
+	CompareTTL bool
+
+	// TTLTolerance is the allowed TTL difference. Default is 1 second.
+	TTLTolerance time.Duration
+
+	// OnEntry streams differences as they are found instead of collecting
+	// them in the report. Return false to stop early.
+	OnEntry func(DiffEntry) bool
+}
+
+// DiffReport summarizes a Diff run.
+type DiffReport struct {
+	// Scanned is the number of distinct keys looked at.
+	Scanned int
+	OnlyA   int
+	OnlyB   int
+	Value   int
+	TTL     int
+
+	// Entries holds the differences when OnEntry is not set.
+	Entries []DiffEntry
+}
+
+// Equal reports whether no differences were found.
+func (r *DiffReport) Equal() bool {
+	return r.OnlyA+r.OnlyB+r.Value+r.TTL == 0
+}
+
+// Diff compares the keyspaces of two deployments, reporting keys only in
+// A, only in B, and keys whose values (or TTLs) differ. Keys are scanned
+// and fetched in pipelined batches, so large datasets can be streamed via
+// OnEntry without holding them in memory. Use it to validate blue/green
+// deployments or debug replication drift.
+//
+// Values are compared by their DUMP payload, so both sides should run the
+// same Redis major version. Writes during the run may show up as
+// differences.
+//
+// Example:
+//
+//	report, err := gibrun.Diff(ctx, oldRedis, newCluster, gibrun.DiffOptions{
+//	    Pattern:    "user:*",
+//	    CompareTTL: true,
+//	})
+//	if err == nil && !report.Equal() {
+//	    log.Printf("drift: %d only-old, %d only-new, %d changed",
+//	        report.OnlyA, report.OnlyB, report.Value)
+//	}
+func Diff(ctx context.Context, a, b Target, opts DiffOptions) (*DiffReport, error) {
+	if opts.Pattern == "" {
+		opts.Pattern = "*"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.TTLTolerance == 0 {
+		opts.TTLTolerance = time.Second
+	}
+
+	d := &differ{ctx: ctx, a: a.cmdable(), b: b.cmdable(), opts: opts, report: &DiffReport{}}
+
+	// Pass 1: every key in A, compared against B.
+	scan := ScanOptions{Pattern: opts.Pattern, Count: opts.BatchSize}
+	err := a.scanBatches(ctx, scan, d.compareBatch)
+	if err == nil {
+		// Pass 2: keys in B that A doesn't have.
+		err = b.scanBatches(ctx, scan, d.missingBatch)
+	}
+	if err == errDiffStopped {
+		err = nil
+	}
+	return d.report, err
+}
+
+// errDiffStopped ends the scan when OnEntry returns false.
+var errDiffStopped = errors.New("gibrun: diff stopped")
+
+type differ struct {
+	ctx    context.Context
+	a, b   redis.Cmdable
+	opts   DiffOptions
+	report *DiffReport
+}
+
+// snapshot holds DUMP payloads and TTLs for a batch of keys.
+type snapshot struct {
+	dumps []*redis.StringCmd
+	ttls  []*redis.DurationCmd
+}
+
+func (d *differ) fetch(rdb redis.Cmdable, keys []string) (*snapshot, error) {
+	s := &snapshot{
+		dumps: make([]*redis.StringCmd, len(keys)),
+		ttls:  make([]*redis.DurationCmd, len(keys)),
+	}
+	_, err := rdb.Pipelined(d.ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			s.dumps[i] = pipe.Dump(d.ctx, key)
+			s.ttls[i] = pipe.PTTL(d.ctx, key)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (d *differ) compareBatch(keys []string) error {
+	sa, err := d.fetch(d.a, keys)
+	if err != nil {
+		return err
+	}
+	sb, err := d.fetch(d.b, keys)
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		dumpA, errA := sa.dumps[i].Bytes()
+		if errA == redis.Nil {
+			// Expired or deleted since SCAN
+			continue
+		}
+		d.report.Scanned++
+
+		entry := DiffEntry{Key: key, TTLA: sa.ttls[i].Val(), TTLB: sb.ttls[i].Val()}
+		dumpB, errB := sb.dumps[i].Bytes()
+		switch {
+		case errB == redis.Nil:
+			entry.Kind = DiffOnlyA
+		case !bytes.Equal(dumpA, dumpB):
+			entry.Kind = DiffValue
+		case d.opts.CompareTTL && !ttlWithin(entry.TTLA, entry.TTLB, d.opts.TTLTolerance):
+			entry.Kind = DiffTTL
+		default:
+			continue
+		}
+		if err := d.emit(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *differ) missingBatch(keys []string) error {
+	exists := make([]*redis.IntCmd, len(keys))
+	_, err := d.a.Pipelined(d.ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			exists[i] = pipe.Exists(d.ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, key := range keys {
+		if exists[i].Val() > 0 {
+			continue
+		}
+		d.report.Scanned++
+		ttl, _ := d.b.PTTL(d.ctx, key).Result()
+		if err := d.emit(DiffEntry{Key: key, Kind: DiffOnlyB, TTLA: -2, TTLB: ttl}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *differ) emit(e DiffEntry) error {
+	switch e.Kind {
+	case DiffOnlyA:
+		d.report.OnlyA++
+	case DiffOnlyB:
+		d.report.OnlyB++
+	case DiffValue:
+		d.report.Value++
+	case DiffTTL:
+		d.report.TTL++
+	}
+
+	if d.opts.OnEntry == nil {
+		d.report.Entries = append(d.report.Entries, e)
+		return nil
+	}
+	if !d.opts.OnEntry(e) {
+		return errDiffStopped
+	}
+	return nil
+}
+
+// ttlWithin reports whether two PTTL results agree within tolerance.
+// Keys without expiry only match each other.
+func ttlWithin(a, b, tolerance time.Duration) bool {
+	if a < 0 || b < 0 {
+		return a == b
+	}
+	diff := a - b
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= tolerance
+}
@@ -0,0 +1,172 @@
+package gibrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Result wraps the reply of a raw command sent through Do,
+// with typed accessors so callers don't need to import go-redis.
+type Result struct {
+	cmd *redis.Cmd
+	enc *encoding
+}
+
+// Do sends a raw command for the occasional operation gibrun doesn't cover.
+// The command still goes through the client connection pool and hooks.
+//
+// Example:
+//
+//	n, err := app.Do(ctx, "OBJECT", "FREQ", "user:123").Int()
+func (c *Client) Do(ctx context.Context, args ...any) *Result {
+	return &Result{
+		cmd: c.rdb.Do(ctx, args...),
+		enc: &c.enc,
+	}
+}
+
+// Do sends a raw command to the cluster.
+// Keys are routed using the first key argument, like go-redis.
+func (c *ClusterClient) Do(ctx context.Context, args ...any) *Result {
+	return &Result{
+		cmd: c.rdb.Do(ctx, args...),
+		enc: &c.enc,
+	}
+}
+
+// Err returns the command error, if any.
+// A nil reply is reported as an error; check it with IsNil.
+func (r *Result) Err() error {
+	return r.cmd.Err()
+}
+
+// IsNil reports whether the server replied with nil (e.g. missing key).
+func (r *Result) IsNil() bool {
+	return r.cmd.Err() == redis.Nil
+}
+
+// Val returns the raw reply value.
+func (r *Result) Val() any {
+	return r.cmd.Val()
+}
+
+// Int returns the reply as int64.
+func (r *Result) Int() (int64, error) {
+	return r.cmd.Int64()
+}
+
+// Float64 returns the reply as float64.
+func (r *Result) Float64() (float64, error) {
+	return r.cmd.Float64()
+}
+
+// Bool returns the reply as bool.
+func (r *Result) Bool() (bool, error) {
+	return r.cmd.Bool()
+}
+
+// String returns the reply as a string.
+func (r *Result) String() (string, error) {
+	return r.cmd.Text()
+}
+
+// Slice returns the reply as a slice of raw values.
+func (r *Result) Slice() ([]any, error) {
+	return r.cmd.Slice()
+}
+
+// Strings returns the reply as a string slice.
+func (r *Result) Strings() ([]string, error) {
+	return r.cmd.StringSlice()
+}
+
+// Scan decodes the reply into dest.
+// String replies are decoded with the client codec (like Run().Bind),
+// array and map replies are mapped onto dest via their JSON shape. Flat
+// field/value arrays (RESP2 replies such as HGETALL) are read as maps
+// when dest is a struct or map.
+//
+// Example:
+//
+//	var doc Profile
+//	err := app.Do(ctx, "JSON.GET", "user:1").Scan(&doc)
+func (r *Result) Scan(dest any) error {
+	if dest == nil {
+		return ErrNilPointer
+	}
+
+	val, err := r.cmd.Result()
+	if err != nil {
+		return err
+	}
+
+	switch v := val.(type) {
+	case string:
+		return r.enc.unmarshal([]byte(v), dest, nil)
+	case []byte:
+		return r.enc.unmarshal(v, dest, nil)
+	default:
+		if pairs, ok := v.([]any); ok && wantsObject(dest) {
+			if m, ok := pairsToMap(pairs); ok {
+				v = m
+			}
+		}
+		data, err := json.Marshal(normalizeReply(v))
+		if err != nil {
+			return fmt.Errorf("gibrun: cannot scan reply: %w", err)
+		}
+		return json.Unmarshal(data, dest)
+	}
+}
+
+// wantsObject reports whether dest points to a struct or map.
+func wantsObject(dest any) bool {
+	t := reflect.TypeOf(dest)
+	if t.Kind() != reflect.Pointer {
+		return false
+	}
+	k := t.Elem().Kind()
+	return k == reflect.Struct || k == reflect.Map
+}
+
+// pairsToMap turns a flat field/value array into a map. Reports false
+// when the array has an odd length or a key that isn't a string.
+func pairsToMap(pairs []any) (map[string]any, bool) {
+	if len(pairs)%2 != 0 {
+		return nil, false
+	}
+	m := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		k, ok := pairs[i].(string)
+		if !ok {
+			return nil, false
+		}
+		m[k] = pairs[i+1]
+	}
+	return m, true
+}
+
+// normalizeReply converts RESP3 maps with interface keys into
+// string-keyed maps so the reply can be JSON encoded.
+func normalizeReply(v any) any {
+	switch val := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(val))
+		for k, item := range val {
+			m[fmt.Sprint(k)] = normalizeReply(item)
+		}
+		return m
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = normalizeReply(item)
+		}
+		return out
+	default:
+		return val
+	}
+}
@@ -0,0 +1,208 @@
+package gibrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseTTL parses a human-friendly duration such as "90s", "15m",
+// "2h30m" or "7d". It accepts everything time.ParseDuration does plus a
+// "d" (24h day) unit, and rejects negative values. A bare number is read
+// as seconds.
+//
+// Example:
+//
+//	ttl, err := gibrun.ParseTTL(os.Getenv("SESSION_TTL")) // "30m"
+func ParseTTL(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("gibrun: empty duration")
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n < 0 {
+			return 0, fmt.Errorf("gibrun: negative duration %q", s)
+		}
+		return time.Duration(n) * time.Second, nil
+	}
+
+	var days time.Duration
+	if i := strings.IndexByte(s, 'd'); i >= 0 {
+		n, err := strconv.ParseFloat(s[:i], 64)
+		if err != nil {
+			return 0, fmt.Errorf("gibrun: invalid duration %q", s)
+		}
+		days = time.Duration(n * float64(24*time.Hour))
+		s = s[i+1:]
+	}
+
+	var rest time.Duration
+	if s != "" {
+		var err error
+		if rest, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("gibrun: invalid duration %q", s)
+		}
+	}
+
+	d := days + rest
+	if days < 0 || rest < 0 {
+		return 0, fmt.Errorf("gibrun: negative duration %q", s)
+	}
+	return d, nil
+}
+
+// Duration is a time.Duration that decodes from config files as either
+// a duration string ("15m", "7d") or a number of nanoseconds. Use it in
+// your own config structs; gibrun's config types already accept strings
+// for their durations when decoded from JSON.
+type Duration time.Duration
+
+// Std returns the value as a time.Duration.
+func (d Duration) Std() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats the duration like time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText encodes the duration as a string such as "15m0s".
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText decodes a duration string with ParseTTL. It is used by
+// YAML and env decoders that support encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := ParseTTL(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// UnmarshalJSON decodes a duration string or a number of nanoseconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(s))
+	}
+	var n int64
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("gibrun: duration must be a string like \"15m\" or nanoseconds: %w", err)
+	}
+	if n < 0 {
+		return fmt.Errorf("gibrun: negative duration %d", n)
+	}
+	*d = Duration(n)
+	return nil
+}
+
+// UnmarshalJSON accepts duration strings such as "30m" for the policy
+// durations.
+func (p *TTLPolicy) UnmarshalJSON(data []byte) error {
+	type plain TTLPolicy
+	var raw struct {
+		*plain
+		Default Duration
+		Max     Duration
+		Jitter  Duration
+	}
+	raw.plain = (*plain)(p)
+	raw.Default, raw.Max, raw.Jitter = Duration(p.Default), Duration(p.Max), Duration(p.Jitter)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.Default, p.Max, p.Jitter = raw.Default.Std(), raw.Max.Std(), raw.Jitter.Std()
+	return nil
+}
+
+// UnmarshalJSON accepts a duration string such as "1m" for Window.
+func (c *RateLimitConfig) UnmarshalJSON(data []byte) error {
+	type plain RateLimitConfig
+	var raw struct {
+		*plain
+		Window Duration
+	}
+	raw.plain = (*plain)(c)
+	raw.Window = Duration(c.Window)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.Window = raw.Window.Std()
+	return nil
+}
+
+// UnmarshalJSON accepts a duration string such as "50ms" for Threshold.
+func (c *DegradationConfig) UnmarshalJSON(data []byte) error {
+	type plain DegradationConfig
+	var raw struct {
+		*plain
+		Threshold Duration
+	}
+	raw.plain = (*plain)(c)
+	raw.Threshold = Duration(c.Threshold)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.Threshold = raw.Threshold.Std()
+	return nil
+}
+
+// UnmarshalJSON accepts duration strings for MaxLag and HealthInterval.
+func (c *ReplicatedConfig) UnmarshalJSON(data []byte) error {
+	type plain ReplicatedConfig
+	var raw struct {
+		*plain
+		MaxLag         Duration
+		HealthInterval Duration
+	}
+	raw.plain = (*plain)(c)
+	raw.MaxLag, raw.HealthInterval = Duration(c.MaxLag), Duration(c.HealthInterval)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.MaxLag, c.HealthInterval = raw.MaxLag.Std(), raw.HealthInterval.Std()
+	return nil
+}
+
+// UnmarshalJSON accepts a duration string such as "100ms" for Backoff.
+func (c *AsyncConfig) UnmarshalJSON(data []byte) error {
+	type plain AsyncConfig
+	var raw struct {
+		*plain
+		Backoff Duration
+	}
+	raw.plain = (*plain)(c)
+	raw.Backoff = Duration(c.Backoff)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	c.Backoff = raw.Backoff.Std()
+	return nil
+}
+
+// UnmarshalJSON accepts duration strings for StaleAfter and TTL.
+func (o *ProgressOptions) UnmarshalJSON(data []byte) error {
+	type plain ProgressOptions
+	var raw struct {
+		*plain
+		StaleAfter Duration
+		TTL        Duration
+	}
+	raw.plain = (*plain)(o)
+	raw.StaleAfter, raw.TTL = Duration(o.StaleAfter), Duration(o.TTL)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	o.StaleAfter, o.TTL = raw.StaleAfter.Std(), raw.TTL.Std()
+	return nil
+}
@@ -0,0 +1,122 @@
+package gibrun
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+)
+
+// KeyProvider supplies AES keys for at-rest encryption.
+// Keys must be 16, 24 or 32 bytes (AES-128/192/256).
+// Each stored value records the ID of the key that sealed it, so keys
+// can be rotated without re-encrypting existing data.
+type KeyProvider interface {
+	// CurrentKey returns the key ID and key used for new writes.
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key for an ID found in a stored value.
+	Key(id string) ([]byte, error)
+}
+
+// StaticKeys is a KeyProvider backed by an in-memory key ring.
+//
+// Example:
+//
+//	app := gibrun.New(gibrun.Config{
+//	    Addr: "localhost:6379",
+//	    Encryption: gibrun.StaticKeys{
+//	        Current: "2024-06",
+//	        Keys: map[string][]byte{
+//	            "2024-01": oldKey,
+//	            "2024-06": newKey,
+//	        },
+//	    },
+//	})
+type StaticKeys struct {
+	// Current is the ID of the key used for new writes.
+	Current string
+
+	// Keys maps key IDs to raw AES keys.
+	Keys map[string][]byte
+}
+
+// CurrentKey returns the current key.
+func (s StaticKeys) CurrentKey() (string, []byte, error) {
+	key, err := s.Key(s.Current)
+	return s.Current, key, err
+}
+
+// Key returns the key with the given ID.
+func (s StaticKeys) Key(id string) ([]byte, error) {
+	key, ok := s.Keys[id]
+	if !ok {
+		return nil, fmt.Errorf("gibrun: unknown encryption key %q", id)
+	}
+	return key, nil
+}
+
+// seal encrypts data with the provider's current key.
+// Layout: envelope header | key ID length | key ID | nonce | ciphertext.
+func seal(keys KeyProvider, data []byte) ([]byte, error) {
+	id, key, err := keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > 255 {
+		return nil, fmt.Errorf("gibrun: encryption key ID %q too long", id)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, envelopeHeaderLen+1+len(id)+len(nonce)+len(data)+gcm.Overhead())
+	out = append(out, envelopeMagic...)
+	out = append(out, envelopeEncrypted, byte(len(id)))
+	out = append(out, id...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, nil), nil
+}
+
+// unseal decrypts an encrypted envelope, looking up the key by its recorded ID.
+func unseal(keys KeyProvider, data []byte) ([]byte, error) {
+	body := data[envelopeHeaderLen:]
+	if len(body) < 1 || len(body) < 1+int(body[0]) {
+		return nil, fmt.Errorf("%w: truncated encrypted envelope", ErrCorruptValue)
+	}
+	id := string(body[1 : 1+int(body[0])])
+	body = body[1+int(body[0]):]
+
+	key, err := keys.Key(id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: key %q: %w", ErrDecryptionKeyMissing, id, err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < gcm.NonceSize() {
+		return nil, fmt.Errorf("%w: truncated encrypted envelope", ErrCorruptValue)
+	}
+
+	plain, err := gcm.Open(nil, body[:gcm.NonceSize()], body[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: decryption failed with key %q", ErrCorruptValue, id)
+	}
+	return plain, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("gibrun: invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
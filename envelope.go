@@ -0,0 +1,30 @@
+package gibrun
+
+import "bytes"
+
+// Stored values may be wrapped in gibrun envelopes. Every envelope starts
+// with envelopeMagic followed by a kind byte; plain JSON and strings never
+// start with a NUL byte, so wrapped and unwrapped values can be told apart.
+var envelopeMagic = []byte("\x00GB")
+
+// envelopeHeaderLen is the length of the magic plus the kind byte.
+const envelopeHeaderLen = 4
+
+// Envelope kinds.
+const (
+	envelopeEncrypted byte = 'E'
+	envelopeVersioned byte = 'V'
+	envelopeChunked   byte = 'M'
+	envelopeSchema    byte = 'S'
+	envelopeChecksum  byte = 'C'
+	envelopeStamped   byte = 'T'
+	envelopeNegative  byte = 'N'
+)
+
+// envelopeKind returns the envelope kind of data, or 0 if it is not wrapped.
+func envelopeKind(data []byte) byte {
+	if len(data) < envelopeHeaderLen || !bytes.HasPrefix(data, envelopeMagic) {
+		return 0
+	}
+	return data[len(envelopeMagic)]
+}
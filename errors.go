@@ -1,6 +1,9 @@
 package gibrun
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 // Standard errors for gibrun operations.
 var (
@@ -9,4 +12,99 @@ var (
 
 	// ErrNilPointer is returned when attempting to bind to a nil pointer.
 	ErrNilPointer = errors.New("gibrun: cannot bind to nil pointer")
+
+	// ErrUnsupported is returned when the server lacks a capability an operation needs.
+	ErrUnsupported = errors.New("gibrun: operation not supported by server")
+
+	// ErrProtoNotConfigured is returned when ProtoCodec meets a protobuf message
+	// but has no MarshalFunc/UnmarshalFunc and the type has no fast-path methods.
+	ErrProtoNotConfigured = errors.New("gibrun: ProtoCodec needs MarshalFunc and UnmarshalFunc for protobuf messages")
+
+	// ErrCorruptValue is returned when a stored value fails integrity checks.
+	ErrCorruptValue = errors.New("gibrun: corrupt value")
+
+	// ErrVersionConflict is returned by IfVersion writes when another writer
+	// updated the key first.
+	ErrVersionConflict = errors.New("gibrun: version conflict")
+
+	// ErrTxConflict is returned when a transaction keeps losing WATCH races
+	// after all retries.
+	ErrTxConflict = errors.New("gibrun: transaction conflict, watched keys kept changing")
+
+	// ErrAsyncQueueFull is returned by ExecAsync when the background
+	// writer can't keep up.
+	ErrAsyncQueueFull = errors.New("gibrun: async write queue is full")
+
+	// ErrPollClosed is returned when voting after a poll's deadline.
+	ErrPollClosed = errors.New("gibrun: poll is closed")
+
+	// ErrUnknownOption is returned when voting for an option the poll
+	// doesn't offer.
+	ErrUnknownOption = errors.New("gibrun: unknown poll option")
+
+	// ErrSchemaUpgrade is returned when a stored payload's schema version
+	// has no registered upgrade path to the current version.
+	ErrSchemaUpgrade = errors.New("gibrun: missing schema upgrade step")
+
+	// ErrWriteThrottled is returned when the write guard rejects a write
+	// to a key written at a pathological rate.
+	ErrWriteThrottled = errors.New("gibrun: write throttled, key is written too often")
+
+	// ErrNotFound can be returned by OrElse loaders to report that the
+	// entity doesn't exist, see RunBuilder.WithNegativeCache.
+	ErrNotFound = errors.New("gibrun: not found")
+
+	// ErrValidation wraps errors returned by client validators.
+	ErrValidation = errors.New("gibrun: validation failed")
+
+	// ErrDecryptionKeyMissing is returned when reading an encrypted value
+	// without Encryption configured, or when the KeyProvider doesn't know
+	// the key ID the value was sealed with.
+	ErrDecryptionKeyMissing = errors.New("gibrun: decryption key missing")
+
+	// ErrAppendEncrypted is returned when Append is used on a client with encryption.
+	ErrAppendEncrypted = errors.New("gibrun: append cannot be used with encryption")
+
+	// ErrShadowDropped is reported to ShadowWriteConfig.OnError when a mirrored
+	// write is dropped because MaxInFlight was reached.
+	ErrShadowDropped = errors.New("gibrun: shadow write dropped, too many in flight")
+
+	// ErrInvalidBitOp is returned by BitOp for an unknown BitOperation.
+	ErrInvalidBitOp = errors.New("gibrun: invalid bit operation")
+
+	// ErrBitfieldOverflow is returned by Bitfield Exec when an operation
+	// was skipped under OverflowFail.
+	ErrBitfieldOverflow = errors.New("gibrun: bitfield overflow")
+
+	// ErrSubscriptionOverflow is reported to SubscribeOptions.OnError when
+	// a message is dropped because the subscription buffer is full.
+	ErrSubscriptionOverflow = errors.New("gibrun: subscription buffer full, message dropped")
+
+	// ErrKeyspaceEventsDisabled is returned by WatchExpirations when
+	// keyspace notifications are off and can't be enabled.
+	ErrKeyspaceEventsDisabled = errors.New("gibrun: keyspace notifications are disabled")
+
+	// ErrArchiveExists is returned by Podium ArchiveTo when the archive
+	// key is already taken.
+	ErrArchiveExists = errors.New("gibrun: archive key already exists")
 )
+
+// VersionError is returned when a feature needs a newer Redis server
+// than the one connected. It matches ErrUnsupported with errors.Is.
+type VersionError struct {
+	// Feature names the command or option that was requested.
+	Feature string
+	// Required is the minimum server version for the feature.
+	Required Version
+	// Actual is the connected server version.
+	Actual Version
+}
+
+func (e *VersionError) Error() string {
+	return fmt.Sprintf("gibrun: %s requires Redis >= %s (server is %s)", e.Feature, e.Required, e.Actual)
+}
+
+// Unwrap allows errors.Is(err, ErrUnsupported).
+func (e *VersionError) Unwrap() error {
+	return ErrUnsupported
+}
@@ -0,0 +1,220 @@
+package gibrun
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// eventNameField is the entry field holding the event name; the payload
+// goes in streamField like Stream().Add.
+const eventNameField = "event"
+
+// replayBatch is how many events Replay reads at once.
+const replayBatch = 100
+
+// EventBusConfig configures an EventBus.
+type EventBusConfig struct {
+	// Stream is the Redis stream carrying the events. Required.
+	Stream string
+
+	// Service names the consumer group. Each service sees every event
+	// once, shared among its instances. Required for Run.
+	Service string
+
+	// Consumer names this instance within the service.
+	// Default is "<hostname>-<pid>".
+	Consumer string
+
+	// MaxLen caps the stream at about this many events. Zero keeps every
+	// event, which Replay needs to rebuild state from the start.
+	MaxLen int64
+
+	// Codec overrides the client codec for event payloads.
+	Codec Codec
+}
+
+// EventBus routes named events over a Redis stream to typed handlers,
+// see On. Delivery, retries and dead-lettering follow Stream().Consume.
+type EventBus struct {
+	client *Client
+	cfg    EventBusConfig
+	// err holds configuration problems found at construction.
+	err error
+
+	mu       sync.RWMutex
+	handlers map[string][]StreamHandler
+}
+
+// NewEventBus creates an event bus. An invalid configuration (e.g. a
+// missing Stream) is reported by every call; check it up front with Err.
+//
+// Example:
+//
+//	bus := gibrun.NewEventBus(app, gibrun.EventBusConfig{Stream: "events", Service: "mailer"})
+//	gibrun.On(bus, "user.created", func(ctx context.Context, u UserCreated) error {
+//	    return sendWelcome(ctx, u.Email)
+//	})
+//	go bus.Run(ctx)
+//
+//	// elsewhere
+//	id, err := bus.Emit(ctx, "user.created", UserCreated{ID: 42, Email: "a@example.com"})
+func NewEventBus(client *Client, cfg EventBusConfig) *EventBus {
+	err := cfg.Validate()
+	if cfg.Consumer == "" {
+		host, _ := os.Hostname()
+		cfg.Consumer = host + "-" + strconv.Itoa(os.Getpid())
+	}
+	return &EventBus{
+		client:   client,
+		cfg:      cfg,
+		err:      err,
+		handlers: make(map[string][]StreamHandler),
+	}
+}
+
+// Err returns the configuration error found at construction, if any.
+func (b *EventBus) Err() error {
+	return b.err
+}
+
+// On registers handler for events named name, decoding each payload
+// into a T. Several handlers may share a name; they run in registration
+// order and the event is retried as a whole if any of them fails, so
+// handlers should be idempotent. Events without a handler are
+// acknowledged and skipped.
+func On[T any](bus *EventBus, name string, handler func(ctx context.Context, event T) error) {
+	bus.mu.Lock()
+	defer bus.mu.Unlock()
+	bus.handlers[name] = append(bus.handlers[name], func(ctx context.Context, msg StreamMessage) error {
+		var event T
+		if err := msg.Bind(&event); err != nil {
+			return err
+		}
+		return handler(ctx, event)
+	})
+}
+
+// Emit publishes an event, marshalled exactly like Gib, and returns its
+// stream ID.
+func (b *EventBus) Emit(ctx context.Context, name string, payload any) (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if payload == nil {
+		return "", ErrNilValue
+	}
+	data, err := b.client.enc.marshal(payload, b.cfg.Codec)
+	if err != nil {
+		return "", err
+	}
+	args := &redis.XAddArgs{
+		Stream: b.cfg.Stream,
+		Values: []any{eventNameField, name, streamField, data},
+	}
+	if b.cfg.MaxLen > 0 {
+		args.MaxLen, args.Approx = b.cfg.MaxLen, true
+	}
+	return b.client.rdb.XAdd(ctx, args).Result()
+}
+
+// Run consumes events as the Service consumer group until ctx is
+// canceled, then returns nil. A new service starts at the oldest event
+// still in the stream.
+func (b *EventBus) Run(ctx context.Context) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.cfg.Service == "" {
+		return &ConfigError{Field: "Service", Problem: "is required to consume"}
+	}
+	return b.stream(ctx).Consume(b.cfg.Service, b.cfg.Consumer, b.dispatch)
+}
+
+// Replay runs the registered handlers on every event from fromID
+// (inclusive, "" for the start of the stream) up to the newest, in
+// order and outside the consumer group, for rebuilding projections.
+// It stops at the first handler error. Returns the ID of the last event
+// handled, to resume from later.
+//
+// Example:
+//
+//	last, err := bus.Replay(ctx, snapshot.EventID)
+func (b *EventBus) Replay(ctx context.Context, fromID string) (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	start := fromID
+	if start == "" {
+		start = "-"
+	}
+
+	var last string
+	s := b.stream(ctx)
+	for {
+		entries, err := b.client.rdb.XRangeN(ctx, b.cfg.Stream, start, "+", replayBatch).Result()
+		if err != nil {
+			return last, err
+		}
+		if len(entries) == 0 {
+			return last, nil
+		}
+		for _, e := range entries {
+			if err := b.dispatch(ctx, s.message(e, 1)); err != nil {
+				return last, err
+			}
+			last = e.ID
+		}
+		start = nextStreamID(last)
+	}
+}
+
+// Rewind moves the Service consumer group back to id ("0" for the start
+// of the stream), so running instances receive the events after it again.
+func (b *EventBus) Rewind(ctx context.Context, id string) error {
+	if b.err != nil {
+		return b.err
+	}
+	if b.cfg.Service == "" {
+		return &ConfigError{Field: "Service", Problem: "is required to consume"}
+	}
+	if err := b.stream(ctx).CreateGroup(b.cfg.Service, id); err != nil {
+		return err
+	}
+	return b.client.rdb.XGroupSetID(ctx, b.cfg.Stream, b.cfg.Service, id).Err()
+}
+
+// dispatch runs the handlers registered for msg's event name.
+func (b *EventBus) dispatch(ctx context.Context, msg StreamMessage) error {
+	name, _ := msg.values[eventNameField].(string)
+	b.mu.RLock()
+	handlers := b.handlers[name]
+	b.mu.RUnlock()
+
+	for _, h := range handlers {
+		if err := h(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// stream returns the StreamBuilder the bus consumes with.
+func (b *EventBus) stream(ctx context.Context) *StreamBuilder {
+	return b.client.Stream(ctx, b.cfg.Stream).Codec(b.cfg.Codec)
+}
+
+// nextStreamID returns the smallest stream ID after id, for exclusive
+// ranges on servers without "(" (Redis < 6.2).
+func nextStreamID(id string) string {
+	ms, seq, _ := strings.Cut(id, "-")
+	n, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return id
+	}
+	return ms + "-" + strconv.FormatUint(n+1, 10)
+}
@@ -0,0 +1,254 @@
+package gibrun
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Failure is a recorded background-processing failure.
+type Failure struct {
+	// ID uniquely identifies the record.
+	ID string `json:"id"`
+
+	// Source names the subsystem, e.g. "migration", "queue:emails".
+	Source string `json:"source"`
+
+	// Key is the affected key, job or message ID.
+	Key string `json:"key"`
+
+	// Class groups errors: "timeout", "network", "canceled", "redis",
+	// "codec" or "other".
+	Class string `json:"class"`
+
+	// Error is the error message.
+	Error string `json:"error"`
+
+	// Payload holds the failed payload (truncated to MaxPayload) for retries.
+	Payload []byte `json:"payload,omitempty"`
+
+	// At is when the failure was recorded.
+	At time.Time `json:"at"`
+}
+
+// Preview returns the first n bytes of the payload as text.
+func (f Failure) Preview(n int) string {
+	if len(f.Payload) <= n {
+		return string(f.Payload)
+	}
+	return string(f.Payload[:n]) + "..."
+}
+
+// FailureLog is a Redis-backed store of recent failures across queue,
+// streams and migration - one place to triage background problems.
+type FailureLog struct {
+	client *Client
+	// MaxPerSource bounds the records kept per source. Default is 1000.
+	MaxPerSource int64
+	// MaxPayload bounds the stored payload size. Default is 64KB.
+	MaxPayload int
+}
+
+// failuresPrefix namespaces the failure keys.
+const failuresPrefix = "gibrun:failures:"
+
+// Failures returns the failure log of this client. Every call returns
+// the same log, so limits set on it apply to all recorders, including
+// Kerja, Outbox and stream consumers; set them before starting those.
+//
+// Example:
+//
+//	app.Failures().MaxPerSource = 200
+//	counts, _ := app.Failures().CountByClass(ctx, "migration")
+//	recent, _ := app.Failures().List(ctx, "migration", 20)
+//	for _, f := range recent {
+//	    log.Printf("%s %s: %s (%s)", f.At, f.Key, f.Error, f.Preview(80))
+//	}
+func (c *Client) Failures() *FailureLog {
+	return c.failures
+}
+
+// Record stores a failure for source. A nil cause is recorded as
+// "unknown".
+func (l *FailureLog) Record(ctx context.Context, source, key string, payload []byte, cause error) error {
+	msg := "unknown"
+	if cause != nil {
+		msg = cause.Error()
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	if len(payload) > l.MaxPayload {
+		payload = payload[:l.MaxPayload]
+	}
+
+	f := Failure{
+		ID:      hex.EncodeToString(id),
+		Source:  source,
+		Key:     key,
+		Class:   classifyError(cause),
+		Error:   msg,
+		Payload: payload,
+		At:      l.client.clock.Now().UTC(),
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+
+	listKey := failuresPrefix + source
+	_, err = l.client.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, listKey, data)
+		pipe.LTrim(ctx, listKey, 0, l.MaxPerSource-1)
+		pipe.SAdd(ctx, failuresPrefix+"sources", source)
+		return nil
+	})
+	return err
+}
+
+// Sources lists every source that has recorded failures.
+func (l *FailureLog) Sources(ctx context.Context) ([]string, error) {
+	return l.client.rdb.SMembers(ctx, failuresPrefix+"sources").Result()
+}
+
+// List returns up to limit of the most recent failures for source.
+// A limit <= 0 returns all records.
+func (l *FailureLog) List(ctx context.Context, source string, limit int) ([]Failure, error) {
+	stop := int64(limit) - 1
+	if limit <= 0 {
+		stop = -1
+	}
+	raw, err := l.client.rdb.LRange(ctx, failuresPrefix+source, 0, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	failures := make([]Failure, 0, len(raw))
+	for _, item := range raw {
+		var f Failure
+		if err := json.Unmarshal([]byte(item), &f); err != nil {
+			continue
+		}
+		failures = append(failures, f)
+	}
+	return failures, nil
+}
+
+// CountByClass counts the recorded failures of source by error class.
+func (l *FailureLog) CountByClass(ctx context.Context, source string) (map[string]int, error) {
+	failures, err := l.List(ctx, source, 0)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, f := range failures {
+		counts[f.Class]++
+	}
+	return counts, nil
+}
+
+// Retry calls fn for every failure of source and removes the ones that
+// succeed. Returns the number of records retried successfully.
+//
+// Example:
+//
+//	n, err := app.Failures().Retry(ctx, "queue:emails", func(ctx context.Context, f gibrun.Failure) error {
+//	    return sendEmail(ctx, f.Payload)
+//	})
+func (l *FailureLog) Retry(ctx context.Context, source string, fn func(ctx context.Context, f Failure) error) (int, error) {
+	failures, err := l.List(ctx, source, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	var retried []string
+	for _, f := range failures {
+		if err := ctx.Err(); err != nil {
+			break
+		}
+		if fn(ctx, f) == nil {
+			retried = append(retried, f.ID)
+		}
+	}
+	if len(retried) == 0 {
+		return 0, ctx.Err()
+	}
+
+	_, err = l.Purge(ctx, source, retried...)
+	return len(retried), err
+}
+
+// Purge removes failures of source. With no IDs, all records are removed.
+// Returns the number of records removed.
+func (l *FailureLog) Purge(ctx context.Context, source string, ids ...string) (int, error) {
+	listKey := failuresPrefix + source
+
+	if len(ids) == 0 {
+		n, err := l.client.rdb.LLen(ctx, listKey).Result()
+		if err != nil {
+			return 0, err
+		}
+		if err := l.client.rdb.Del(ctx, listKey).Err(); err != nil {
+			return 0, err
+		}
+		return int(n), l.client.rdb.SRem(ctx, failuresPrefix+"sources", source).Err()
+	}
+
+	want := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		want[id] = true
+	}
+
+	raw, err := l.client.rdb.LRange(ctx, listKey, 0, -1).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, item := range raw {
+		var f Failure
+		if json.Unmarshal([]byte(item), &f) != nil || !want[f.ID] {
+			continue
+		}
+		n, err := l.client.rdb.LRem(ctx, listKey, 1, item).Result()
+		if err != nil {
+			return removed, err
+		}
+		removed += int(n)
+	}
+	return removed, nil
+}
+
+// classifyError groups an error into a coarse class for triage.
+func classifyError(err error) string {
+	var netErr net.Error
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.Is(err, ErrCorruptValue):
+		return "codec"
+	}
+
+	var redisErr redis.Error
+	if errors.As(err, &redisErr) {
+		return "redis"
+	}
+	return "other"
+}
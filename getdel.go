@@ -0,0 +1,79 @@
+package gibrun
+
+import (
+	"github.com/redis/go-redis/v9"
+)
+
+// BindAndDelete reads the value into dest and deletes the key in the same
+// atomic step, so one-shot tokens (verification codes, one-time download
+// links) can never be read twice. Uses GETDEL on Redis 6.2+ and GET plus
+// DEL in MULTI on older servers.
+// Returns (false, nil) if the key doesn't exist.
+//
+// Example:
+//
+//	var code VerifyCode
+//	found, err := app.Run(ctx, "verify:"+token).BindAndDelete(&code)
+//	if !found {
+//	    // expired or already used
+//	}
+func (b *RunBuilder) BindAndDelete(dest any) (bool, error) {
+	if dest == nil {
+		return false, ErrNilPointer
+	}
+	b.toWriter()
+
+	var data []byte
+	var err error
+	if b.client.requireVersion(b.ctx, "GETDEL", Version{Major: 6, Minor: 2}) == nil {
+		data, err = b.client.rdb.GetDel(b.ctx, b.key).Bytes()
+	} else {
+		var get *redis.StringCmd
+		_, err = b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			get = pipe.Get(b.ctx, b.key)
+			pipe.Del(b.ctx, b.key)
+			return nil
+		})
+		if err != nil && err != redis.Nil {
+			return false, err
+		}
+		data, err = get.Bytes()
+	}
+
+	if err == nil && envelopeKind(data) == envelopeChunked {
+		data, err = b.takeChunks(data)
+	}
+	if err == nil && isNegative(data) {
+		err = redis.Nil
+	}
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if err := b.client.enc.unmarshal(data, dest, b.codec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// takeChunks reassembles a chunked value whose manifest was already
+// removed, then deletes the chunks.
+func (b *RunBuilder) takeChunks(manifest []byte) ([]byte, error) {
+	count, _, err := parseManifest(b.key, manifest)
+	if err != nil {
+		return nil, err
+	}
+	data, err := b.client.reassemble(b.ctx, b.key, manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, count)
+	for i := range keys {
+		keys[i] = chunkKey(b.key, i)
+	}
+	return data, b.client.rdb.Del(b.ctx, keys...).Err()
+}
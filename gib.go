@@ -2,8 +2,10 @@ package gibrun
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // GibBuilder provides a fluent API for storing data in Redis.
@@ -14,6 +16,51 @@ type GibBuilder struct {
 	key    string
 	value  any
 	ttl    time.Duration
+	codec  Codec
+
+	// ifVersion enables compare-and-set when non-nil.
+	ifVersion *int64
+
+	// asHash stores the value as a Redis hash.
+	asHash bool
+
+	// appendMode appends to the existing string instead of replacing it.
+	appendMode bool
+
+	// asJSON stores the value as a RedisJSON document at jsonPath.
+	asJSON   bool
+	jsonPath string
+
+	// persist and policy drive write-through to a backing store.
+	persist func(ctx context.Context) error
+	policy  WritePolicy
+
+	// tags registers the key in tag sets for group invalidation.
+	tags []string
+
+	// expireAt sets an absolute expiry, overriding ttl when non-zero.
+	expireAt time.Time
+
+	// ifNotExists only writes when the key is absent (SET NX); acquired
+	// records whether the write happened.
+	ifNotExists bool
+	acquired    bool
+
+	// dedupToken skips the write if the token was recently applied.
+	dedupToken  string
+	dedupWindow time.Duration
+
+	// retry retries transient write errors, see Retry.
+	retry retryPolicy
+
+	// negative stores the negative-cache marker, see NotFound.
+	negative bool
+
+	// schema names the stored schema, see Schema.
+	schema string
+
+	// detail collects write details for ExecDetail.
+	detail *GibResult
 }
 
 // Value sets the data to be stored.
@@ -29,7 +76,8 @@ func (b *GibBuilder) Value(v any) *GibBuilder {
 }
 
 // TTL sets the time-to-live for the cached data.
-// If not called, the data will persist indefinitely.
+// If not called, the data will persist indefinitely unless a
+// TTLPolicy on the client provides a default.
 //
 // Example:
 //
@@ -39,6 +87,30 @@ func (b *GibBuilder) TTL(d time.Duration) *GibBuilder {
 	return b
 }
 
+// ExpireAt makes the data expire at a wall-clock moment, such as the end
+// of the day or a token's expiry timestamp. It overrides TTL; TTL policy
+// limits still apply. A moment already in the past deletes the key; for
+// conditional writes (IfVersion, IfNotExists, DedupToken, Append, AsHash,
+// AsJSON, WriteThrough) it returns ErrUnsupported instead.
+//
+// Example:
+//
+//	app.Gib(ctx, "token:abc").Value(tok).ExpireAt(tok.ExpiresAt).Exec()
+func (b *GibBuilder) ExpireAt(t time.Time) *GibBuilder {
+	b.expireAt = t
+	return b
+}
+
+// Codec overrides the client codec for this operation.
+//
+// Example:
+//
+//	app.Gib(ctx, "blob").Value(v).Codec(msgpackCodec{}).Exec()
+func (b *GibBuilder) Codec(c Codec) *GibBuilder {
+	b.codec = c
+	return b
+}
+
 // Exec executes the storage operation.
 // This is where the "downstreaming" happens - raw data gets transformed
 // and stored in Redis.
@@ -47,33 +119,266 @@ func (b *GibBuilder) TTL(d time.Duration) *GibBuilder {
 //
 //	err := app.Gib(ctx, "key").Value(data).TTL(5*time.Minute).Exec()
 func (b *GibBuilder) Exec() error {
+	start := time.Now()
+	err := b.retry.do(b.ctx, b.client.clock, b.exec)
+	b.client.stats.write(b.key, err, time.Since(start))
+	return err
+}
+
+// exec runs Exec without recording stats.
+func (b *GibBuilder) exec() error {
 	if b.value == nil {
 		return ErrNilValue
 	}
+	if err := b.client.validate(b.key, b.value); err != nil {
+		return err
+	}
+	if err := b.client.guardWrite(b.key); err != nil {
+		return err
+	}
+	if !b.expireAt.IsZero() {
+		b.ttl = b.expireAt.Sub(b.client.clock.Now())
+		if b.ttl <= 0 {
+			if b.conditional() {
+				return fmt.Errorf("%w: ExpireAt in the past only applies to plain writes, not IfVersion, IfNotExists, DedupToken, Append, AsHash, AsJSON or WriteThrough", ErrUnsupported)
+			}
+			return b.client.Del(b.ctx, b.key)
+		}
+	}
+
+	var err error
+	if b.persist != nil {
+		err = b.execWriteThrough()
+	} else {
+		err = b.store()
+	}
+	if err != nil {
+		return err
+	}
+	if b.ifNotExists && !b.acquired {
+		return nil
+	}
+	return b.registerTags()
+}
+
+// conditional reports whether the write depends on stored state or
+// side effects that a plain DEL would skip.
+func (b *GibBuilder) conditional() bool {
+	return b.ifVersion != nil || b.ifNotExists || b.dedupToken != "" ||
+		b.appendMode || b.asHash || b.asJSON || b.persist != nil
+}
 
-	// Auto-downstreaming: marshal struct to JSON
-	data, err := b.marshal(b.value)
+// store writes the value to Redis according to the builder mode.
+func (b *GibBuilder) store() error {
+	if b.ifNotExists && (b.asHash || b.appendMode || b.ifVersion != nil) {
+		return fmt.Errorf("%w: IfNotExists can't be combined with AsHash, Append or IfVersion", ErrUnsupported)
+	}
+	if b.asJSON {
+		return b.execJSON()
+	}
+	if b.asHash {
+		if err := b.noteExisting(); err != nil {
+			return err
+		}
+		return b.execHash()
+	}
+	if b.appendMode {
+		if err := b.noteExisting(); err != nil {
+			return err
+		}
+		return b.execAppend()
+	}
+
+	// The marker is stored bare so every client recognizes it
+	if b.negative {
+		return b.set(negativeMarker, b.client.ttl.apply(b.key, b.ttl))
+	}
+
+	// Auto-downstreaming: marshal struct via the codec
+	data, err := b.client.enc.marshalSchema(b.value, b.codec, b.schema)
 	if err != nil {
 		return err
 	}
 
-	// Store in Redis with optional TTL
-	if b.ttl > 0 {
-		return b.client.rdb.Set(b.ctx, b.key, data, b.ttl).Err()
+	// Store in Redis with optional TTL, shaped by the client TTL policies
+	ttl := b.client.ttl.apply(b.key, b.ttl)
+	if b.detail != nil {
+		b.detail.Size = len(data)
+		b.detail.TTL = ttl
+	}
+
+	if b.dedupToken != "" {
+		written, err := b.execDedup(data, ttl)
+		if err != nil || !written {
+			return err
+		}
+		b.client.mirrorWrite(b.key, data, ttl)
+		return nil
+	}
+	if b.ifVersion != nil {
+		if err := b.noteExisting(); err != nil {
+			return err
+		}
+		return b.execVersioned(data)
+	}
+	if b.client.chunkSize > 0 && len(data) > b.client.chunkSize && !b.ifNotExists {
+		if err := b.noteExisting(); err != nil {
+			return err
+		}
+		return b.execChunked(data, ttl)
+	}
+	if err := b.set(data, ttl); err != nil {
+		return err
+	}
+	if b.ifNotExists && !b.acquired {
+		return nil
+	}
+
+	if b.client.l1 != nil {
+		b.client.l1.set(b.key, data, ttl)
+	}
+	b.client.mirrorWrite(b.key, data, ttl)
+	return nil
+}
+
+// Append appends data to the existing string value instead of replacing it,
+// creating the key if it doesn't exist. Useful for log-style or
+// token-accumulation keys without read-modify-write cycles.
+// Appended data is stored raw, so it can't be combined with encryption
+// and isn't covered by Config.Checksum.
+//
+// Example:
+//
+//	err := app.Gib(ctx, "chat:42:tokens").Append(token).TTL(time.Hour).Exec()
+func (b *GibBuilder) Append(data string) *GibBuilder {
+	b.value = data
+	b.appendMode = true
+	return b
+}
+
+// execAppend runs APPEND, refreshing the TTL if one is set.
+func (b *GibBuilder) execAppend() error {
+	if b.client.enc.keys != nil {
+		return ErrAppendEncrypted
+	}
+
+	ttl := b.client.ttl.apply(b.key, b.ttl)
+	if b.detail != nil {
+		b.detail.Size = len(b.value.(string))
+		b.detail.TTL = ttl
+	}
+	_, err := b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		pipe.Append(b.ctx, b.key, b.value.(string))
+		if ttl > 0 {
+			pipe.PExpire(b.ctx, b.key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// ExecGetOld stores the new value and binds the previous one into dest,
+// atomically in a single SET ... GET round trip. This enables swap patterns
+// without a race between Run and Gib.
+// Returns (true, nil) if an old value existed and was bound.
+// Returns (false, nil) if the key didn't exist before.
+//
+// Example:
+//
+//	var prev Session
+//	existed, err := app.Gib(ctx, "session:123").Value(next).ExecGetOld(&prev)
+func (b *GibBuilder) ExecGetOld(dest any) (bool, error) {
+	if dest == nil {
+		return false, ErrNilPointer
+	}
+
+	old, found, err := b.execGetOld()
+	if err != nil || !found {
+		return false, err
+	}
+
+	if err := b.client.enc.unmarshal(old, dest, b.codec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ExecGetOldBytes is like ExecGetOld but returns the previous raw bytes.
+// Returns (value, true, nil) if an old value existed, (nil, false, nil) if not.
+func (b *GibBuilder) ExecGetOldBytes() ([]byte, bool, error) {
+	old, found, err := b.execGetOld()
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	old, err = b.client.enc.open(old)
+	if err != nil {
+		return nil, false, err
+	}
+	return old, true, nil
+}
+
+// execGetOld runs SET ... GET, returning the previous stored bytes.
+func (b *GibBuilder) execGetOld() ([]byte, bool, error) {
+	if b.value == nil {
+		return nil, false, ErrNilValue
+	}
+	if err := b.client.validate(b.key, b.value); err != nil {
+		return nil, false, err
+	}
+	if err := b.client.guardWrite(b.key); err != nil {
+		return nil, false, err
+	}
+
+	if !b.expireAt.IsZero() {
+		b.ttl = b.expireAt.Sub(b.client.clock.Now())
+		if b.ttl <= 0 {
+			b.ttl = time.Millisecond
+		}
+	}
+
+	// SET ... GET needs Redis 6.2+
+	if err := b.client.requireVersion(b.ctx, "SET GET", Version{Major: 6, Minor: 2}); err != nil {
+		return nil, false, err
+	}
+
+	data, err := b.client.enc.marshalSchema(b.value, b.codec, b.schema)
+	if err != nil {
+		return nil, false, err
+	}
+
+	ttl := b.client.ttl.apply(b.key, b.ttl)
+	old, err := b.client.rdb.SetArgs(b.ctx, b.key, data, redis.SetArgs{
+		TTL: ttl,
+		Get: true,
+	}).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			b.client.mirrorWrite(b.key, data, ttl)
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+
+	b.client.mirrorWrite(b.key, data, ttl)
+	// A negative-cache marker means the entity didn't exist
+	if isNegative(old) {
+		return nil, false, nil
 	}
-	return b.client.rdb.Set(b.ctx, b.key, data, 0).Err()
+	return old, true, nil
 }
 
 // marshal converts the value to a storable format.
-// Supports automatic JSON marshalling for complex types.
-func (b *GibBuilder) marshal(v any) ([]byte, error) {
+// Strings and byte slices are stored directly, everything else goes
+// through the codec.
+func marshal(v any, codec Codec) ([]byte, error) {
 	switch val := v.(type) {
 	case string:
 		return []byte(val), nil
 	case []byte:
 		return val, nil
 	default:
-		// Auto-downstreaming: marshal struct/slice/map to JSON
-		return json.Marshal(val)
+		// Auto-downstreaming: marshal struct/slice/map via the codec
+		return codec.Marshal(val)
 	}
 }
@@ -6,6 +6,8 @@ package gibrun
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -19,12 +21,110 @@ type Config struct {
 	Password string
 	// DB is the Redis database number to use.
 	DB int
+
+	// RequireVersion is the minimum Redis server version (e.g. "6.2").
+	// When set, Ping fails with a VersionError on older servers.
+	RequireVersion string
+
+	// Codec serializes non-string values. Default is JSONCodec.
+	Codec Codec
+
+	// Encryption enables AES-GCM encryption of stored values.
+	// Gib encrypts and Run decrypts transparently.
+	Encryption KeyProvider
+
+	// Checksum stores a CRC32 of every value written by Gib and verifies
+	// it on Run, returning ErrCorruptValue on mismatch instead of failing
+	// later with an unmarshal error. Values with checksums are verified
+	// even when this is off.
+	Checksum bool
+
+	// TTLPolicies applies default, maximum and jittered TTLs by key prefix.
+	TTLPolicies []TTLPolicy
+
+	// ChunkSize splits values larger than this many bytes across
+	// "key:chunk:N" keys, reassembled transparently by Run. Use it to stay
+	// under proxy or value-size limits (e.g. 512 * 1024). Zero disables chunking.
+	ChunkSize int
+
+	// Degradation enables rolling latency monitoring, see Client.Degraded.
+	Degradation *DegradationConfig
+
+	// Validators run before every write. See Client.AddValidator.
+	Validators []ValidateFunc
+
+	// Async configures the background writer used by ExecAsync.
+	// Defaults apply when nil.
+	Async *AsyncConfig
+
+	// Clock is the time source for client-side time math.
+	// Default is SystemClock.
+	Clock Clock
+
+	// WriteGuard flags (and optionally throttles) keys written at
+	// pathological rates.
+	WriteGuard *WriteGuardConfig
+
+	// TrackWriteTime stores the write time with every value, reported by
+	// Run().BindWithInfo as HitInfo.StoredAt. Adds 12 bytes per value.
+	TrackWriteTime bool
+
+	// L1 enables an in-process cache in front of Redis, invalidated
+	// across instances over pub/sub. See L1Config.
+	L1 *L1Config
 }
 
 // Client is the main gibrun client that wraps Redis operations
 // with an opinionated, developer-friendly API.
 type Client struct {
-	rdb *redis.Client
+	rdb   *redis.Client
+	clock Clock
+	enc   encoding
+	ttl   ttlPolicies
+
+	// chunkSize is Config.ChunkSize.
+	chunkSize int
+
+	// latency monitors p99 when Config.Degradation is set.
+	latency *latencyMonitor
+
+	// validators run before every write.
+	validatorsMu sync.RWMutex
+	validators   []ValidateFunc
+
+	// writeGuard is set when Config.WriteGuard is.
+	writeGuard *writeGuard
+
+	// l1 is the in-process cache, set when Config.L1 is.
+	l1 *l1Cache
+
+	// stats counts reads and writes per key prefix.
+	stats statsRecorder
+
+	// failures is the client FailureLog, see Failures.
+	failures *FailureLog
+
+	// flights deduplicates concurrent OrElse loads per key.
+	flights flightGroup
+
+	// async flushes ExecAsync writes, started on first use.
+	asyncCfg  AsyncConfig
+	asyncOnce sync.Once
+	async     *asyncWriter
+
+	// probed caches the ProbeServer result for capability checks.
+	probeMu sync.Mutex
+	probed  *ServerInfo
+
+	// minVersion is the parsed Config.RequireVersion.
+	minVersion    *Version
+	minVersionErr error
+
+	// shadowW mirrors writes when ShadowWrites is enabled.
+	shadowW atomic.Pointer[shadowWriter]
+
+	// shadowR compares reads when ShadowReads is enabled.
+	shadowR atomic.Pointer[shadowReader]
 }
 
 // New creates a new gibrun Client with the given configuration.
@@ -44,20 +144,90 @@ func New(cfg Config) *Client {
 		DB:       cfg.DB,
 	})
 
-	return &Client{
-		rdb: rdb,
+	c := &Client{
+		rdb:   rdb,
+		clock: cfg.Clock,
+		enc:   newEncoding(cfg.Codec, cfg.Encryption, cfg.Checksum),
+		ttl:   newTTLPolicies(cfg.TTLPolicies),
+
+		chunkSize:  cfg.ChunkSize,
+		validators: cfg.Validators,
+	}
+
+	if c.clock == nil {
+		c.clock = SystemClock()
+	}
+	c.failures = &FailureLog{client: c, MaxPerSource: 1000, MaxPayload: 64 * 1024}
+	if cfg.WriteGuard != nil {
+		c.writeGuard = newWriteGuard(*cfg.WriteGuard, c.clock)
+	}
+	c.enc.stamp = cfg.TrackWriteTime
+	c.enc.now = c.clock.Now
+
+	if cfg.Async != nil {
+		c.asyncCfg = *cfg.Async
+	}
+
+	if cfg.L1 != nil {
+		c.l1 = newL1Cache(*cfg.L1, c.clock)
+		rdb.AddHook(l1Hook{l1: c.l1, rdb: rdb})
+		c.l1.subscribe(rdb)
+	}
+
+	if cfg.Degradation != nil {
+		c.latency = newLatencyMonitor(*cfg.Degradation)
+		rdb.AddHook(c.latency)
+		if c.l1 != nil {
+			c.l1.degraded = c.Degraded
+		}
 	}
+
+	if cfg.RequireVersion != "" {
+		v, err := ParseVersion(cfg.RequireVersion)
+		if err != nil {
+			c.minVersionErr = err
+		} else {
+			c.minVersion = &v
+		}
+	}
+
+	return c
 }
 
 // Ping checks the connection to Redis.
 // Returns nil if the connection is healthy.
+// If Config.RequireVersion is set, also verifies the server version.
 func (c *Client) Ping(ctx context.Context) error {
-	return c.rdb.Ping(ctx).Err()
+	if c.minVersionErr != nil {
+		return c.minVersionErr
+	}
+	if err := c.rdb.Ping(ctx).Err(); err != nil {
+		return err
+	}
+	if c.minVersion != nil {
+		info, err := c.serverInfo(ctx)
+		if err != nil {
+			return err
+		}
+		if !info.Version.AtLeast(*c.minVersion) {
+			return &VersionError{Feature: "Config.RequireVersion", Required: *c.minVersion, Actual: info.Version}
+		}
+	}
+	return nil
 }
 
 // Close closes the Redis connection.
 // Always defer this after creating a client.
+// Pending ExecAsync writes are flushed first.
 func (c *Client) Close() error {
+	// Flush pending ExecAsync writes first
+	c.asyncOnce.Do(func() {})
+	if c.async != nil {
+		c.async.close()
+	}
+	if c.l1 != nil {
+		c.l1.close()
+	}
 	return c.rdb.Close()
 }
 
@@ -103,9 +273,31 @@ func (c *Client) Sprint(ctx context.Context, key string) *SprintBuilder {
 	}
 }
 
-// Del deletes one or more keys from Redis.
+// Del deletes one or more keys from Redis, along with the chunks of
+// chunked values.
 func (c *Client) Del(ctx context.Context, keys ...string) error {
-	return c.rdb.Del(ctx, keys...).Err()
+	if len(keys) == 0 {
+		return nil
+	}
+	if !c.scripting(ctx) {
+		return c.delFallback(ctx, keys)
+	}
+	return delScript.Run(ctx, c.rdb, keys, manifestPrefix()).Err()
+}
+
+// delFallback mirrors delScript with WATCH/MULTI.
+func (c *Client) delFallback(ctx context.Context, keys []string) error {
+	return c.watchRetry(ctx, func(tx *redis.Tx) error {
+		chunks, err := storedChunks(ctx, tx, keys, 0)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, append(chunks, keys...)...)
+			return nil
+		})
+		return err
+	}, keys...)
 }
 
 // Exists checks if a key exists in Redis.
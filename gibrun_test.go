@@ -2,10 +2,21 @@ package gibrun_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/arielfikru/gibrun"
+	"github.com/redis/go-redis/v9"
 )
 
 // TestConfig tests that configuration is properly applied
@@ -244,3 +255,4866 @@ func TestExists(t *testing.T) {
 
 	client.Del(ctx, key)
 }
+
+func TestGibExecGetOld(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:getold"
+	client.Del(ctx, key)
+
+	var old TestStruct
+	existed, err := client.Gib(ctx, key).Value(TestStruct{Name: "first", Value: 1}).ExecGetOld(&old)
+	if err != nil {
+		t.Fatalf("ExecGetOld failed: %v", err)
+	}
+	if existed {
+		t.Error("expected no previous value")
+	}
+
+	existed, err = client.Gib(ctx, key).Value(TestStruct{Name: "second", Value: 2}).ExecGetOld(&old)
+	if err != nil {
+		t.Fatalf("ExecGetOld failed: %v", err)
+	}
+	if !existed || old.Name != "first" {
+		t.Errorf("expected previous value first, got %+v", old)
+	}
+
+	// A negative-cache marker counts as no previous value
+	client.Gib(ctx, key).NotFound().TTL(time.Minute).Exec()
+	existed, err = client.Gib(ctx, key).Value(TestStruct{Name: "third", Value: 3}).ExecGetOld(&old)
+	if err != nil || existed {
+		t.Errorf("expected no previous value over a NotFound marker, got %v %v", existed, err)
+	}
+	client.Gib(ctx, key).NotFound().TTL(time.Minute).Exec()
+	if raw, existed, err := client.Gib(ctx, key).Value("fourth").ExecGetOldBytes(); err != nil || existed {
+		t.Errorf("expected no previous bytes over a NotFound marker, got %q %v %v", raw, existed, err)
+	}
+	if val, _, _ := client.Run(ctx, key).Raw(); val != "fourth" {
+		t.Errorf("expected the write over the marker, got %q", val)
+	}
+
+	client.Del(ctx, key)
+}
+
+func TestParseVersion(t *testing.T) {
+	v, err := gibrun.ParseVersion("6.2.14")
+	if err != nil {
+		t.Fatalf("ParseVersion failed: %v", err)
+	}
+	if v.String() != "6.2.14" {
+		t.Errorf("expected 6.2.14, got %s", v)
+	}
+	if !v.AtLeast(gibrun.Version{Major: 6, Minor: 2}) {
+		t.Error("expected 6.2.14 to be at least 6.2")
+	}
+	if v.AtLeast(gibrun.Version{Major: 7}) {
+		t.Error("expected 6.2.14 to be below 7.0")
+	}
+	if _, err := gibrun.ParseVersion("seven"); err == nil {
+		t.Error("expected error for invalid version")
+	}
+}
+
+func TestRequireVersionInvalid(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:           "localhost:6379",
+		RequireVersion: "latest",
+	})
+	defer client.Close()
+
+	if err := client.Ping(context.Background()); err == nil {
+		t.Error("expected error for invalid RequireVersion")
+	}
+}
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	var codec gibrun.Codec = gibrun.JSONCodec{}
+
+	data, err := codec.Marshal(TestStruct{Name: "codec", Value: 7})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result TestStruct
+	if err := codec.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if result.Name != "codec" || result.Value != 7 {
+		t.Errorf("unexpected round trip result %+v", result)
+	}
+}
+
+// fakeProto mimics a gogoproto-generated message.
+type fakeProto struct {
+	payload string
+}
+
+func (m *fakeProto) ProtoMessage()            {}
+func (m *fakeProto) Marshal() ([]byte, error) { return []byte("wire:" + m.payload), nil }
+func (m *fakeProto) Unmarshal(data []byte) error {
+	m.payload = string(data[len("wire:"):])
+	return nil
+}
+
+func TestProtoCodec(t *testing.T) {
+	codec := gibrun.ProtoCodec{}
+
+	data, err := codec.Marshal(&fakeProto{payload: "hello"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != "wire:hello" {
+		t.Errorf("expected wire format, got %q", data)
+	}
+
+	var msg fakeProto
+	if err := codec.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if msg.payload != "hello" {
+		t.Errorf("expected hello, got %q", msg.payload)
+	}
+
+	// Non-proto values fall back to JSON
+	data, err = codec.Marshal(TestStruct{Name: "json"})
+	if err != nil {
+		t.Fatalf("fallback Marshal failed: %v", err)
+	}
+	if data[0] != '{' {
+		t.Errorf("expected JSON fallback, got %q", data)
+	}
+}
+
+func TestGobCodecRoundTrip(t *testing.T) {
+	var codec gibrun.Codec = gibrun.GobCodec{}
+
+	original := map[string]time.Time{"created": time.Date(2024, 6, 1, 12, 0, 0, 123456789, time.UTC)}
+	data, err := codec.Marshal(original)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result map[string]time.Time
+	if err := codec.Unmarshal(data, &result); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !result["created"].Equal(original["created"]) {
+		t.Errorf("expected %v, got %v", original["created"], result["created"])
+	}
+}
+
+func TestGibAppendAndStrLen(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:append"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	if n, err := client.Run(ctx, key).StrLen(); err != nil || n != 0 {
+		t.Errorf("expected StrLen 0 for a missing key, got %d %v", n, err)
+	}
+
+	res, err := client.Gib(ctx, key).Append("hello").ExecDetail()
+	if err != nil {
+		t.Fatalf("first Append failed: %v", err)
+	}
+	if !res.Created || res.Replaced || res.Size != 5 {
+		t.Errorf("unexpected first detail: %+v", res)
+	}
+	res, err = client.Gib(ctx, key).Append(", world").TTL(time.Minute).ExecDetail()
+	if err != nil {
+		t.Fatalf("second Append failed: %v", err)
+	}
+	if res.Created || !res.Replaced || res.Size != 7 {
+		t.Errorf("unexpected second detail: %+v", res)
+	}
+
+	if val, found, _ := client.Run(ctx, key).Raw(); !found || val != "hello, world" {
+		t.Errorf("expected the appended value, got %q", val)
+	}
+	if n, err := client.Run(ctx, key).StrLen(); err != nil || n != 12 {
+		t.Errorf("expected StrLen 12, got %d %v", n, err)
+	}
+	if ttl, _ := client.Do(ctx, "PTTL", key).Int(); ttl <= 0 || ttl > 60000 {
+		t.Errorf("expected Append to refresh the TTL, got %d", ttl)
+	}
+
+	encrypted := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+		Encryption: gibrun.StaticKeys{
+			Current: "k1",
+			Keys:    map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")},
+		},
+	})
+	defer encrypted.Close()
+	if err := encrypted.Gib(ctx, key).Append("!").Exec(); !errors.Is(err, gibrun.ErrAppendEncrypted) {
+		t.Errorf("expected ErrAppendEncrypted, got %v", err)
+	}
+	if n, _ := client.Run(ctx, key).StrLen(); n != 12 {
+		t.Errorf("expected the rejected Append to leave the value alone, StrLen %d", n)
+	}
+}
+
+func TestEncryptionRoundTrip(t *testing.T) {
+	keys := gibrun.StaticKeys{
+		Current: "k1",
+		Keys:    map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")},
+	}
+	client := gibrun.New(gibrun.Config{
+		Addr:       "localhost:6379",
+		Encryption: keys,
+	})
+	defer client.Close()
+	plain := gibrun.New(gibrun.Config{Addr: "localhost:6379"})
+	defer plain.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:encrypted"
+	original := TestStruct{Name: "secret", Value: 99}
+	if err := client.Gib(ctx, key).Value(original).TTL(time.Minute).Exec(); err != nil {
+		t.Fatalf("Gib failed: %v", err)
+	}
+	defer client.Del(ctx, key)
+
+	stored, err := plain.Do(ctx, "GET", key).String()
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	if strings.Contains(stored, "secret") {
+		t.Error("expected stored value to be encrypted")
+	}
+
+	if _, _, err := plain.Run(ctx, key).Raw(); !errors.Is(err, gibrun.ErrDecryptionKeyMissing) {
+		t.Errorf("expected ErrDecryptionKeyMissing without Encryption, got %v", err)
+	}
+
+	// Values written before Encryption was enabled still decode
+	legacy := key + ":legacy"
+	plain.Gib(ctx, legacy).Value(original).Exec()
+	defer plain.Del(ctx, legacy)
+	var mixed TestStruct
+	if found, err := client.Run(ctx, legacy).Bind(&mixed); err != nil || !found || mixed != original {
+		t.Errorf("expected plain value to decode on encrypting client: %+v %v %v", mixed, found, err)
+	}
+
+	var result TestStruct
+	found, err := client.Run(ctx, key).Bind(&result)
+	if err != nil || !found {
+		t.Fatalf("Bind failed: found=%v err=%v", found, err)
+	}
+	if result != original {
+		t.Errorf("expected %+v, got %+v", original, result)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	if err := (gibrun.Config{Addr: "localhost:6379"}).Validate(); err != nil {
+		t.Errorf("expected valid config, got %v", err)
+	}
+
+	err := (gibrun.Config{Addr: "", DB: -1}).Validate()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	for _, field := range []string{"Addr", "DB"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("expected error to mention %s, got %v", field, err)
+		}
+	}
+
+	limiter := gibrun.NewRateLimiter(gibrun.New(gibrun.Config{Addr: "localhost:6379"}), gibrun.RateLimitConfig{})
+	if limiter.Err() == nil {
+		t.Error("expected rate limiter with zero Rate and Window to report an error")
+	}
+	if _, err := limiter.Allow(context.Background(), "user:1"); err == nil {
+		t.Error("expected Allow to fail on invalid config")
+	}
+}
+
+func TestGibIfVersion(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:versioned"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	if err := client.Gib(ctx, key).Value(TestStruct{Name: "v1"}).IfVersion(0).Exec(); err != nil {
+		t.Fatalf("first versioned write failed: %v", err)
+	}
+
+	var result TestStruct
+	version, found, err := client.Run(ctx, key).BindVersion(&result)
+	if err != nil || !found {
+		t.Fatalf("BindVersion failed: found=%v err=%v", found, err)
+	}
+	if version != 1 || result.Name != "v1" {
+		t.Errorf("expected version 1 with v1, got %d with %+v", version, result)
+	}
+
+	err = client.Gib(ctx, key).Value(TestStruct{Name: "stale"}).IfVersion(0).Exec()
+	if !errors.Is(err, gibrun.ErrVersionConflict) {
+		t.Errorf("expected ErrVersionConflict, got %v", err)
+	}
+}
+
+func TestGibChunked(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:      "localhost:6379",
+		ChunkSize: 16,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:chunked"
+	original := strings.Repeat("gibrun-chunk-", 10)
+	if err := client.Gib(ctx, key).Value(original).TTL(time.Minute).Exec(); err != nil {
+		t.Fatalf("Gib failed: %v", err)
+	}
+	defer client.Del(ctx, key)
+
+	val, found, err := client.Run(ctx, key).Raw()
+	if err != nil || !found {
+		t.Fatalf("Raw failed: found=%v err=%v", found, err)
+	}
+	if val != original {
+		t.Errorf("expected reassembled value, got %q", val)
+	}
+}
+
+func TestGibChunkedNoOrphans(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:      "localhost:6379",
+		ChunkSize: 16,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:chunked:orphans"
+	chunks := func() []string {
+		keys, err := client.Do(ctx, "KEYS", key+":chunk:*").Strings()
+		if err != nil {
+			t.Fatalf("KEYS failed: %v", err)
+		}
+		return keys
+	}
+	long := strings.Repeat("gibrun-chunk-", 10)
+	defer client.Del(ctx, key)
+
+	// Longer, then shorter chunked value: only the new chunks remain
+	client.Gib(ctx, key).Value(long).Exec()
+	client.Gib(ctx, key).Value(long[:40]).Exec()
+	if n := len(chunks()); n != 3 {
+		t.Errorf("expected 3 chunks after shrinking, got %d", n)
+	}
+
+	// Plain value over a chunked one
+	client.Gib(ctx, key).Value("small").Exec()
+	if left := chunks(); len(left) != 0 {
+		t.Errorf("expected no chunks after overwrite, got %v", left)
+	}
+	if val, _, _ := client.Run(ctx, key).Raw(); val != "small" {
+		t.Errorf("expected small, got %q", val)
+	}
+
+	// Del and InvalidateTag drop the chunks too
+	client.Gib(ctx, key).Value(long).Exec()
+	client.Del(ctx, key)
+	if left := chunks(); len(left) != 0 {
+		t.Errorf("expected no chunks after Del, got %v", left)
+	}
+	client.Gib(ctx, key).Value(long).Tags("test-chunked").Exec()
+	client.InvalidateTag(ctx, "test-chunked")
+	if left := chunks(); len(left) != 0 {
+		t.Errorf("expected no chunks after InvalidateTag, got %v", left)
+	}
+}
+
+func TestDelChunkedScriptAndFallback(t *testing.T) {
+	ctx := context.Background()
+
+	for _, scripting := range []bool{true, false} {
+		client := gibrun.New(gibrun.Config{
+			Addr:      "localhost:6379",
+			ChunkSize: 16,
+		})
+		defer client.Close()
+
+		if err := client.Ping(ctx); err != nil {
+			t.Skip("Redis not available, skipping integration test")
+		}
+
+		info, err := gibrun.ProbeServer(ctx, client)
+		if err != nil {
+			t.Fatalf("ProbeServer failed: %v", err)
+		}
+		if scripting && !info.Supports(gibrun.CapScripting) {
+			continue
+		}
+		info.Capabilities[gibrun.CapScripting] = scripting
+
+		chunked, plain, list := "test:gibrun:del:chunked", "test:gibrun:del:plain", "test:gibrun:del:list"
+		client.Gib(ctx, chunked).Value(strings.Repeat("gibrun-del-", 10)).Exec()
+		client.Gib(ctx, plain).Value("small").Exec()
+		client.Do(ctx, "RPUSH", list, "a")
+
+		if err := client.Del(ctx, chunked, plain, list); err != nil {
+			t.Fatalf("scripting=%v: Del failed: %v", scripting, err)
+		}
+		if left, _ := client.Do(ctx, "KEYS", "test:gibrun:del:*").Strings(); len(left) != 0 {
+			t.Errorf("scripting=%v: expected everything deleted, got %v", scripting, left)
+		}
+	}
+}
+
+func TestMGibChunkedAndGuarded(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:       "localhost:6379",
+		ChunkSize:  16,
+		WriteGuard: &gibrun.WriteGuardConfig{MaxRate: 1, Window: time.Hour, Throttle: true},
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	small, large := "test:gibrun:mgib:small", "test:gibrun:mgib:large"
+	chunks := func(key string) int {
+		keys, _ := client.Do(ctx, "KEYS", key+":chunk:*").Strings()
+		return len(keys)
+	}
+	long := strings.Repeat("gibrun-mgib-", 10)
+	client.Del(ctx, small, large)
+	defer client.Del(ctx, small, large)
+
+	// A batch that fails to marshal writes nothing and isn't counted
+	err := client.MGib(ctx).
+		Add(small, "first", time.Minute).
+		Add(large, make(chan int), time.Minute).
+		Exec()
+	if err == nil {
+		t.Fatal("expected a marshal error")
+	}
+
+	// Leave a chunked value behind to be replaced by a small one
+	other := gibrun.New(gibrun.Config{Addr: "localhost:6379", ChunkSize: 16})
+	defer other.Close()
+	other.Gib(ctx, small).Value(long).Exec()
+	if n := chunks(small); n == 0 {
+		t.Fatal("expected a chunked value to start from")
+	}
+
+	err = client.MGib(ctx).
+		Add(small, "plain", time.Minute).
+		Add(large, long, time.Minute).
+		Exec()
+	if err != nil {
+		t.Fatalf("MGib failed: %v", err)
+	}
+	var got string
+	if found, err := client.Run(ctx, large).Bind(&got); err != nil || !found || got != long {
+		t.Errorf("expected the chunked value back, got %q %v %v", got, found, err)
+	}
+	if n := chunks(large); n == 0 {
+		t.Error("expected the large value to be chunked")
+	}
+	if ttl, _ := client.Do(ctx, "PTTL", large).Int(); ttl <= 0 {
+		t.Errorf("expected the chunked value to expire, PTTL is %d", ttl)
+	}
+	if found, _ := client.Run(ctx, small).Bind(&got); !found || got != "plain" {
+		t.Errorf("expected plain, got %q", got)
+	}
+	if n := chunks(small); n != 0 {
+		t.Errorf("expected stale chunks of %s to be dropped, %d left", small, n)
+	}
+
+	// Both keys used their one write, the next is throttled
+	if err := client.MGib(ctx).Add(small, "again", 0).Exec(); !errors.Is(err, gibrun.ErrWriteThrottled) {
+		t.Errorf("expected ErrWriteThrottled, got %v", err)
+	}
+}
+
+func TestInvalidateTag(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	keys := []string{"test:gibrun:tagged:1", "test:gibrun:tagged:2"}
+	for _, key := range keys {
+		if err := client.Gib(ctx, key).Value("v").Tags("test-org:7").Exec(); err != nil {
+			t.Fatalf("Gib failed: %v", err)
+		}
+	}
+
+	n, err := client.InvalidateTag(ctx, "test-org:7")
+	if err != nil {
+		t.Fatalf("InvalidateTag failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("expected 2 deleted keys, got %d", n)
+	}
+	for _, key := range keys {
+		if exists, _ := client.Exists(ctx, key); exists {
+			t.Errorf("expected %s to be invalidated", key)
+		}
+	}
+	if exists, _ := client.Exists(ctx, "gibrun:tag:test-org:7"); exists {
+		t.Error("expected the emptied tag set to be removed")
+	}
+
+	// Tag sets live as long as their longest-lived entry
+	set := "gibrun:tag:test-org:8"
+	defer client.Del(ctx, set, keys[0], keys[1])
+	client.Gib(ctx, keys[0]).Value("v").TTL(time.Minute).Tags("test-org:8").Exec()
+	client.Gib(ctx, keys[1]).Value("v").TTL(time.Hour).Tags("test-org:8").Exec()
+	if ttl, err := client.Do(ctx, "PTTL", set).Int(); err != nil || ttl <= int64(time.Minute/time.Millisecond) {
+		t.Errorf("expected tag set TTL near an hour, got %v %v", ttl, err)
+	}
+	client.Gib(ctx, keys[0]).Value("v").Tags("test-org:8").Exec()
+	if ttl, _ := client.Do(ctx, "PTTL", set).Int(); ttl != -1 {
+		t.Errorf("expected tag set to persist with an untimed entry, got %d", ttl)
+	}
+}
+
+func TestValidators(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:       "localhost:6379",
+		Validators: []gibrun.ValidateFunc{gibrun.MaxValueSize(8)},
+	})
+	defer client.Close()
+	client.AddValidator(gibrun.KeyPattern(regexp.MustCompile(`^app:`)))
+
+	ctx := context.Background()
+
+	err := client.Gib(ctx, "bad-key").Value("v").Exec()
+	if !errors.Is(err, gibrun.ErrValidation) {
+		t.Errorf("expected ErrValidation for key, got %v", err)
+	}
+
+	err = client.Gib(ctx, "app:big").Value(strings.Repeat("x", 9)).Exec()
+	if !errors.Is(err, gibrun.ErrValidation) {
+		t.Errorf("expected ErrValidation for size, got %v", err)
+	}
+}
+
+func TestBlusukanWhere(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	client.Gib(ctx, "test:gibrun:where:1").Value(map[string]string{"plan": "trial"}).Exec()
+	client.Gib(ctx, "test:gibrun:where:2").Value(map[string]string{"plan": "pro"}).Exec()
+	client.Gib(ctx, "test:gibrun:where:3").NotFound().TTL(time.Minute).Exec()
+	defer client.Del(ctx, "test:gibrun:where:1", "test:gibrun:where:2", "test:gibrun:where:3")
+
+	keys, err := client.Blusukan(ctx, gibrun.ScanOptions{
+		Pattern: "test:gibrun:where:*",
+		Where: func(key string, val []byte) bool {
+			if key == "test:gibrun:where:3" {
+				t.Error("expected the NotFound marker to be skipped")
+			}
+			return strings.Contains(string(val), `"plan":"trial"`)
+		},
+	}).All()
+	if err != nil {
+		t.Fatalf("Blusukan failed: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != "test:gibrun:where:1" {
+		t.Errorf("expected only test:gibrun:where:1, got %v", keys)
+	}
+}
+
+func TestGibExecDetail(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:detail"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	res, err := client.Gib(ctx, key).Value("hello").TTL(time.Minute).ExecDetail()
+	if err != nil {
+		t.Fatalf("ExecDetail failed: %v", err)
+	}
+	if !res.Created || res.Replaced || res.Size != 5 || res.TTL != time.Minute {
+		t.Errorf("unexpected first result: %+v", res)
+	}
+
+	res, err = client.Gib(ctx, key).Value("again").ExecDetail()
+	if err != nil {
+		t.Fatalf("ExecDetail failed: %v", err)
+	}
+	if res.Created || !res.Replaced {
+		t.Errorf("expected replaced, got %+v", res)
+	}
+}
+
+func TestTTLPolicies(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+		TTLPolicies: []gibrun.TTLPolicy{
+			{Prefix: "test:gibrun:ttl:", Default: time.Minute, Max: time.Hour, Jitter: 10 * time.Second},
+			{Prefix: "test:gibrun:ttl:capped:", Max: 30 * time.Second},
+		},
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	keys := []string{"test:gibrun:ttl:default", "test:gibrun:ttl:explicit", "test:gibrun:ttl:max", "test:gibrun:ttl:capped:x"}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	// Default TTLs are jittered
+	res, err := client.Gib(ctx, keys[0]).Value("v").ExecDetail()
+	if err != nil {
+		t.Fatalf("ExecDetail failed: %v", err)
+	}
+	if res.TTL < time.Minute || res.TTL >= time.Minute+10*time.Second {
+		t.Errorf("expected Default plus jitter, got %v", res.TTL)
+	}
+
+	// Explicit TTLs are kept exact
+	for i := 0; i < 5; i++ {
+		res, err = client.Gib(ctx, keys[1]).Value("v").TTL(2 * time.Minute).ExecDetail()
+		if err != nil || res.TTL != 2*time.Minute {
+			t.Fatalf("expected an exact explicit TTL, got %v %v", res.TTL, err)
+		}
+	}
+
+	// Max caps explicit TTLs, and the longest prefix wins
+	res, _ = client.Gib(ctx, keys[2]).Value("v").TTL(48 * time.Hour).ExecDetail()
+	if res.TTL != time.Hour {
+		t.Errorf("expected Max to cap the TTL, got %v", res.TTL)
+	}
+	res, _ = client.Gib(ctx, keys[3]).Value("v").ExecDetail()
+	if res.TTL != 30*time.Second {
+		t.Errorf("expected the capped policy Max for a key without TTL, got %v", res.TTL)
+	}
+	if ttl, _ := client.Do(ctx, "PTTL", keys[3]).Int(); ttl <= 0 || ttl > 30000 {
+		t.Errorf("expected the stored PTTL within 30s, got %d", ttl)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := gibrun.New(gibrun.Config{Addr: "localhost:6379", DB: 14})
+	b := gibrun.New(gibrun.Config{Addr: "localhost:6379", DB: 15})
+	defer a.Close()
+	defer b.Close()
+
+	ctx := context.Background()
+
+	if err := a.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	keys := []string{"test:gibrun:diff:same", "test:gibrun:diff:changed", "test:gibrun:diff:a", "test:gibrun:diff:b"}
+	defer a.Del(ctx, keys...)
+	defer b.Del(ctx, keys...)
+
+	a.Gib(ctx, keys[0]).Value("v").Exec()
+	b.Gib(ctx, keys[0]).Value("v").Exec()
+	a.Gib(ctx, keys[1]).Value("old").Exec()
+	b.Gib(ctx, keys[1]).Value("new").Exec()
+	a.Gib(ctx, keys[2]).Value("v").Exec()
+	b.Gib(ctx, keys[3]).Value("v").Exec()
+
+	report, err := gibrun.Diff(ctx, a, b, gibrun.DiffOptions{Pattern: "test:gibrun:diff:*"})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if report.OnlyA != 1 || report.OnlyB != 1 || report.Value != 1 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+}
+
+func TestCacheGetMany(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	loads := 0
+	users := gibrun.NewCache(client, gibrun.CacheOptions[User]{
+		Prefix: "test:gibrun:cache:",
+		TTL:    time.Minute,
+		LoadMany: func(ctx context.Context, ids []string) (map[string]User, error) {
+			loads++
+			out := make(map[string]User)
+			for _, id := range ids {
+				if id != "missing" {
+					out[id] = User{Name: "user-" + id}
+				}
+			}
+			return out, nil
+		},
+	})
+	defer client.Del(ctx, users.Key("1"), users.Key("2"))
+
+	client.Gib(ctx, users.Key("1")).Value(User{Name: "cached"}).Exec()
+	client.Del(ctx, users.Key("2"))
+
+	got, err := users.GetMany(ctx, []string{"1", "2", "missing"})
+	if err != nil {
+		t.Fatalf("GetMany failed: %v", err)
+	}
+	if loads != 1 || len(got) != 2 || got["1"].Name != "cached" || got["2"].Name != "user-2" {
+		t.Errorf("unexpected result %+v after %d loads", got, loads)
+	}
+
+	var stored User
+	if found, _ := client.Run(ctx, users.Key("2")).Bind(&stored); !found {
+		t.Error("expected loaded value to be written back")
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	client.Del(ctx, "test:gibrun:pipe:counter")
+	defer client.Del(ctx, "test:gibrun:pipe:value", "test:gibrun:pipe:counter")
+
+	results, err := client.Pipeline(ctx).
+		Gib("test:gibrun:pipe:value").Value("v").TTL(time.Minute).
+		Sprint("test:gibrun:pipe:counter").IncrBy(5).
+		Del("test:gibrun:pipe:none").
+		Exec()
+	if err != nil {
+		t.Fatalf("Pipeline failed: %v", err)
+	}
+	if len(results) != 3 || results[1].Int != 5 || results[2].Int != 0 {
+		t.Errorf("unexpected results: %+v", results)
+	}
+}
+
+func TestTxRun(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:tx:balance"
+	client.Gib(ctx, key).Value("10").Exec()
+	defer client.Del(ctx, key)
+
+	err := client.Tx(ctx).Watch(key).Run(func(tx *gibrun.TxOps) error {
+		n, err := tx.Int(key)
+		if err != nil {
+			return err
+		}
+		if n < 10 {
+			return errors.New("insufficient balance")
+		}
+		tx.IncrBy(key, 5)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Tx failed: %v", err)
+	}
+
+	val, _, _ := client.Run(ctx, key).Raw()
+	if val != "15" {
+		t.Errorf("expected 15, got %s", val)
+	}
+}
+
+func TestClaimAckNack(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	set := "test:gibrun:{claim}"
+	client.Del(ctx, set, set+":inflight")
+	defer client.Del(ctx, set, set+":inflight")
+
+	now := time.Now()
+	client.Schedule(ctx, set, "a", now.Add(-time.Second))
+	client.Schedule(ctx, set, "b", now.Add(-time.Second))
+	client.Schedule(ctx, set, "later", now.Add(time.Hour))
+
+	ids, err := client.Claim(ctx, set, 10, time.Minute)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 due members, got %v", ids)
+	}
+
+	if err := client.Ack(ctx, set, ids[0]); err != nil {
+		t.Fatalf("Ack failed: %v", err)
+	}
+	if ok, err := client.Nack(ctx, set, ids[1], 0); err != nil || !ok {
+		t.Fatalf("Nack failed: %v %v", ok, err)
+	}
+
+	again, _ := client.Claim(ctx, set, 10, time.Minute)
+	if len(again) != 1 || again[0] != ids[1] {
+		t.Errorf("expected nacked member to be claimable, got %v", again)
+	}
+}
+
+func TestGibExpireAt(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:expireat"
+	defer client.Del(ctx, key)
+
+	if err := client.Gib(ctx, key).Value("v").ExpireAt(time.Now().Add(time.Hour)).Exec(); err != nil {
+		t.Fatalf("Gib failed: %v", err)
+	}
+	ttl := client.Do(ctx, "PTTL", key)
+	if ms, _ := ttl.Int(); ms <= 0 || ms > time.Hour.Milliseconds() {
+		t.Errorf("expected TTL up to an hour, got %dms", ms)
+	}
+
+	if err := client.Gib(ctx, key).Value("v").ExpireAt(time.Now().Add(-time.Minute)).Exec(); err != nil {
+		t.Fatalf("Gib failed: %v", err)
+	}
+	if exists, _ := client.Exists(ctx, key); exists {
+		t.Error("expected past expiry to delete the key")
+	}
+
+	// Conditional writes never turn into an unconditional DEL
+	past := time.Now().Add(-time.Minute)
+	persisted := false
+	conditional := map[string]*gibrun.GibBuilder{
+		"IfVersion":   client.Gib(ctx, key).Value("v").IfVersion(1),
+		"IfNotExists": client.Gib(ctx, key).Value("v").IfNotExists(),
+		"DedupToken":  client.Gib(ctx, key).Value("v").DedupToken("test-expireat"),
+		"Append":      client.Gib(ctx, key).Append("v"),
+		"AsHash":      client.Gib(ctx, key).AsHash().Value(map[string]string{"f": "v"}),
+		"AsJSON":      client.Gib(ctx, key).AsJSON().Value(map[string]string{"f": "v"}),
+		"WriteThrough": client.Gib(ctx, key).Value("v").
+			WriteThrough(func(ctx context.Context) error { persisted = true; return nil }),
+	}
+	for name, b := range conditional {
+		client.Do(ctx, "SET", key, "held")
+		if err := b.ExpireAt(past).Exec(); !errors.Is(err, gibrun.ErrUnsupported) {
+			t.Errorf("%s: expected ErrUnsupported, got %v", name, err)
+		}
+		if val, _, _ := client.Run(ctx, key).Raw(); val != "held" {
+			t.Errorf("%s: expected the key untouched, got %q", name, val)
+		}
+	}
+	if persisted {
+		t.Error("expected WriteThrough not to persist a rejected write")
+	}
+	if acquired, err := client.Gib(ctx, key).Value("v").IfNotExists().ExpireAt(past).ExecAcquired(); acquired || err == nil {
+		t.Errorf("expected ExecAcquired to fail without acquiring, got %v %v", acquired, err)
+	}
+}
+
+func TestProgress(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	defer client.Del(ctx, "gibrun:progress:test-export")
+
+	p, err := client.NewProgress(ctx, "test-export", gibrun.ProgressOptions{StaleAfter: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewProgress failed: %v", err)
+	}
+	p.Update(ctx, 40, "render", "building")
+
+	state, found, err := client.ProgressOf(ctx, "test-export")
+	if err != nil || !found {
+		t.Fatalf("ProgressOf failed: %v", err)
+	}
+	if state.Percent != 40 || state.Stage != "render" || state.Stale {
+		t.Errorf("unexpected state: %+v", state)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	state, _, _ = client.ProgressOf(ctx, "test-export")
+	if !state.Stale {
+		t.Error("expected job to be stale")
+	}
+
+	p.Finish(ctx, "ready")
+	state, _, _ = client.ProgressOf(ctx, "test-export")
+	if !state.Done || state.Stale || state.Percent != 100 {
+		t.Errorf("unexpected finished state: %+v", state)
+	}
+}
+
+func TestGibExecAsync(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:  "localhost:6379",
+		Async: &gibrun.AsyncConfig{Workers: 2, QueueSize: 16},
+	})
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		client.Close()
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:async"
+	if err := client.Gib(ctx, key).Value("v").TTL(time.Minute).ExecAsync(); err != nil {
+		t.Fatalf("ExecAsync failed: %v", err)
+	}
+	// Close flushes the queue
+	client.Close()
+
+	check := gibrun.New(gibrun.Config{Addr: "localhost:6379"})
+	defer check.Close()
+	defer check.Del(ctx, key)
+
+	if exists, _ := check.Exists(ctx, key); !exists {
+		t.Error("expected async write to be flushed on Close")
+	}
+}
+
+func TestGibExecAsyncAfterClose(t *testing.T) {
+	ctx := context.Background()
+
+	// Close before the writer ever started, and after it did
+	for _, started := range []bool{false, true} {
+		client := gibrun.New(gibrun.Config{
+			Addr:  "localhost:6379",
+			Async: &gibrun.AsyncConfig{Workers: 1, QueueSize: 1},
+		})
+		if started {
+			client.Gib(ctx, "test:gibrun:async:closed").Value("v").TTL(time.Minute).ExecAsync()
+		}
+		client.Close()
+
+		err := client.Gib(ctx, "test:gibrun:async:closed").Value("v").TTL(time.Minute).ExecAsync()
+		if !errors.Is(err, redis.ErrClosed) {
+			t.Errorf("started=%v: expected redis.ErrClosed after Close, got %v", started, err)
+		}
+	}
+}
+
+func TestPoll(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	poll := client.Poll("test:lunch")
+	poll.Delete(ctx)
+	defer poll.Delete(ctx)
+
+	if err := poll.Open(ctx, time.Now().Add(time.Minute), "bakso", "sate"); err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	if voted, err := poll.Vote(ctx, "u1", "bakso"); err != nil || !voted {
+		t.Fatalf("expected first vote to count: %v %v", voted, err)
+	}
+	if voted, _ := poll.Vote(ctx, "u1", "sate"); voted {
+		t.Error("expected second vote by same voter to be rejected")
+	}
+	if _, err := poll.Vote(ctx, "u2", "pizza"); !errors.Is(err, gibrun.ErrUnknownOption) {
+		t.Errorf("expected ErrUnknownOption, got %v", err)
+	}
+
+	tally, _ := poll.Tally(ctx)
+	if tally["bakso"] != 1 || tally["sate"] != 0 {
+		t.Errorf("unexpected tally: %v", tally)
+	}
+
+	poll.Open(ctx, time.Now().Add(-time.Second))
+	if _, err := poll.Vote(ctx, "u3", "sate"); !errors.Is(err, gibrun.ErrPollClosed) {
+		t.Errorf("expected ErrPollClosed, got %v", err)
+	}
+}
+
+func TestSchemaUpgradeOnRead(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type User struct {
+		FullName string `json:"full_name"`
+	}
+
+	client.RegisterSchema("User", 2, func(old []byte) ([]byte, error) {
+		return []byte(strings.Replace(string(old), `"name"`, `"full_name"`, 1)), nil
+	})
+
+	key := "test:gibrun:schema"
+	defer client.Del(ctx, key)
+
+	// Payload cached before the schema existed counts as v1
+	client.Gib(ctx, key).Value(`{"name":"Budi"}`).Exec()
+
+	var u User
+	if _, err := client.Run(ctx, key).Bind(&u); err != nil {
+		t.Fatalf("Bind failed: %v", err)
+	}
+	if u.FullName != "Budi" {
+		t.Errorf("expected upgraded payload, got %+v", u)
+	}
+
+	// New writes carry v2 and are not upgraded again
+	client.Gib(ctx, key).Value(User{FullName: "Sari"}).Exec()
+	u = User{}
+	client.Run(ctx, key).Bind(&u)
+	if u.FullName != "Sari" {
+		t.Errorf("expected v2 payload, got %+v", u)
+	}
+}
+
+func TestExportImportState(t *testing.T) {
+	src := gibrun.New(gibrun.Config{Addr: "localhost:6379", DB: 14})
+	dst := gibrun.New(gibrun.Config{Addr: "localhost:6379", DB: 15})
+	defer src.Close()
+	defer dst.Close()
+
+	ctx := context.Background()
+
+	if err := src.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test-state:ratelimit:user:1"
+	src.Gib(ctx, key).Value("7").TTL(time.Minute).Exec()
+	dst.Del(ctx, key)
+	defer src.Del(ctx, key)
+	defer dst.Del(ctx, key)
+
+	var buf strings.Builder
+	n, err := gibrun.ExportState(ctx, src, &buf, gibrun.StateOptions{Patterns: []string{"test-state:*"}})
+	if err != nil || n != 1 {
+		t.Fatalf("ExportState: %d keys, %v", n, err)
+	}
+
+	n, err = gibrun.ImportState(ctx, dst, strings.NewReader(buf.String()), gibrun.ImportOptions{})
+	if err != nil || n != 1 {
+		t.Fatalf("ImportState: %d keys, %v", n, err)
+	}
+
+	val, _, _ := dst.Run(ctx, key).Raw()
+	if val != "7" {
+		t.Errorf("expected restored value 7, got %q", val)
+	}
+}
+
+func TestChecksumDetectsTruncation(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:     "localhost:6379",
+		Checksum: true,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:checksum"
+	defer client.Del(ctx, key)
+
+	type Doc struct {
+		Body string `json:"body"`
+	}
+	client.Gib(ctx, key).Value(Doc{Body: "hello world"}).Exec()
+
+	var doc Doc
+	if _, err := client.Run(ctx, key).Bind(&doc); err != nil || doc.Body != "hello world" {
+		t.Fatalf("Bind failed: %v %+v", err, doc)
+	}
+
+	// Simulate a proxy cutting the value short
+	raw, _ := client.Do(ctx, "GET", key).String()
+	client.Do(ctx, "SET", key, raw[:len(raw)-3])
+
+	if _, err := client.Run(ctx, key).Bind(&doc); !errors.Is(err, gibrun.ErrCorruptValue) {
+		t.Errorf("expected ErrCorruptValue, got %v", err)
+	}
+}
+
+func TestBlusukanMany(t *testing.T) {
+	a := gibrun.New(gibrun.Config{Addr: "localhost:6379", DB: 14})
+	b := gibrun.New(gibrun.Config{Addr: "localhost:6379", DB: 15})
+	defer a.Close()
+	defer b.Close()
+
+	ctx := context.Background()
+
+	if err := a.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	a.Gib(ctx, "test:gibrun:many:1").Value("v").Exec()
+	b.Gib(ctx, "test:gibrun:many:2").Value("v").Exec()
+	b.Gib(ctx, "test:gibrun:many:3").Value("v").Exec()
+	defer a.Del(ctx, "test:gibrun:many:1")
+	defer b.Del(ctx, "test:gibrun:many:2", "test:gibrun:many:3")
+
+	counts, err := gibrun.BlusukanMany(ctx, []gibrun.ScanSource{
+		{Name: "a", Target: a},
+		{Name: "b", Target: b},
+	}, gibrun.ScanOptions{Pattern: "test:gibrun:many:*"}).Count()
+	if err != nil {
+		t.Fatalf("BlusukanMany failed: %v", err)
+	}
+	if counts["a"] != 1 || counts["b"] != 2 {
+		t.Errorf("unexpected counts: %v", counts)
+	}
+}
+
+func TestManualClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := gibrun.NewManualClock(start)
+
+	clock.Sleep(time.Minute)
+	clock.Advance(time.Second)
+	if got := clock.Now().Sub(start); got != time.Minute+time.Second {
+		t.Errorf("expected 1m1s elapsed, got %v", got)
+	}
+}
+
+func TestRateLimiterManualClock(t *testing.T) {
+	clock := gibrun.NewManualClock(time.Now())
+	client := gibrun.New(gibrun.Config{
+		Addr:  "localhost:6379",
+		Clock: clock,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	limiter := gibrun.NewRateLimiter(client, gibrun.RateLimitConfig{
+		KeyPrefix: "test:ratelimit:clock",
+		Rate:      1,
+		Window:    time.Minute,
+	})
+	limiter.Reset(ctx, "user")
+
+	if res, _ := limiter.Allow(ctx, "user"); !res.Allowed {
+		t.Fatal("expected first request to be allowed")
+	}
+	if res, _ := limiter.Allow(ctx, "user"); res.Allowed {
+		t.Fatal("expected second request to be denied")
+	}
+
+	clock.Advance(time.Minute)
+	if res, _ := limiter.Allow(ctx, "user"); !res.Allowed {
+		t.Error("expected request in the next window to be allowed")
+	}
+}
+
+func TestGibExecAcquired(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:lease"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	ok, err := client.Gib(ctx, key).Value("worker-1").TTL(time.Minute).IfNotExists().ExecAcquired()
+	if err != nil || !ok {
+		t.Fatalf("expected first acquire to succeed: %v %v", ok, err)
+	}
+
+	ok, err = client.Gib(ctx, key).Value("worker-2").TTL(time.Minute).IfNotExists().ExecAcquired()
+	if err != nil || ok {
+		t.Fatalf("expected second acquire to fail: %v %v", ok, err)
+	}
+
+	holder, _, _ := client.Run(ctx, key).Raw()
+	if holder != "worker-1" {
+		t.Errorf("expected worker-1 to hold the lease, got %s", holder)
+	}
+}
+
+func TestDedupToken(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:dedup"
+	other := "test:gibrun:dedup:other"
+	counter := "test:gibrun:dedup:counter"
+	token := "test-req-" + time.Now().Format(time.RFC3339Nano)
+	marker := func(key, token string) string { return "gibrun:dedup:{" + key + "}:" + token }
+	client.Del(ctx, key, other, counter)
+	defer client.Del(ctx, key, other, counter, marker(key, token), marker(other, token), marker(counter, token+":n"))
+
+	res, err := client.Gib(ctx, key).Value("first").DedupToken(token).ExecDetail()
+	if err != nil || res.Deduplicated {
+		t.Fatalf("expected first write to apply: %+v %v", res, err)
+	}
+	res, err = client.Gib(ctx, key).Value("retry").DedupToken(token).ExecDetail()
+	if err != nil || !res.Deduplicated {
+		t.Fatalf("expected retry to be deduplicated: %+v %v", res, err)
+	}
+	if val, _, _ := client.Run(ctx, key).Raw(); val != "first" {
+		t.Errorf("expected first value to stay, got %s", val)
+	}
+
+	// Tokens are scoped per key
+	res, err = client.Gib(ctx, other).Value("other").DedupToken(token).ExecDetail()
+	if err != nil || res.Deduplicated {
+		t.Errorf("expected the token to apply to another key: %+v %v", res, err)
+	}
+
+	client.Sprint(ctx, counter).DedupToken(token + ":n").DedupWindow(time.Minute).IncrBy(5)
+	n, _ := client.Sprint(ctx, counter).DedupToken(token + ":n").IncrBy(5)
+	if n != 5 {
+		t.Errorf("expected counter to be incremented once, got %d", n)
+	}
+	if ttl, _ := client.Do(ctx, "PTTL", marker(counter, token+":n")).Int(); ttl <= 0 || ttl > int64(time.Minute/time.Millisecond) {
+		t.Errorf("expected the Sprint marker to follow DedupWindow, PTTL %d", ttl)
+	}
+}
+
+func TestReplicatedSession(t *testing.T) {
+	app := gibrun.NewReplicated(gibrun.ReplicatedConfig{
+		Primary: gibrun.Config{Addr: "localhost:6379"},
+	})
+	defer app.Close()
+
+	ctx := context.Background()
+
+	if err := app.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:session"
+	defer app.Del(ctx, key)
+
+	s := app.Session()
+	if err := s.Gib(ctx, key).Value("fresh").Exec(); err != nil {
+		t.Fatalf("Gib failed: %v", err)
+	}
+
+	val, found, err := s.Run(ctx, key).Raw()
+	if err != nil || !found || val != "fresh" {
+		t.Errorf("expected to read own write, got %q %v %v", val, found, err)
+	}
+	if written := s.Written(); len(written) != 1 || written[0] != key {
+		t.Errorf("unexpected written keys: %v", written)
+	}
+}
+
+func TestRunOrElse(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	key := "test:gibrun:orelse"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	var loads atomic.Int32
+	loader := func(ctx context.Context) (any, time.Duration, error) {
+		loads.Add(1)
+		time.Sleep(50 * time.Millisecond)
+		return User{Name: "Budi"}, time.Minute, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var u User
+			found, err := client.Run(ctx, key).OrElse(loader).Bind(&u)
+			if err != nil || !found || u.Name != "Budi" {
+				t.Errorf("unexpected result: %v %v %+v", found, err, u)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := loads.Load(); n != 1 {
+		t.Errorf("expected a single load, got %d", n)
+	}
+}
+
+func TestRunBindWithInfo(t *testing.T) {
+	clock := gibrun.NewManualClock(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+	client := gibrun.New(gibrun.Config{
+		Addr:           "localhost:6379",
+		Clock:          clock,
+		TrackWriteTime: true,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	key := "test:gibrun:info"
+	defer client.Del(ctx, key)
+	client.Gib(ctx, key).Value(User{Name: "Budi"}).TTL(time.Minute).Exec()
+
+	var u User
+	info, err := client.Run(ctx, key).BindWithInfo(&u)
+	if err != nil {
+		t.Fatalf("BindWithInfo failed: %v", err)
+	}
+	if !info.Found || u.Name != "Budi" || info.Codec != "JSONCodec" {
+		t.Errorf("unexpected info: %+v", info)
+	}
+	if !info.StoredAt.Equal(clock.Now()) {
+		t.Errorf("expected StoredAt %v, got %v", clock.Now(), info.StoredAt)
+	}
+	if info.TTLRemaining <= 0 || info.TTLRemaining > time.Minute {
+		t.Errorf("unexpected TTL: %v", info.TTLRemaining)
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Duration
+		ok   bool
+	}{
+		{"90s", 90 * time.Second, true},
+		{"2h30m", 2*time.Hour + 30*time.Minute, true},
+		{"7d", 7 * 24 * time.Hour, true},
+		{"1d12h", 36 * time.Hour, true},
+		{"45", 45 * time.Second, true},
+		{"-5m", 0, false},
+		{"soon", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, err := gibrun.ParseTTL(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("ParseTTL(%q) = %v, %v; want %v (ok=%v)", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestConfigDurationsFromJSON(t *testing.T) {
+	var cfg gibrun.RateLimitConfig
+	if err := json.Unmarshal([]byte(`{"Rate": 100, "Window": "1m"}`), &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if cfg.Rate != 100 || cfg.Window != time.Minute {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	var policies []gibrun.TTLPolicy
+	if err := json.Unmarshal([]byte(`[{"Prefix": "session:", "Default": "30m", "Max": "1d"}]`), &policies); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if policies[0].Default != 30*time.Minute || policies[0].Max != 24*time.Hour {
+		t.Errorf("unexpected policy: %+v", policies[0])
+	}
+
+	if err := json.Unmarshal([]byte(`{"Window": "-1m"}`), &cfg); err == nil {
+		t.Error("expected negative window to be rejected")
+	}
+}
+
+func TestRunStaleWhileRevalidate(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:swr"
+	defer client.Del(ctx, key)
+
+	// Past its soft TTL: 30s left of a 1m stale window
+	client.Gib(ctx, key).Value("stale").TTL(30 * time.Second).Exec()
+
+	loader := func(ctx context.Context) (any, time.Duration, error) {
+		return "fresh", time.Minute, nil
+	}
+
+	var val string
+	found, err := client.Run(ctx, key).StaleWhileRevalidate(loader, time.Minute).Bind(&val)
+	if err != nil || !found || val != "stale" {
+		t.Fatalf("expected stale value served immediately, got %q %v %v", val, found, err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if v, _, _ := client.Run(ctx, key).Raw(); v == "fresh" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("expected background refresh to store the fresh value")
+}
+
+func TestWriteGuard(t *testing.T) {
+	clock := gibrun.NewManualClock(time.Now())
+	var hot []string
+	client := gibrun.New(gibrun.Config{
+		Addr:  "localhost:6379",
+		Clock: clock,
+		WriteGuard: &gibrun.WriteGuardConfig{
+			MaxRate:  2,
+			Throttle: true,
+			OnHotKey: func(key string, _ float64) { hot = append(hot, key) },
+		},
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+	key := "test:gibrun:hot"
+	defer client.Del(ctx, key)
+
+	// The guard runs before Redis is contacted, so only throttling matters here
+	for i := 0; i < 2; i++ {
+		if err := client.Gib(ctx, key).Value("v").Exec(); errors.Is(err, gibrun.ErrWriteThrottled) {
+			t.Fatalf("write %d throttled too early", i+1)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := client.Gib(ctx, key).Value("v").Exec(); !errors.Is(err, gibrun.ErrWriteThrottled) {
+			t.Fatalf("expected ErrWriteThrottled, got %v", err)
+		}
+	}
+	if len(hot) != 1 || hot[0] != key {
+		t.Errorf("expected a single hot-key callback, got %v", hot)
+	}
+
+	clock.Advance(time.Second)
+	if err := client.Gib(ctx, key).Value("v").Exec(); errors.Is(err, gibrun.ErrWriteThrottled) {
+		t.Error("expected the next window to accept writes")
+	}
+}
+
+func TestTTLAudit(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	keys := []string{"test:audit:1", "test:audit:2", "test:audit:3", "test:audit:4"}
+	defer client.Del(ctx, keys...)
+	client.Gib(ctx, keys[0]).Value("v").Exec()
+	client.Gib(ctx, keys[1]).Value("v").TTL(time.Hour).Exec()
+	client.Gib(ctx, keys[2]).Value("v").TTL(time.Hour).Exec()
+	client.Gib(ctx, keys[3]).Value("v").TTL(48 * time.Hour).Exec()
+
+	report, err := gibrun.TTLAudit(ctx, client, []string{"test:audit:"})
+	if err != nil {
+		t.Fatalf("TTLAudit failed: %v", err)
+	}
+	a := report[0]
+	if a.Keys != 4 || a.NoTTL != 1 || a.NoTTLFraction != 0.25 {
+		t.Errorf("unexpected counts: %+v", a)
+	}
+	if a.MaxTTL <= 47*time.Hour || a.P50 > time.Hour {
+		t.Errorf("unexpected percentiles: %+v", a)
+	}
+}
+
+func TestRunWithNegativeCache(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:negative"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	loads := 0
+	loader := func(ctx context.Context) (any, time.Duration, error) {
+		loads++
+		return nil, 0, gibrun.ErrNotFound
+	}
+
+	for i := 0; i < 3; i++ {
+		var v string
+		found, err := client.Run(ctx, key).OrElse(loader).WithNegativeCache(time.Minute).Bind(&v)
+		if err != nil || found {
+			t.Fatalf("expected a miss, got %v %v", found, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("expected the loader to run once, got %d", loads)
+	}
+
+	if _, found, _ := client.Run(ctx, key).Raw(); found {
+		t.Error("expected the marker to read as a miss")
+	}
+}
+
+func TestMRunBind(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	keys := []string{"test:gibrun:mrun:1", "test:gibrun:mrun:2", "test:gibrun:mrun:3"}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	err := client.MGib(ctx).
+		Add(keys[0], TestStruct{Name: "a", Value: 1}, time.Minute).
+		Add(keys[2], TestStruct{Name: "c", Value: 3}, time.Minute).
+		Exec()
+	if err != nil {
+		t.Fatalf("MGib failed: %v", err)
+	}
+
+	var byKey map[string]TestStruct
+	found, err := client.MRun(ctx, keys...).BindMap(&byKey)
+	if err != nil {
+		t.Fatalf("BindMap failed: %v", err)
+	}
+	if len(found) != 2 || len(byKey) != 2 || byKey[keys[2]].Value != 3 {
+		t.Errorf("unexpected BindMap result: %v %+v", found, byKey)
+	}
+
+	var list []TestStruct
+	found, err = client.MRun(ctx, keys...).BindSlice(&list)
+	if err != nil {
+		t.Fatalf("BindSlice failed: %v", err)
+	}
+	if len(list) != 2 || found[1] != keys[2] || list[1].Name != "c" {
+		t.Errorf("unexpected BindSlice result: %v %+v", found, list)
+	}
+}
+
+func TestRunBindWithTTL(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:bindttl"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	var got TestStruct
+	if _, found, err := client.Run(ctx, key).BindWithTTL(&got); err != nil || found {
+		t.Fatalf("expected a miss, got %v %v", found, err)
+	}
+
+	client.Gib(ctx, key).Value(TestStruct{Name: "ttl", Value: 1}).TTL(time.Minute).Exec()
+
+	ttl, found, err := client.Run(ctx, key).BindWithTTL(&got)
+	if err != nil || !found {
+		t.Fatalf("expected a hit, got %v %v", found, err)
+	}
+	if ttl <= 50*time.Second || ttl > time.Minute {
+		t.Errorf("unexpected TTL %v", ttl)
+	}
+	if got.Name != "ttl" {
+		t.Errorf("unexpected value %+v", got)
+	}
+}
+
+func TestRunTouch(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:touch"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	client.Gib(ctx, key).Value("session").TTL(10 * time.Second).Exec()
+
+	var got string
+	found, err := client.Run(ctx, key).Touch(time.Hour).Bind(&got)
+	if err != nil || !found || got != "session" {
+		t.Fatalf("Touch read failed: %v %v %q", found, err, got)
+	}
+
+	ttl, _, err := client.Run(ctx, key).BindWithTTL(&got)
+	if err != nil {
+		t.Fatalf("BindWithTTL failed: %v", err)
+	}
+	if ttl < 59*time.Minute {
+		t.Errorf("expected the TTL to slide to an hour, got %v", ttl)
+	}
+}
+
+func TestTypedGetSet(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:typed"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	if _, found, err := gibrun.Get[TestStruct](ctx, client, key); err != nil || found {
+		t.Fatalf("expected a miss, got %v %v", found, err)
+	}
+
+	if err := gibrun.Set(ctx, client, key, TestStruct{Name: "typed", Value: 7}, time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+
+	got, found, err := gibrun.Get[TestStruct](ctx, client, key)
+	if err != nil || !found || got.Value != 7 {
+		t.Errorf("unexpected Get result: %+v %v %v", got, found, err)
+	}
+}
+
+func TestRunBindPath(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:bindpath"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	doc := map[string]any{
+		"title": "catalog",
+		"items": []map[string]any{
+			{"name": "kopi", "price": 12.5},
+			{"name": "teh", "price": 8},
+		},
+	}
+	client.Gib(ctx, key).Value(doc).Exec()
+
+	var price float64
+	found, err := client.Run(ctx, key).BindPath("items.1.price", &price)
+	if err != nil || !found || price != 8 {
+		t.Errorf("unexpected BindPath result: %v %v %v", price, found, err)
+	}
+
+	var name string
+	found, err = client.Run(ctx, key).BindPath("items.5.name", &name)
+	if err != nil || found {
+		t.Errorf("expected a missing path, got %v %v", found, err)
+	}
+}
+
+func TestRunScalars(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	prefix := "test:gibrun:scalar:"
+	keys := []string{prefix + "int", prefix + "float", prefix + "bool", prefix + "time"}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	client.Sprint(ctx, keys[0]).IncrBy(42)
+	client.Gib(ctx, keys[1]).Value(2.5).Exec()
+	client.Gib(ctx, keys[2]).Value(true).Exec()
+	client.Gib(ctx, keys[3]).Value(now).Exec()
+
+	if n, found, err := client.Run(ctx, keys[0]).Int(); err != nil || !found || n != 42 {
+		t.Errorf("Int: %v %v %v", n, found, err)
+	}
+	if f, found, err := client.Run(ctx, keys[1]).Float64(); err != nil || !found || f != 2.5 {
+		t.Errorf("Float64: %v %v %v", f, found, err)
+	}
+	if v, found, err := client.Run(ctx, keys[2]).Bool(); err != nil || !found || !v {
+		t.Errorf("Bool: %v %v %v", v, found, err)
+	}
+	if ts, found, err := client.Run(ctx, keys[3]).Time(); err != nil || !found || !ts.Equal(now) {
+		t.Errorf("Time: %v %v %v", ts, found, err)
+	}
+	if _, _, err := client.Run(ctx, keys[3]).Int(); err == nil {
+		t.Error("expected a parse error reading a time as an integer")
+	}
+}
+
+func TestGibAsHashRoundTrip(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type address struct {
+		City string `json:"city"`
+	}
+	type account struct {
+		ID      int64             `redis:"id"`
+		Active  bool              `redis:"active"`
+		Score   float64           `redis:"score"`
+		Avatar  []byte            `redis:"avatar"`
+		Address address           `redis:"address"`
+		Labels  map[string]string `redis:"labels"`
+		Nick    string            `redis:"nick,omitempty"`
+		Secret  string            `redis:"-"`
+	}
+
+	key, mapKey := "test:gibrun:ashash", "test:gibrun:ashash:map"
+	client.Del(ctx, key, mapKey)
+	defer client.Del(ctx, key, mapKey)
+
+	in := &account{
+		ID: 7, Active: true, Score: 9.5, Avatar: []byte{0xff, 0x00, 0x01},
+		Address: address{City: "Solo"}, Labels: map[string]string{"tier": "gold"}, Secret: "hidden",
+	}
+	res, err := client.Gib(ctx, key).AsHash().Value(in).TTL(time.Minute).ExecDetail()
+	if err != nil {
+		t.Fatalf("AsHash write failed: %v", err)
+	}
+	if !res.Created || res.TTL != time.Minute || res.Size == 0 {
+		t.Errorf("unexpected detail: %+v", res)
+	}
+
+	fields, _ := client.Do(ctx, "HKEYS", key).Strings()
+	sort.Strings(fields)
+	if want := []string{"active", "address", "avatar", "id", "labels", "score"}; !reflect.DeepEqual(fields, want) {
+		t.Errorf("expected fields %v (no omitempty or - fields), got %v", want, fields)
+	}
+	if ttl, _ := client.Do(ctx, "PTTL", key).Int(); ttl <= 0 || ttl > 60000 {
+		t.Errorf("expected the TTL on the hash, got %d", ttl)
+	}
+
+	var out account
+	found, err := client.Run(ctx, key).FromHash().Bind(&out)
+	if err != nil || !found {
+		t.Fatalf("FromHash Bind failed: %v %v", found, err)
+	}
+	in.Secret = ""
+	if !reflect.DeepEqual(out, *in) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", out, *in)
+	}
+
+	// String-keyed maps are stored field by field too
+	if err := client.Gib(ctx, mapKey).AsHash().Value(map[string]int{"a": 1, "b": 2}).Exec(); err != nil {
+		t.Fatalf("AsHash map write failed: %v", err)
+	}
+	var counts map[string]int
+	if found, err := client.Run(ctx, mapKey).FromHash().Bind(&counts); err != nil || !found || counts["b"] != 2 {
+		t.Errorf("unexpected map round trip: %v %v %v", counts, found, err)
+	}
+
+	if err := client.Gib(ctx, mapKey).AsHash().Value(map[int]string{1: "x"}).Exec(); err == nil {
+		t.Error("expected non-string map keys to be rejected")
+	}
+	if err := client.Gib(ctx, mapKey).AsHash().Value("scalar").Exec(); err == nil {
+		t.Error("expected a scalar value to be rejected")
+	}
+}
+
+func TestRunFromHash(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type profile struct {
+		Name    string    `redis:"name"`
+		Email   string    `redis:"email"`
+		Age     int       `redis:"age"`
+		Tags    []string  `redis:"tags"`
+		Joined  time.Time `redis:"joined"`
+		Ignored string
+	}
+
+	key := "test:gibrun:fromhash"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	joined := time.Date(2024, 8, 17, 10, 0, 0, 0, time.UTC)
+	in := profile{Name: "Budi", Email: "budi@example.com", Age: 30, Tags: []string{"admin"}, Joined: joined}
+	if err := client.Gib(ctx, key).AsHash().Value(in).Exec(); err != nil {
+		t.Fatalf("AsHash write failed: %v", err)
+	}
+
+	var out profile
+	found, err := client.Run(ctx, key).FromHash().Bind(&out)
+	if err != nil || !found {
+		t.Fatalf("FromHash Bind failed: %v %v", found, err)
+	}
+	if out.Name != in.Name || out.Age != 30 || len(out.Tags) != 1 || !out.Joined.Equal(joined) {
+		t.Errorf("unexpected struct %+v", out)
+	}
+
+	var email string
+	found, err = client.Run(ctx, key).FromHash().BindField("email", &email)
+	if err != nil || !found || email != in.Email {
+		t.Errorf("unexpected BindField result: %q %v %v", email, found, err)
+	}
+	if found, _ := client.Run(ctx, key).FromHash().BindField("phone", &email); found {
+		t.Error("expected a missing field to report not found")
+	}
+}
+
+func TestL1Cache(t *testing.T) {
+	l1 := &gibrun.L1Config{MaxEntries: 100, TTL: time.Minute, Channel: "test:gibrun:l1"}
+	a := gibrun.New(gibrun.Config{Addr: "localhost:6379", L1: l1})
+	defer a.Close()
+	b := gibrun.New(gibrun.Config{Addr: "localhost:6379", L1: l1})
+	defer b.Close()
+
+	ctx := context.Background()
+
+	if err := a.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:l1"
+	a.Del(ctx, key)
+	defer a.Del(ctx, key)
+
+	a.Gib(ctx, key).Value("v1").Exec()
+
+	var got string
+	if found, err := b.Run(ctx, key).Bind(&got); err != nil || !found || got != "v1" {
+		t.Fatalf("first read failed: %v %v %q", found, err, got)
+	}
+
+	// Served from b's memory even though Redis no longer has it
+	plain := gibrun.New(gibrun.Config{Addr: "localhost:6379"})
+	defer plain.Close()
+	if err := plain.Do(ctx, "SET", key, "outside").Err(); err != nil {
+		t.Fatalf("SET failed: %v", err)
+	}
+	b.Run(ctx, key).Bind(&got)
+	if got != "v1" {
+		t.Errorf("expected the L1 copy, got %q", got)
+	}
+
+	// A write through a gibrun client invalidates every instance
+	a.Gib(ctx, key).Value("v2").Exec()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		b.Run(ctx, key).Bind(&got)
+		if got == "v2" {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Errorf("expected invalidation to reach the other instance, still %q", got)
+}
+
+func TestDegradation(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:        "localhost:6379",
+		Degradation: &gibrun.DegradationConfig{Threshold: 20 * time.Millisecond, Window: 100, MinSamples: 50},
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	// Blocking reads wait on purpose and must not count as slow
+	key := "test:gibrun:degrade:list"
+	client.Del(ctx, key)
+	for i := 0; i < 5; i++ {
+		client.Do(ctx, "BLPOP", key, "0.05")
+	}
+	for i := 0; i < 100; i++ {
+		client.Ping(ctx)
+	}
+	if client.Degraded() {
+		t.Errorf("expected blocking reads to be ignored, p99 is %v", client.LatencyP99())
+	}
+	if p99 := client.LatencyP99(); p99 <= 0 || p99 >= 20*time.Millisecond {
+		t.Errorf("expected a small non-zero p99, got %v", p99)
+	}
+
+	// Degraded clients keep serving expired L1 entries
+	clock := gibrun.NewManualClock(time.Now())
+	var flips []bool
+	slow := gibrun.New(gibrun.Config{
+		Addr:  "localhost:6379",
+		Clock: clock,
+		L1:    &gibrun.L1Config{MaxEntries: 10, TTL: time.Minute, StaleWhileDegraded: time.Hour, Channel: "test:gibrun:degrade:l1"},
+		Degradation: &gibrun.DegradationConfig{
+			Threshold:  time.Nanosecond,
+			Window:     50,
+			MinSamples: 50,
+			OnChange:   func(degraded bool, _ time.Duration) { flips = append(flips, degraded) },
+		},
+	})
+	defer slow.Close()
+
+	key = "test:gibrun:degrade:l1"
+	slow.Del(ctx, key)
+	defer slow.Del(ctx, key)
+
+	slow.Gib(ctx, key).Value("v1").Exec()
+	var got string
+	slow.Run(ctx, key).Bind(&got)
+	client.Do(ctx, "SET", key, "outside")
+
+	for i := 0; i < 100; i++ {
+		slow.Ping(ctx)
+	}
+	if !slow.Degraded() || len(flips) != 1 || !flips[0] {
+		t.Fatalf("expected one flip to degraded, got %v %v", slow.Degraded(), flips)
+	}
+
+	clock.Advance(2 * time.Minute)
+	slow.Run(ctx, key).Bind(&got)
+	if got != "v1" {
+		t.Errorf("expected the stale L1 copy while degraded, got %q", got)
+	}
+
+	clock.Advance(time.Hour)
+	slow.Run(ctx, key).Bind(&got)
+	if got != "outside" {
+		t.Errorf("expected Redis past StaleWhileDegraded, got %q", got)
+	}
+}
+
+func TestClientStats(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "statstest:1"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	var v TestStruct
+	client.Run(ctx, key).Bind(&v)
+	client.Gib(ctx, key).Value("not json").Exec()
+	client.Run(ctx, key).Bind(&v)
+	var s string
+	client.Run(ctx, key).Bind(&s)
+
+	var got *gibrun.PrefixStats
+	for _, st := range client.Stats() {
+		if st.Prefix == "statstest:" {
+			st := st
+			got = &st
+		}
+	}
+	if got == nil {
+		t.Fatal("expected stats for the statstest: prefix")
+	}
+	if got.Hits != 1 || got.Misses != 1 || got.BindErrors != 1 || got.Writes != 1 {
+		t.Errorf("unexpected stats %+v", *got)
+	}
+	if got.HitRatio() != 0.5 {
+		t.Errorf("expected hit ratio 0.5, got %v", got.HitRatio())
+	}
+}
+
+func TestMRunBindEachLoadMissing(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	keys := []string{"test:gibrun:each:1", "test:gibrun:each:2", "test:gibrun:each:3"}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	client.Gib(ctx, keys[0]).Value(TestStruct{Name: "cached", Value: 1}).Exec()
+
+	var asked []string
+	loader := func(ctx context.Context, missing []string) (map[string]any, time.Duration, error) {
+		asked = missing
+		return map[string]any{keys[1]: TestStruct{Name: "loaded", Value: 2}}, time.Minute, nil
+	}
+
+	byKey := map[string]TestStruct{}
+	found, err := client.MRun(ctx, keys...).BindEach(&byKey).LoadMissing(loader)
+	if err != nil {
+		t.Fatalf("LoadMissing failed: %v", err)
+	}
+	if !found[keys[0]] || found[keys[1]] || found[keys[2]] {
+		t.Errorf("unexpected found map %v", found)
+	}
+	if len(asked) != 2 {
+		t.Errorf("expected the loader to get the two misses, got %v", asked)
+	}
+	if len(byKey) != 2 || byKey[keys[1]].Name != "loaded" {
+		t.Errorf("unexpected result %+v", byKey)
+	}
+
+	// The loaded value was written back
+	var again TestStruct
+	if found, _ := client.Run(ctx, keys[1]).Bind(&again); !found {
+		t.Error("expected the loaded value to be cached")
+	}
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := gibrun.NewManualClock(start)
+	client := gibrun.New(gibrun.Config{
+		Addr:  "localhost:1",
+		Clock: clock,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	var v string
+	if _, err := client.Run(ctx, "any").Retry(2, time.Second).Bind(&v); err == nil {
+		t.Fatal("expected a connection error")
+	}
+	if waited := clock.Now().Sub(start); waited != 3*time.Second {
+		t.Errorf("expected two retries with doubling backoff (3s), waited %v", waited)
+	}
+
+	// Validation errors are never retried
+	client.AddValidator(func(string, any) error { return errors.New("rejected") })
+	clock.Set(start)
+	if err := client.Gib(ctx, "any").Value("x").Retry(2, time.Second).Exec(); !errors.Is(err, gibrun.ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+	if !clock.Now().Equal(start) {
+		t.Errorf("expected no retries, clock moved to %v", clock.Now())
+	}
+}
+
+func TestRunBindAndDelete(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:getdel"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	client.Gib(ctx, key).Value(TestStruct{Name: "otp", Value: 123456}).TTL(time.Minute).Exec()
+
+	var got TestStruct
+	found, err := client.Run(ctx, key).BindAndDelete(&got)
+	if err != nil || !found || got.Value != 123456 {
+		t.Fatalf("first read failed: %+v %v %v", got, found, err)
+	}
+
+	found, err = client.Run(ctx, key).BindAndDelete(&got)
+	if err != nil || found {
+		t.Errorf("expected the token to be gone, got %v %v", found, err)
+	}
+}
+
+func TestRunGetExPersist(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:getex"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	client.Gib(ctx, key).Value("draft").TTL(time.Minute).Exec()
+
+	var got string
+	if found, err := client.Run(ctx, key).GetEx(time.Hour).Bind(&got); err != nil || !found {
+		t.Fatalf("GetEx failed: %v %v", found, err)
+	}
+	if ttl, _, _ := client.Run(ctx, key).BindWithTTL(&got); ttl < 59*time.Minute {
+		t.Errorf("expected GetEx to set an hour TTL, got %v", ttl)
+	}
+
+	if found, err := client.Run(ctx, key).Persist().Bind(&got); err != nil || !found {
+		t.Fatalf("Persist failed: %v %v", found, err)
+	}
+	if ttl, _, _ := client.Run(ctx, key).BindWithTTL(&got); ttl != -1 {
+		t.Errorf("expected no expiry after Persist, got %v", ttl)
+	}
+}
+
+func TestSprintGetOrErr(t *testing.T) {
+	down := gibrun.New(gibrun.Config{Addr: "localhost:1"})
+	defer down.Close()
+	if _, err := down.Sprint(context.Background(), "counter").Get(); err == nil {
+		t.Error("expected Get to surface connection errors")
+	}
+
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:getorerr"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	if n, exists, err := client.Sprint(ctx, key).GetOrErr(); err != nil || exists || n != 0 {
+		t.Errorf("expected a missing key, got %v %v %v", n, exists, err)
+	}
+
+	client.Sprint(ctx, key).IncrBy(5)
+	if n, exists, err := client.Sprint(ctx, key).GetOrErr(); err != nil || !exists || n != 5 {
+		t.Errorf("expected 5, got %v %v %v", n, exists, err)
+	}
+}
+
+func TestSprintIncrWithTTL(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:incrttl"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	if n, err := client.Sprint(ctx, key).IncrWithTTL(time.Minute); err != nil || n != 1 {
+		t.Fatalf("first increment: %v %v", n, err)
+	}
+	if n, err := client.Sprint(ctx, key).IncrWithTTL(time.Hour); err != nil || n != 2 {
+		t.Fatalf("second increment: %v %v", n, err)
+	}
+
+	// The window started at the first increment
+	var v string
+	ttl, _, _ := client.Run(ctx, key).BindWithTTL(&v)
+	if ttl <= 0 || ttl > time.Minute {
+		t.Errorf("expected the first window to stick, got %v", ttl)
+	}
+}
+
+func TestSprintIncrIfBelow(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:incrifbelow"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	var wg sync.WaitGroup
+	var granted atomic.Int64
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok, err := client.Sprint(ctx, key).IncrIfBelow(5); err == nil && ok {
+				granted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if granted.Load() != 5 {
+		t.Errorf("expected exactly 5 increments, got %d", granted.Load())
+	}
+	if n, ok, err := client.Sprint(ctx, key).IncrIfBelow(5); err != nil || ok || n != 5 {
+		t.Errorf("expected the limit to hold at 5, got %v %v %v", n, ok, err)
+	}
+}
+
+func TestSprintCompareAndSwap(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:cas"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	if ok, err := client.Sprint(ctx, key).CompareAndSwap(0, 1); err != nil || !ok {
+		t.Fatalf("expected 0 -> 1 on a missing key, got %v %v", ok, err)
+	}
+	if ok, err := client.Sprint(ctx, key).CompareAndSwap(0, 2); err != nil || ok {
+		t.Errorf("expected a stale swap to fail, got %v %v", ok, err)
+	}
+	if ok, err := client.Sprint(ctx, key).CompareAndSwap(1, 2); err != nil || !ok {
+		t.Errorf("expected 1 -> 2, got %v %v", ok, err)
+	}
+	if n, _ := client.Sprint(ctx, key).Get(); n != 2 {
+		t.Errorf("expected 2, got %d", n)
+	}
+}
+
+func TestSprintBounded(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:bounded"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	stock := client.Sprint(ctx, key).Bounded(0, 10)
+	if n, clamped, err := stock.IncrBy(4); err != nil || clamped || n != 4 {
+		t.Errorf("expected 4, got %v %v %v", n, clamped, err)
+	}
+	if n, clamped, err := stock.DecrBy(6); err != nil || !clamped || n != 0 {
+		t.Errorf("expected clamping to 0, got %v %v %v", n, clamped, err)
+	}
+	if n, clamped, err := stock.IncrBy(25); err != nil || !clamped || n != 10 {
+		t.Errorf("expected clamping to 10, got %v %v %v", n, clamped, err)
+	}
+}
+
+func TestSprintWindowed(t *testing.T) {
+	clock := gibrun.NewManualClock(time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC))
+	client := gibrun.New(gibrun.Config{
+		Addr:  "localhost:6379",
+		Clock: clock,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	name := "test:gibrun:windowed"
+	rpm := client.Sprint(ctx, name).Windowed(time.Minute).Keep(5)
+	series, _ := rpm.Series(5)
+	for _, s := range series {
+		client.Del(ctx, name+":1m0s:"+strconv.FormatInt(s.Start.UnixMilli(), 10))
+	}
+
+	rpm.IncrBy(3)
+	clock.Advance(time.Minute)
+	rpm.IncrBy(2)
+	clock.Advance(2 * time.Minute)
+	rpm.Incr()
+
+	series, err := rpm.Series(4)
+	if err != nil {
+		t.Fatalf("Series failed: %v", err)
+	}
+	want := []int64{3, 2, 0, 1}
+	for i, s := range series {
+		if s.Count != want[i] {
+			t.Errorf("bucket %d (%v): expected %d, got %d", i, s.Start, want[i], s.Count)
+		}
+		client.Del(ctx, name+":1m0s:"+strconv.FormatInt(s.Start.UnixMilli(), 10))
+	}
+	if sum, _ := rpm.SumLast(4); sum != 6 {
+		t.Errorf("expected SumLast(4) = 6, got %d", sum)
+	}
+
+	// Sub-second buckets must not share a key
+	fast := client.Sprint(ctx, name).Windowed(500 * time.Millisecond).Keep(4)
+	now := clock.Now().UnixMilli()
+	fastKeys := []string{
+		name + ":500ms:" + strconv.FormatInt(now, 10),
+		name + ":500ms:" + strconv.FormatInt(now+500, 10),
+	}
+	client.Del(ctx, fastKeys...)
+	defer client.Del(ctx, fastKeys...)
+
+	fast.IncrBy(4)
+	clock.Advance(500 * time.Millisecond)
+	fast.IncrBy(5)
+	series, err = fast.Series(2)
+	if err != nil || len(series) != 2 || series[0].Count != 4 || series[1].Count != 5 {
+		t.Errorf("expected sub-second buckets [4 5], got %+v %v", series, err)
+	}
+}
+
+func TestSprintWindowedInvalidBucket(t *testing.T) {
+	client := gibrun.New(gibrun.Config{Addr: "localhost:6379"})
+	defer client.Close()
+
+	ctx := context.Background()
+	for _, bucket := range []time.Duration{0, -time.Minute, time.Microsecond} {
+		w := client.Sprint(ctx, "test:gibrun:windowed:invalid").Windowed(bucket)
+		var cfgErr *gibrun.ConfigError
+		if _, err := w.Incr(); !errors.As(err, &cfgErr) {
+			t.Errorf("bucket %v: expected a ConfigError from Incr, got %v", bucket, err)
+		}
+		if _, err := w.Series(3); !errors.As(err, &cfgErr) {
+			t.Errorf("bucket %v: expected a ConfigError from Series, got %v", bucket, err)
+		}
+	}
+}
+
+func TestSprintResetAndGet(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:resetandget"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	if n, err := client.Sprint(ctx, key).ResetAndGet(); err != nil || n != 0 {
+		t.Errorf("expected 0 for a missing key, got %v %v", n, err)
+	}
+
+	client.Sprint(ctx, key).IncrBy(7)
+	if n, err := client.Sprint(ctx, key).ResetAndGet(); err != nil || n != 7 {
+		t.Errorf("expected 7, got %v %v", n, err)
+	}
+	if n, _ := client.Sprint(ctx, key).Get(); n != 0 {
+		t.Errorf("expected counter to be reset, got %d", n)
+	}
+}
+
+func TestSprintGetFloat(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:getfloat"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	if v, err := client.Sprint(ctx, key).GetFloat(); err != nil || v != 0 {
+		t.Errorf("expected 0 for a missing key, got %v %v", v, err)
+	}
+
+	client.Sprint(ctx, key).IncrByFloat(1.25)
+	client.Sprint(ctx, key).IncrByFloat(0.5)
+	if v, err := client.Sprint(ctx, key).GetFloat(); err != nil || v != 1.75 {
+		t.Errorf("expected 1.75, got %v %v", v, err)
+	}
+}
+
+func TestSprintMany(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	a, b, c := "test:gibrun:sprintmany:a", "test:gibrun:sprintmany:b", "test:gibrun:sprintmany:c"
+	client.Del(ctx, a, b, c)
+	defer client.Del(ctx, a, b, c)
+
+	vals, err := client.SprintMany(ctx).Incr(a).IncrBy(b, 5).Decr(c).Incr(a).Exec()
+	if err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+	if vals[a] != 2 || vals[b] != 5 || vals[c] != -1 {
+		t.Errorf("unexpected values: %v", vals)
+	}
+}
+
+func TestSprintRate(t *testing.T) {
+	clock := gibrun.NewManualClock(time.Now())
+	client := gibrun.New(gibrun.Config{
+		Addr:  "localhost:6379",
+		Clock: clock,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:rate"
+	client.Del(ctx, key, key+":rate")
+	defer client.Del(ctx, key, key+":rate")
+
+	counter := client.Sprint(ctx, key)
+	if r, err := counter.Rate(time.Minute); err != nil || r != 0 {
+		t.Errorf("expected 0 with a single sample, got %v %v", r, err)
+	}
+
+	counter.IncrBy(100)
+	clock.Advance(10 * time.Second)
+	if r, err := counter.Rate(time.Minute); err != nil || r != 10 {
+		t.Errorf("expected 10/s, got %v %v", r, err)
+	}
+
+	// Samples older than the window no longer count
+	clock.Advance(2 * time.Minute)
+	counter.IncrBy(60)
+	if r, err := counter.Rate(time.Minute); err != nil || r != 0 {
+		t.Errorf("expected 0 after the window passed, got %v %v", r, err)
+	}
+}
+
+func TestSprintField(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:fieldsprint"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	stats := client.Sprint(ctx, key)
+	stats.Field("clicks").Incr()
+	stats.Field("clicks").Incr()
+	stats.Field("shares").IncrBy(3)
+	stats.Field("shares").Decr()
+
+	if n, err := stats.Field("clicks").Get(); err != nil || n != 2 {
+		t.Errorf("expected 2 clicks, got %v %v", n, err)
+	}
+	if n, err := stats.Field("missing").Get(); err != nil || n != 0 {
+		t.Errorf("expected 0 for a missing field, got %v %v", n, err)
+	}
+
+	all, err := stats.Fields()
+	if err != nil {
+		t.Fatalf("Fields failed: %v", err)
+	}
+	if len(all) != 2 || all["clicks"] != 2 || all["shares"] != 2 {
+		t.Errorf("unexpected fields: %v", all)
+	}
+}
+
+func TestAntri(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type Job struct {
+		ID int `json:"id"`
+	}
+
+	key := "test:gibrun:antri"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	jobs := client.Antri(ctx, key)
+	if n, err := jobs.Push(Job{ID: 1}, Job{ID: 2}, Job{ID: 3}); err != nil || n != 3 {
+		t.Fatalf("Push failed: %v %v", n, err)
+	}
+
+	var all []Job
+	if err := jobs.Range(0, -1).Bind(&all); err != nil || len(all) != 3 || all[0].ID != 1 {
+		t.Errorf("unexpected Range result: %v %v", all, err)
+	}
+
+	var job Job
+	if found, err := jobs.PopLeft(&job); err != nil || !found || job.ID != 1 {
+		t.Errorf("expected job 1, got %v %v %v", job, found, err)
+	}
+
+	jobs.Trim(1)
+	if n, _ := jobs.Len(); n != 1 {
+		t.Errorf("expected 1 item after Trim, got %d", n)
+	}
+	if found, err := jobs.BPopLeft(time.Second, &job); err != nil || !found || job.ID != 3 {
+		t.Errorf("expected job 3, got %v %v %v", job, found, err)
+	}
+	if found, err := jobs.BPopLeft(100*time.Millisecond, &job); err != nil || found {
+		t.Errorf("expected timeout on an empty list, got %v %v", found, err)
+	}
+}
+
+func TestKoalisi(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	premium, active, out := "test:gibrun:koalisi:premium", "test:gibrun:koalisi:active", "test:gibrun:koalisi:out"
+	client.Del(ctx, premium, active, out)
+	defer client.Del(ctx, premium, active, out)
+
+	if n, err := client.Koalisi(ctx, premium).Add(1, 2, 3, 3); err != nil || n != 3 {
+		t.Fatalf("Add failed: %v %v", n, err)
+	}
+	client.Koalisi(ctx, active).Add(2, 3, 4)
+
+	if ok, _ := client.Koalisi(ctx, premium).IsMember(2); !ok {
+		t.Error("expected 2 to be a member")
+	}
+
+	var ids []int
+	if err := client.Koalisi(ctx, premium).Intersect(active).Bind(&ids); err != nil {
+		t.Fatalf("Intersect failed: %v", err)
+	}
+	sort.Ints(ids)
+	if !reflect.DeepEqual(ids, []int{2, 3}) {
+		t.Errorf("expected [2 3], got %v", ids)
+	}
+
+	if err := client.Koalisi(ctx, premium).Diff(active).Bind(&ids); err != nil || !reflect.DeepEqual(ids, []int{1}) {
+		t.Errorf("expected [1], got %v %v", ids, err)
+	}
+
+	if n, err := client.Koalisi(ctx, premium).Union(active).Store(out); err != nil || n != 4 {
+		t.Errorf("expected a union of 4, got %v %v", n, err)
+	}
+
+	client.Koalisi(ctx, premium).Remove(1)
+	if n, _ := client.Koalisi(ctx, premium).Len(); n != 2 {
+		t.Errorf("expected 2 members after Remove, got %d", n)
+	}
+}
+
+func TestPodium(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:podium"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	board := client.Podium(ctx, key)
+	for i, name := range []string{"a", "b", "c", "d", "e"} {
+		board.AddScore(name, float64(10*(i+1)))
+	}
+	board.IncrScore("a", 100) // a: 110
+
+	var top []string
+	if err := board.Top(3).Bind(&top); err != nil || !reflect.DeepEqual(top, []string{"a", "e", "d"}) {
+		t.Errorf("expected [a e d], got %v %v", top, err)
+	}
+
+	if rank, found, err := board.Rank("d"); err != nil || !found || rank != 2 {
+		t.Errorf("expected d at rank 2, got %v %v %v", rank, found, err)
+	}
+	if _, found, _ := board.Rank("zzz"); found {
+		t.Error("expected missing member to have no rank")
+	}
+
+	around, err := board.Around("c", 1).Entries()
+	if err != nil || len(around) != 3 || around[0].Member != "d" || around[1].Rank != 3 || around[2].Member != "b" {
+		t.Errorf("unexpected Around result: %+v %v", around, err)
+	}
+
+	byScore, err := board.RangeByScore(20, 40).Entries()
+	if err != nil || len(byScore) != 3 || byScore[0].Member != "d" || byScore[0].Rank != 2 {
+		t.Errorf("unexpected RangeByScore result: %+v %v", byScore, err)
+	}
+
+	var lowest []string
+	if err := client.Podium(ctx, key).Ascending().Top(2).Bind(&lowest); err != nil || !reflect.DeepEqual(lowest, []string{"b", "c"}) {
+		t.Errorf("expected [b c], got %v %v", lowest, err)
+	}
+}
+
+func TestPodiumSeason(t *testing.T) {
+	clock := gibrun.NewManualClock(time.Unix(1700000000, 0))
+	client := gibrun.New(gibrun.Config{
+		Addr:  "localhost:6379",
+		Clock: clock,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key, archive := "test:gibrun:podium:season", "test:gibrun:podium:season:1"
+	client.Del(ctx, key, archive)
+	defer client.Del(ctx, key, archive)
+
+	board := client.Podium(ctx, key).StableTies()
+	board.IncrScore("late", 10) // overtaken below
+	clock.Advance(time.Second)
+	board.IncrScore("first", 50)
+	clock.Advance(time.Second)
+	board.IncrScore("second", 50)
+	clock.Advance(time.Second)
+	board.IncrScore("late", 40)
+
+	var order []string
+	if err := board.Top(3).Bind(&order); err != nil || !reflect.DeepEqual(order, []string{"first", "second", "late"}) {
+		t.Errorf("expected ties broken by time, got %v %v", order, err)
+	}
+	if score, _, err := board.Score("late"); err != nil || score != 50 {
+		t.Errorf("expected integer score 50, got %v %v", score, err)
+	}
+
+	page, err := board.Page(2, 2).Entries()
+	if err != nil || len(page) != 1 || page[0].Member != "late" || page[0].Rank != 2 {
+		t.Errorf("unexpected page 2: %+v %v", page, err)
+	}
+
+	if pct, found, err := board.PercentileRank("second"); err != nil || !found || pct != 50 {
+		t.Errorf("expected 50th percentile, got %v %v %v", pct, found, err)
+	}
+
+	if ok, err := board.ArchiveTo(archive); err != nil || !ok {
+		t.Fatalf("ArchiveTo failed: %v %v", ok, err)
+	}
+	if n, _ := board.Len(); n != 0 {
+		t.Errorf("expected fresh board after archiving, got %d members", n)
+	}
+	board.AddScore("first", 1)
+	if _, err := board.ArchiveTo(archive); !errors.Is(err, gibrun.ErrArchiveExists) {
+		t.Errorf("expected ErrArchiveExists, got %v", err)
+	}
+}
+
+func TestKabinet(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type User struct {
+		Name  string `redis:"name"`
+		Email string `redis:"email"`
+		Bio   string `redis:"bio,omitempty"`
+		Age   int    `redis:"age"`
+	}
+
+	key := "test:gibrun:kabinet"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	user := User{Name: "Budi", Email: "budi@example.com", Bio: "hi", Age: 30}
+	if err := client.Kabinet(ctx, key).Value(user).Exec(); err != nil {
+		t.Fatalf("Exec failed: %v", err)
+	}
+
+	// Only email is written; Bio is cleared and Age stays as stored
+	update := User{Email: "new@example.com", Age: 99}
+	if err := client.Kabinet(ctx, key).Value(update).SetFields("email", "bio"); err != nil {
+		t.Fatalf("SetFields failed: %v", err)
+	}
+	if err := client.Kabinet(ctx, key).Value(update).SetFields("nope"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+
+	var got User
+	if found, err := client.Kabinet(ctx, key).Bind(&got); err != nil || !found {
+		t.Fatalf("Bind failed: %v %v", found, err)
+	}
+	if (got != User{Name: "Budi", Email: "new@example.com", Age: 30}) {
+		t.Errorf("unexpected user: %+v", got)
+	}
+
+	if n, err := client.Kabinet(ctx, key).DelFields("age"); err != nil || n != 1 {
+		t.Errorf("expected 1 deleted field, got %v %v", n, err)
+	}
+	if fields, _ := client.Kabinet(ctx, key).Fields(); len(fields) != 2 {
+		t.Errorf("expected 2 fields left, got %v", fields)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	day1, day2, week := "test:gibrun:unique:1", "test:gibrun:unique:2", "test:gibrun:unique:week"
+	client.Del(ctx, day1, day2, week)
+	defer client.Del(ctx, day1, day2, week)
+
+	if changed, err := client.Unique(ctx, day1).Add(1, 2, 3); err != nil || !changed {
+		t.Fatalf("Add failed: %v %v", changed, err)
+	}
+	if changed, _ := client.Unique(ctx, day1).Add(2); changed {
+		t.Error("expected re-adding an item to leave the estimate unchanged")
+	}
+	client.Unique(ctx, day2).Add(3, 4)
+
+	if n, err := client.Unique(ctx, day1).Count(); err != nil || n != 3 {
+		t.Errorf("expected 3, got %v %v", n, err)
+	}
+	if n, err := client.Unique(ctx, day1).CountWith(day2); err != nil || n != 4 {
+		t.Errorf("expected 4 across days, got %v %v", n, err)
+	}
+	if err := client.Unique(ctx, day1).MergeInto(week, day2); err != nil {
+		t.Fatalf("MergeInto failed: %v", err)
+	}
+	if n, _ := client.Unique(ctx, week).Count(); n != 4 {
+		t.Errorf("expected 4 in the merged key, got %d", n)
+	}
+}
+
+func TestAbsen(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	day1, day2, both, user := "test:gibrun:absen:1", "test:gibrun:absen:2", "test:gibrun:absen:both", "test:gibrun:absen:user"
+	client.Del(ctx, day1, day2, both, user)
+	defer client.Del(ctx, day1, day2, both, user)
+
+	for _, id := range []int64{1, 5, 9, 20} {
+		client.Absen(ctx, day1).SetBit(id, true)
+	}
+	client.Absen(ctx, day2).SetBit(5, true)
+	client.Absen(ctx, day2).SetBit(20, true)
+
+	if on, _ := client.Absen(ctx, day1).GetBit(9); !on {
+		t.Error("expected bit 9 to be set")
+	}
+	if n, err := client.Absen(ctx, day1).CountRange(2, 19); err != nil || n != 2 {
+		t.Errorf("expected 2 bits in [2, 19], got %v %v", n, err)
+	}
+	if _, err := client.Absen(ctx, day1).BitOp(gibrun.BitAnd, both, day2); err != nil {
+		t.Fatalf("BitOp failed: %v", err)
+	}
+	if n, _ := client.Absen(ctx, both).Count(); n != 2 {
+		t.Errorf("expected 2 users on both days, got %d", n)
+	}
+
+	now := time.Now()
+	streak := client.Absen(ctx, user)
+	for _, daysAgo := range []int{0, 1, 2, 4} {
+		streak.MarkDay(now.AddDate(0, 0, -daysAgo))
+	}
+	if n, err := streak.Streak(now); err != nil || n != 3 {
+		t.Errorf("expected a 3 day streak, got %v %v", n, err)
+	}
+	if n, _ := streak.CountDays(now.AddDate(0, 0, -6), now); n != 4 {
+		t.Errorf("expected 4 active days, got %d", n)
+	}
+}
+
+func TestBitfieldTypes(t *testing.T) {
+	if gibrun.U(8) != "u8" || gibrun.I(16) != "i16" {
+		t.Errorf("unexpected types %q %q", gibrun.U(8), gibrun.I(16))
+	}
+	if off := gibrun.U(4).Offset(3); off != 12 {
+		t.Errorf("expected offset 12, got %d", off)
+	}
+}
+
+func TestBitfield(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:bitfield"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	u8 := gibrun.U(8)
+	vals, err := client.Bitfield(ctx, key).
+		Set(u8, u8.Offset(0), 250).
+		Overflow(gibrun.OverflowSat).
+		IncrBy(u8, u8.Offset(0), 10).
+		IncrBy(u8, u8.Offset(1), 3).
+		Get(u8, u8.Offset(1)).
+		Exec()
+	if err != nil || !reflect.DeepEqual(vals, []int64{0, 255, 3, 3}) {
+		t.Errorf("expected [0 255 3 3], got %v %v", vals, err)
+	}
+
+	vals, err = client.Bitfield(ctx, key).Overflow(gibrun.OverflowFail).IncrBy(u8, 0, 1).Exec()
+	if !errors.Is(err, gibrun.ErrBitfieldOverflow) || len(vals) != 1 {
+		t.Errorf("expected ErrBitfieldOverflow, got %v %v", vals, err)
+	}
+}
+
+func TestStreamConsume(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type Event struct {
+		N int `json:"n"`
+	}
+
+	key := "test:gibrun:stream"
+	source := "stream:" + key + ":workers"
+	client.Del(ctx, key)
+	client.Failures().Purge(ctx, source)
+	defer client.Del(ctx, key)
+	defer client.Failures().Purge(ctx, source)
+
+	for i := 1; i <= 3; i++ {
+		if _, err := client.Stream(ctx, key).MaxLen(100).Add(Event{N: i}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+	}
+
+	consumeCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	seen := map[int]int{}
+	err := client.Stream(consumeCtx, key).
+		Block(100*time.Millisecond).
+		ClaimIdle(100*time.Millisecond).
+		MaxDeliveries(2).
+		Consume("workers", "w1", func(ctx context.Context, msg gibrun.StreamMessage) error {
+			var ev Event
+			if err := msg.Bind(&ev); err != nil {
+				return err
+			}
+			mu.Lock()
+			seen[ev.N]++
+			done := seen[1] == 1 && seen[2] == 2 && seen[3] == 2
+			mu.Unlock()
+			if done {
+				defer cancel()
+			}
+			switch {
+			case ev.N == 2 && msg.Deliveries == 1:
+				return errors.New("transient failure")
+			case ev.N == 3:
+				return errors.New("poison message")
+			}
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("Consume failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if seen[1] != 1 || seen[2] != 2 || seen[3] != 2 {
+		t.Errorf("unexpected deliveries: %v", seen)
+	}
+	failures, _ := client.Failures().List(ctx, source, 0)
+	if len(failures) != 1 {
+		t.Errorf("expected the poison message in the failure log, got %d records", len(failures))
+	}
+}
+
+func TestFailureLogRecord(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	source := "test:failures"
+	fl := client.Failures()
+	if client.Failures() != fl {
+		t.Fatal("expected Failures to return the same log every call")
+	}
+	fl.Purge(ctx, source)
+	defer fl.Purge(ctx, source)
+
+	fl.MaxPerSource, fl.MaxPayload = 3, 4
+	defer func() { fl.MaxPerSource, fl.MaxPayload = 1000, 64*1024 }()
+
+	if err := fl.Record(ctx, source, "job:nil", nil, nil); err != nil {
+		t.Fatalf("Record with a nil cause failed: %v", err)
+	}
+	for i := 1; i <= 4; i++ {
+		err := fl.Record(ctx, source, "job:"+strconv.Itoa(i), []byte("payload"), context.DeadlineExceeded)
+		if err != nil {
+			t.Fatalf("Record failed: %v", err)
+		}
+	}
+
+	// Limits set on one Failures() call apply to later calls
+	failures, err := client.Failures().List(ctx, source, 0)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(failures) != 3 {
+		t.Fatalf("expected the log trimmed to 3 records, got %d", len(failures))
+	}
+	if failures[0].Key != "job:4" || failures[2].Key != "job:2" {
+		t.Errorf("expected the newest records first, got %s..%s", failures[0].Key, failures[2].Key)
+	}
+	if f := failures[0]; f.Class != "timeout" || string(f.Payload) != "payl" {
+		t.Errorf("unexpected record: class %q payload %q", f.Class, f.Payload)
+	}
+
+	fl.MaxPerSource = 10
+	fl.Record(ctx, source, "job:nil", nil, nil)
+	failures, _ = fl.List(ctx, source, 1)
+	if len(failures) != 1 || failures[0].Error != "unknown" {
+		t.Errorf("expected a nil cause recorded as unknown, got %+v", failures)
+	}
+	sources, _ := fl.Sources(ctx)
+	if !strings.Contains(","+strings.Join(sources, ",")+",", ","+source+",") {
+		t.Errorf("expected %q among the sources, got %v", source, sources)
+	}
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type Update struct {
+		N int `json:"n"`
+	}
+
+	channel := "test:gibrun:siaran"
+	got := make(chan int, 10)
+	var errs atomic.Int32
+	sub, err := gibrun.SubscribeWith(ctx, client, channel, gibrun.SubscribeOptions{
+		OnError: func(string, error) { errs.Add(1) },
+	}, func(u Update) error {
+		got <- u.N
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Subscribe failed: %v", err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if n, err := client.Publish(ctx, channel, Update{N: i}); err != nil || n != 1 {
+			t.Fatalf("Publish failed: %v %v", n, err)
+		}
+	}
+	client.Do(ctx, "PUBLISH", channel, "not json")
+
+	for i := 1; i <= 3; i++ {
+		select {
+		case n := <-got:
+			if n != i {
+				t.Errorf("expected message %d, got %d", i, n)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for message %d", i)
+		}
+	}
+
+	if err := sub.Close(); err != nil {
+		t.Errorf("Close failed: %v", err)
+	}
+	if errs.Load() != 1 {
+		t.Errorf("expected the malformed message to be reported, got %d errors", errs.Load())
+	}
+	if n, _ := client.Publish(ctx, channel, Update{N: 4}); n != 0 {
+		t.Errorf("expected no subscribers after Close, got %d", n)
+	}
+}
+
+func TestWatchExpirations(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	keys := make(chan string, 10)
+	sub, err := client.WatchExpirations(ctx, "test:gibrun:watch:*", func(key string) {
+		keys <- key
+	})
+	if errors.Is(err, gibrun.ErrKeyspaceEventsDisabled) {
+		t.Skip("keyspace notifications unavailable")
+	}
+	if err != nil {
+		t.Fatalf("WatchExpirations failed: %v", err)
+	}
+	defer sub.Close()
+
+	client.Gib(ctx, "test:gibrun:watch:a").Value("x").TTL(100 * time.Millisecond).Exec()
+	client.Gib(ctx, "test:gibrun:watch:b").Value("x").Exec()
+	client.Gib(ctx, "test:gibrun:other").Value("x").Exec()
+	client.Del(ctx, "test:gibrun:watch:b", "test:gibrun:other")
+
+	got := map[string]bool{}
+	timeout := time.After(3 * time.Second)
+	for len(got) < 2 {
+		select {
+		case key := <-keys:
+			got[key] = true
+		case <-timeout:
+			t.Fatalf("timed out, got %v", got)
+		}
+	}
+	if !got["test:gibrun:watch:a"] || !got["test:gibrun:watch:b"] {
+		t.Errorf("unexpected keys: %v", got)
+	}
+}
+
+func TestGibRunAsJSON(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type Profile struct {
+		City string `json:"city"`
+	}
+	type User struct {
+		Name    string  `json:"name"`
+		Profile Profile `json:"profile"`
+	}
+
+	key := "test:gibrun:asjson"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+
+	err := client.Gib(ctx, key).AsJSON().Value(User{Name: "Budi", Profile: Profile{City: "Bandung"}}).Exec()
+	if errors.Is(err, gibrun.ErrUnsupported) {
+		t.Skip("RedisJSON not available")
+	}
+	if err != nil {
+		t.Fatalf("AsJSON Exec failed: %v", err)
+	}
+
+	if err := client.Gib(ctx, key).AsJSON().Path("$.profile").Value(Profile{City: "Jakarta"}).Exec(); err != nil {
+		t.Fatalf("Path Exec failed: %v", err)
+	}
+
+	var user User
+	if found, err := client.Run(ctx, key).AsJSON().Bind(&user); err != nil || !found {
+		t.Fatalf("Bind failed: %v %v", found, err)
+	}
+	if user.Name != "Budi" || user.Profile.City != "Jakarta" {
+		t.Errorf("unexpected document: %+v", user)
+	}
+
+	var city string
+	if found, err := client.Run(ctx, key).AsJSON().Path("$.profile.city").Bind(&city); err != nil || !found || city != "Jakarta" {
+		t.Errorf("expected Jakarta, got %q %v %v", city, found, err)
+	}
+	if found, err := client.Run(ctx, key).AsJSON().Path("$.missing").Bind(&city); err != nil || found {
+		t.Errorf("expected a missing path to be not found, got %v %v", found, err)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	type NoTags struct{ Name string }
+	if err := client.CreateIndex(ctx, "idx:bad", NoTags{}, gibrun.IndexOptions{}); err == nil {
+		t.Error("expected an error for a schema without search tags")
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type User struct {
+		Name string `redis:"name" search:"text,sortable"`
+		City string `redis:"city" search:"tag"`
+		Age  int    `redis:"age" search:"numeric"`
+	}
+
+	index, prefix := "test:gibrun:idx:users", "test:gibrun:search:"
+	client.DropIndex(ctx, index)
+	err := client.CreateIndex(ctx, index, User{}, gibrun.IndexOptions{Prefixes: []string{prefix}})
+	if errors.Is(err, gibrun.ErrUnsupported) {
+		t.Skip("RediSearch not available")
+	}
+	if err != nil {
+		t.Fatalf("CreateIndex failed: %v", err)
+	}
+	defer client.DropIndex(ctx, index)
+
+	users := []User{{"Ani", "Jakarta", 30}, {"Budi", "Bandung", 25}, {"Citra", "Jakarta", 17}}
+	for i, u := range users {
+		key := prefix + strconv.Itoa(i)
+		client.Kabinet(ctx, key).Value(u).Exec()
+		defer client.Del(ctx, key)
+	}
+
+	// Indexing is asynchronous for the initial scan only; new writes are immediate
+	var got []User
+	total, err := client.Search(ctx, index).
+		Query("@city:{Jakarta}").
+		SortBy("name", true).
+		Limit(0, 10).
+		Bind(&got)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if total != 2 || len(got) != 2 || got[0] != users[0] || got[1] != users[2] {
+		t.Errorf("unexpected results: %d %+v", total, got)
+	}
+}
+
+func TestTimeSeries(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key, hourly := "test:gibrun:ts", "test:gibrun:ts:hourly"
+	client.Del(ctx, key, hourly)
+	defer client.Del(ctx, key, hourly)
+
+	ts := client.TimeSeries(ctx, key).
+		Retention(24 * time.Hour).
+		Labels(map[string]string{"test": "gibrun-ts"})
+	err := ts.Downsample(hourly, gibrun.AggAvg, time.Hour)
+	if errors.Is(err, gibrun.ErrUnsupported) {
+		t.Skip("RedisTimeSeries not available")
+	}
+	if err != nil {
+		t.Fatalf("Downsample failed: %v", err)
+	}
+
+	base := time.Now().Truncate(time.Hour).Add(-2 * time.Hour)
+	for i, v := range []float64{10, 20, 30} {
+		if err := ts.AddAt(base.Add(time.Duration(i)*time.Minute), v); err != nil {
+			t.Fatalf("AddAt failed: %v", err)
+		}
+	}
+
+	samples, err := ts.Range(base, base.Add(time.Hour))
+	if err != nil || len(samples) != 3 || samples[1].Value != 20 || !samples[1].Time.Equal(base.Add(time.Minute)) {
+		t.Errorf("unexpected samples: %+v %v", samples, err)
+	}
+
+	avg, err := ts.RangeAggregated(base, base.Add(time.Hour), gibrun.AggAvg, time.Hour)
+	if err != nil || len(avg) != 1 || avg[0].Value != 20 {
+		t.Errorf("expected an average of 20, got %+v %v", avg, err)
+	}
+
+	all, err := client.TimeSeriesMRange(ctx, base, base.Add(time.Hour), "test=gibrun-ts")
+	if err != nil || len(all[key]) != 3 {
+		t.Errorf("unexpected MRange result: %v %v", all, err)
+	}
+}
+
+func TestBloomCuckoo(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	bloomKey, cuckooKey := "test:gibrun:bloom", "test:gibrun:cuckoo"
+	client.Del(ctx, bloomKey, bloomKey+":bloom", cuckooKey)
+	defer client.Del(ctx, bloomKey, bloomKey+":bloom", cuckooKey)
+
+	seen := client.Bloom(ctx, bloomKey).Capacity(1000).ErrorRate(0.01)
+	added, err := seen.Add("a", "b", "a")
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if !added[0] || !added[1] {
+		t.Errorf("expected new items to be reported as added, got %v", added)
+	}
+	if again, _ := seen.Add("b"); again[0] {
+		t.Error("expected a re-added item to be reported as seen")
+	}
+	for _, item := range []string{"a", "b"} {
+		if ok, err := seen.Exists(item); err != nil || !ok {
+			t.Errorf("expected %q to exist, got %v %v", item, ok, err)
+		}
+	}
+	falsePositives := 0
+	for i := 0; i < 200; i++ {
+		if ok, _ := seen.Exists("missing-" + strconv.Itoa(i)); ok {
+			falsePositives++
+		}
+	}
+	if falsePositives > 10 {
+		t.Errorf("too many false positives: %d/200", falsePositives)
+	}
+
+	active := client.Cuckoo(ctx, cuckooKey)
+	if ok, err := active.Add("s1"); err != nil || !ok {
+		t.Fatalf("Add failed: %v %v", ok, err)
+	}
+	if ok, _ := active.Exists("s1"); !ok {
+		t.Error("expected s1 to exist")
+	}
+	if ok, err := active.Delete("s1"); err != nil || !ok {
+		t.Errorf("Delete failed: %v %v", ok, err)
+	}
+	if ok, _ := active.Exists("s1"); ok {
+		t.Error("expected s1 to be gone after Delete")
+	}
+}
+
+func TestBloomInvalidParams(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:bloom:invalid"
+	client.Del(ctx, key, key+":bloom")
+	defer client.Del(ctx, key, key+":bloom")
+
+	cases := []*gibrun.BloomBuilder{
+		client.Bloom(ctx, key).ErrorRate(0),
+		client.Bloom(ctx, key).ErrorRate(1),
+		client.Bloom(ctx, key).ErrorRate(-0.5),
+		client.Bloom(ctx, key).Capacity(0),
+		client.Bloom(ctx, key).Capacity(-10),
+	}
+	for i, b := range cases {
+		if _, err := b.Add("a"); err == nil {
+			t.Errorf("case %d: expected Add to reject the parameters", i)
+		}
+	}
+}
+
+func TestTDigest(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	a, b, all := "test:gibrun:tdigest:a", "test:gibrun:tdigest:b", "test:gibrun:tdigest:all"
+	client.Del(ctx, a, b, all)
+	defer client.Del(ctx, a, b, all)
+
+	for i := 1; i <= 50; i++ {
+		if err := client.TDigest(ctx, a).Add(float64(i)); errors.Is(err, gibrun.ErrUnsupported) {
+			t.Skip("RedisBloom not available")
+		} else if err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		client.TDigest(ctx, b).Add(float64(50 + i))
+	}
+
+	if err := client.TDigest(ctx, a).MergeInto(all, b); err != nil {
+		t.Fatalf("MergeInto failed: %v", err)
+	}
+	q, err := client.TDigest(ctx, all).Quantiles(0.5, 0.99)
+	if err != nil {
+		t.Fatalf("Quantiles failed: %v", err)
+	}
+	if math.Abs(q[0]-50) > 2 || math.Abs(q[1]-99) > 2 {
+		t.Errorf("unexpected quantiles: %v", q)
+	}
+}
+
+func TestPSubscribe(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	type routed struct {
+		channel string
+		keys    []string
+	}
+	got := make(chan routed, 10)
+	sub, err := gibrun.PSubscribe(ctx, client, "test:gibrun:invalidate:*", func(channel string, keys []string) error {
+		got <- routed{channel, keys}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("PSubscribe failed: %v", err)
+	}
+	defer sub.Close()
+
+	client.Publish(ctx, "test:gibrun:invalidate:users", []string{"user:1"})
+	client.Publish(ctx, "test:gibrun:other", []string{"ignored"})
+	client.Publish(ctx, "test:gibrun:invalidate:products", []string{"product:9", "product:10"})
+
+	want := []routed{
+		{"test:gibrun:invalidate:users", []string{"user:1"}},
+		{"test:gibrun:invalidate:products", []string{"product:9", "product:10"}},
+	}
+	for _, w := range want {
+		select {
+		case r := <-got:
+			if !reflect.DeepEqual(r, w) {
+				t.Errorf("expected %+v, got %+v", w, r)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %s", w.channel)
+		}
+	}
+}
+
+func TestKerja(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := gibrun.NewKerja(client, gibrun.KerjaConfig{}).Err(); err == nil {
+		t.Error("expected an error for a missing Name")
+	}
+	if err := gibrun.NewKerja(client, gibrun.KerjaConfig{Name: "q", Visibility: time.Millisecond}).Err(); err == nil {
+		t.Error("expected an error for a Visibility under 3ms")
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	name := "{test:gibrun:kerja}"
+	keys := []string{name, name + ":inflight", name + ":jobs", name + ":attempts", "gibrun:failures:kerja:" + name}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	queue := gibrun.NewKerja(client, gibrun.KerjaConfig{
+		Name:         name,
+		MaxAttempts:  2,
+		RetryDelay:   time.Millisecond,
+		PollInterval: 10 * time.Millisecond,
+	})
+	for i := 0; i < 10; i++ {
+		if _, err := queue.Enqueue(ctx, map[string]int{"n": i}); err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+	}
+	poison, _ := queue.Enqueue(ctx, map[string]int{"n": -1})
+
+	var mu sync.Mutex
+	seen := map[int]bool{}
+	attempts := 0
+	workCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.Work(workCtx, 4, func(ctx context.Context, job gibrun.Job) error {
+			var p map[string]int
+			if err := job.Bind(&p); err != nil {
+				return err
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if p["n"] < 0 {
+				attempts++
+				return errors.New("poison")
+			}
+			seen[p["n"]] = true
+			return nil
+		})
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		finished := len(seen) == 10 && attempts == 2
+		mu.Unlock()
+		if finished {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Work returned %v", err)
+	}
+
+	if len(seen) != 10 || attempts != 2 {
+		t.Errorf("expected 10 jobs and 2 poison attempts, got %d and %d", len(seen), attempts)
+	}
+	if n, _ := queue.Len(ctx); n != 0 {
+		t.Errorf("expected empty queue, got %d", n)
+	}
+	failed, _ := client.Failures().List(ctx, "kerja:"+name, 10)
+	if len(failed) != 1 || failed[0].Key != poison {
+		t.Errorf("expected poison job in the failure log, got %+v", failed)
+	}
+}
+
+func TestKerjaStopsWhileIdle(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	name := "{test:gibrun:kerja:idle}"
+	client.Del(ctx, name, name+":inflight")
+
+	// An idle worker must not sit out its PollInterval after cancel
+	queue := gibrun.NewKerja(client, gibrun.KerjaConfig{Name: name, PollInterval: time.Minute})
+	workCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- queue.Work(workCtx, 2, func(ctx context.Context, job gibrun.Job) error { return nil }) }()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Work failed: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Work to return promptly after cancel")
+	}
+}
+
+func TestKerjaDelayed(t *testing.T) {
+	clock := gibrun.NewManualClock(time.Unix(1700000000, 0))
+	client := gibrun.New(gibrun.Config{
+		Addr:  "localhost:6379",
+		Clock: clock,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	name := "{test:gibrun:kerja:delayed}"
+	keys := []string{name, name + ":inflight", name + ":jobs", name + ":attempts"}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	queue := gibrun.NewKerja(client, gibrun.KerjaConfig{Name: name, PollInterval: time.Millisecond})
+	queue.EnqueueIn(ctx, "later", 24*time.Hour)
+	queue.EnqueueAt(ctx, "overdue", clock.Now().Add(-time.Minute))
+
+	var mu sync.Mutex
+	var ran []string
+	workCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- queue.Work(workCtx, 2, func(ctx context.Context, job gibrun.Job) error {
+			mu.Lock()
+			defer mu.Unlock()
+			ran = append(ran, string(job.Data))
+			return nil
+		})
+	}()
+
+	waitFor := func(n int) []string {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			got := append([]string(nil), ran...)
+			mu.Unlock()
+			if len(got) >= n {
+				return got
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), ran...)
+	}
+
+	if got := waitFor(1); !reflect.DeepEqual(got, []string{"overdue"}) {
+		t.Errorf("expected only the overdue job to run, got %v", got)
+	}
+	clock.Advance(24 * time.Hour)
+	if got := waitFor(2); !reflect.DeepEqual(got, []string{"overdue", "later"}) {
+		t.Errorf("expected the delayed job once due, got %v", got)
+	}
+	cancel()
+	<-done
+}
+
+func TestOutboxRelay(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := gibrun.NewOutbox(client, gibrun.OutboxConfig{}).Err(); err == nil {
+		t.Error("expected an error for a missing Stream")
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	stream := "{test:gibrun:outbox}"
+	keys := []string{stream, stream + ":relay", stream + ":checkpoint", "test:gibrun:outbox:order"}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	outbox := gibrun.NewOutbox(client, gibrun.OutboxConfig{
+		Stream:       stream,
+		PollInterval: 10 * time.Millisecond,
+		RetryDelay:   time.Millisecond,
+	})
+	outbox.Append(ctx, "order.created", map[string]int{"n": 1})
+	err := client.Tx(ctx).Run(func(tx *gibrun.TxOps) error {
+		tx.Gib("test:gibrun:outbox:order", "paid", 0)
+		tx.Outbox(outbox, "order.paid", map[string]int{"n": 2})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Tx failed: %v", err)
+	}
+	last, _ := outbox.Append(ctx, "order.shipped", map[string]int{"n": 3})
+
+	var mu sync.Mutex
+	var topics []string
+	failed := false
+	relayCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- outbox.Relay(relayCtx, func(ctx context.Context, ev gibrun.OutboxEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if ev.Topic == "order.paid" && !failed {
+				failed = true
+				return errors.New("broker down")
+			}
+			topics = append(topics, ev.Topic)
+			return nil
+		})
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(topics)
+		mu.Unlock()
+		if n == 3 {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Relay returned %v", err)
+	}
+
+	if want := []string{"order.created", "order.paid", "order.shipped"}; !reflect.DeepEqual(topics, want) {
+		t.Errorf("expected %v in order, got %v", want, topics)
+	}
+	if n, _ := outbox.Len(ctx); n != 0 {
+		t.Errorf("expected delivered events to be removed, %d left", n)
+	}
+	if cp, _ := outbox.Checkpoint(ctx); cp != last {
+		t.Errorf("expected checkpoint %s, got %s", last, cp)
+	}
+}
+
+func TestOutboxRelayStopsWhileRetrying(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	stream := "{test:gibrun:outbox:retry}"
+	keys := []string{stream, stream + ":relay", stream + ":checkpoint"}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	// A long retry backoff must not delay shutdown
+	outbox := gibrun.NewOutbox(client, gibrun.OutboxConfig{Stream: stream, RetryDelay: time.Minute})
+	outbox.Append(ctx, "order.created", map[string]int{"n": 1})
+
+	var calls atomic.Int32
+	relayCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() {
+		done <- outbox.Relay(relayCtx, func(ctx context.Context, ev gibrun.OutboxEvent) error {
+			calls.Add(1)
+			return errors.New("broker down")
+		})
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Relay returned %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Relay to return promptly after cancel")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected a single attempt before shutdown, got %d", n)
+	}
+	if n, _ := outbox.Len(ctx); n != 1 {
+		t.Errorf("expected the undelivered event to stay, got %d", n)
+	}
+}
+
+func TestOutboxRelayKeepsLeaseWhilePublishing(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := gibrun.NewOutbox(client, gibrun.OutboxConfig{Stream: "s", Lease: time.Millisecond}).Err(); err == nil {
+		t.Error("expected an error for a Lease under 3ms")
+	}
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	stream := "{test:gibrun:outbox:lease}"
+	keys := []string{stream, stream + ":relay", stream + ":checkpoint"}
+	client.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+
+	// Publishing takes several leases; the standby must not take over
+	outbox := gibrun.NewOutbox(client, gibrun.OutboxConfig{
+		Stream:       stream,
+		Lease:        30 * time.Millisecond,
+		PollInterval: 5 * time.Millisecond,
+	})
+	outbox.Append(ctx, "order.created", map[string]int{"n": 1})
+
+	var calls atomic.Int32
+	publish := func(ctx context.Context, ev gibrun.OutboxEvent) error {
+		calls.Add(1)
+		time.Sleep(150 * time.Millisecond)
+		return nil
+	}
+	relayCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			outbox.Relay(relayCtx, publish)
+		}()
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	cancel()
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected the event published once, got %d", n)
+	}
+	if n, _ := outbox.Len(ctx); n != 0 {
+		t.Errorf("expected the event delivered, %d left", n)
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	stream := "test:gibrun:eventbus"
+	client.Del(ctx, stream)
+	defer client.Del(ctx, stream)
+
+	type UserCreated struct {
+		ID int `json:"id"`
+	}
+	type UserDeleted struct {
+		ID int `json:"id"`
+	}
+
+	bus := gibrun.NewEventBus(client, gibrun.EventBusConfig{Stream: stream, Service: "mailer"})
+	var mu sync.Mutex
+	users := map[int]bool{}
+	gibrun.On(bus, "user.created", func(ctx context.Context, ev UserCreated) error {
+		mu.Lock()
+		defer mu.Unlock()
+		users[ev.ID] = true
+		return nil
+	})
+	gibrun.On(bus, "user.deleted", func(ctx context.Context, ev UserDeleted) error {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(users, ev.ID)
+		return nil
+	})
+
+	bus.Emit(ctx, "user.created", UserCreated{ID: 1})
+	mid, _ := bus.Emit(ctx, "user.created", UserCreated{ID: 2})
+	bus.Emit(ctx, "user.ignored", map[string]int{"id": 9})
+	bus.Emit(ctx, "user.deleted", UserDeleted{ID: 1})
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- bus.Run(runCtx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		settled := len(users) == 1 && users[2]
+		mu.Unlock()
+		if settled {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+	if !reflect.DeepEqual(users, map[int]bool{2: true}) {
+		t.Errorf("expected only user 2, got %v", users)
+	}
+
+	// Rebuild from the second event onwards
+	users = map[int]bool{}
+	last, err := bus.Replay(ctx, mid)
+	if err != nil {
+		t.Fatalf("Replay failed: %v", err)
+	}
+	if !reflect.DeepEqual(users, map[int]bool{2: true}) {
+		t.Errorf("expected replay to rebuild user 2, got %v", users)
+	}
+	if last == "" || last == mid {
+		t.Errorf("expected replay to reach past %s, got %q", mid, last)
+	}
+}
+
+func TestClusterFromReplica(t *testing.T) {
+	cluster := gibrun.NewCluster(gibrun.ClusterConfig{
+		Addrs: []string{"localhost:7000", "localhost:7001", "localhost:7002"},
+	})
+	defer cluster.Close()
+
+	ctx := context.Background()
+
+	if err := cluster.Ping(ctx); err != nil {
+		t.Skip("Redis Cluster not available, skipping integration test")
+	}
+
+	key := "test:gibrun:cluster:replica"
+	cluster.Del(ctx, key)
+	defer cluster.Del(ctx, key)
+
+	if err := cluster.Gib(ctx, key).Value(TestStruct{Name: "replica", Value: 7}).Exec(); err != nil {
+		t.Fatalf("Gib failed: %v", err)
+	}
+
+	// Replicas lag behind the master, give them a moment
+	var got TestStruct
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		found, err := cluster.Run(ctx, key).FromReplica().Bind(&got)
+		if err != nil {
+			t.Fatalf("FromReplica Bind failed: %v", err)
+		}
+		if found {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if got.Name != "replica" || got.Value != 7 {
+		t.Errorf("unexpected replica read: %+v", got)
+	}
+
+	if found, err := cluster.Run(ctx, key+":missing").FromReplica().Bind(&got); err != nil || found {
+		t.Errorf("expected a miss, got %v %v", found, err)
+	}
+}
+
+func TestShadowWrites(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+	shadow := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+		DB:   1,
+	})
+	defer shadow.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	var mu sync.Mutex
+	var shadowErrs []error
+	client.ShadowWrites(gibrun.ShadowWriteConfig{
+		Target:  shadow,
+		Percent: 100,
+		OnError: func(key string, err error) {
+			mu.Lock()
+			shadowErrs = append(shadowErrs, err)
+			mu.Unlock()
+		},
+	})
+
+	key := "test:gibrun:shadow:write"
+	client.Del(ctx, key)
+	shadow.Del(ctx, key)
+	defer client.Del(ctx, key)
+	defer shadow.Del(ctx, key)
+
+	if err := client.Gib(ctx, key).Value(TestStruct{Name: "mirrored", Value: 1}).TTL(time.Minute).Exec(); err != nil {
+		t.Fatalf("Gib failed: %v", err)
+	}
+
+	// Mirroring is asynchronous
+	var got TestStruct
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if found, _ := shadow.Run(ctx, key).Bind(&got); found {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got.Name != "mirrored" {
+		t.Fatalf("expected the write mirrored to the shadow, got %+v", got)
+	}
+	if ttl, _ := shadow.Do(ctx, "PTTL", key).Int(); ttl <= 0 || ttl > 60000 {
+		t.Errorf("expected the TTL mirrored too, got %d", ttl)
+	}
+
+	// Roughly Percent of the writes are sampled
+	client.ShadowWrites(gibrun.ShadowWriteConfig{Target: shadow, Percent: 50})
+	keys := make([]string, 200)
+	for i := range keys {
+		keys[i] = "test:gibrun:shadow:sample:" + strconv.Itoa(i)
+	}
+	shadow.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+	defer shadow.Del(ctx, keys...)
+	for _, k := range keys {
+		client.Gib(ctx, k).Value("v").Exec()
+	}
+	time.Sleep(200 * time.Millisecond)
+	mirrored := 0
+	for _, k := range keys {
+		if ok, _ := shadow.Exists(ctx, k); ok {
+			mirrored++
+		}
+	}
+	if mirrored < 50 || mirrored > 150 {
+		t.Errorf("expected about half of 200 writes mirrored, got %d", mirrored)
+	}
+
+	// A zero Percent stops mirroring
+	client.ShadowWrites(gibrun.ShadowWriteConfig{Target: shadow})
+	client.Gib(ctx, key).Value(TestStruct{Name: "primary only"}).Exec()
+	time.Sleep(50 * time.Millisecond)
+	shadow.Run(ctx, key).Bind(&got)
+	if got.Name != "mirrored" {
+		t.Errorf("expected mirroring stopped, shadow has %+v", got)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(shadowErrs) != 0 {
+		t.Errorf("unexpected shadow errors: %v", shadowErrs)
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	ctx := context.Background()
+
+	down := gibrun.New(gibrun.Config{Addr: "localhost:1"})
+	defer down.Close()
+	result, err := down.SelfTest(ctx)
+	if err == nil || result.OK || len(result.Steps) != 1 || result.Steps[0].Name != "ping" {
+		t.Errorf("expected the ping stage to fail, got %+v %v", result, err)
+	}
+
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	result, err = client.SelfTest(ctx)
+	if err != nil {
+		t.Fatalf("SelfTest failed: %v", err)
+	}
+	if !result.OK || len(result.Steps) != 5 || result.RoundTrip <= 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	// Stage timings follow the client clock
+	clock := gibrun.NewManualClock(time.Now())
+	manual := gibrun.New(gibrun.Config{Addr: "localhost:6379", Clock: clock})
+	defer manual.Close()
+	result, err = manual.SelfTest(ctx)
+	if err != nil {
+		t.Fatalf("SelfTest with a ManualClock failed: %v", err)
+	}
+	for _, s := range result.Steps {
+		if s.Duration != 0 {
+			t.Errorf("expected %s to take no ManualClock time, got %v", s.Name, s.Duration)
+		}
+	}
+}
+
+func TestDoResult(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	str, counter, list, doc := "test:gibrun:do:str", "test:gibrun:do:counter", "test:gibrun:do:list", "test:gibrun:do:doc"
+	client.Del(ctx, str, counter, list, doc)
+	defer client.Del(ctx, str, counter, list, doc)
+
+	client.Do(ctx, "SET", str, "hello")
+	if s, err := client.Do(ctx, "GET", str).String(); err != nil || s != "hello" {
+		t.Errorf("String: expected hello, got %q %v", s, err)
+	}
+	client.Do(ctx, "INCRBY", counter, 41)
+	if n, err := client.Do(ctx, "INCR", counter).Int(); err != nil || n != 42 {
+		t.Errorf("Int: expected 42, got %d %v", n, err)
+	}
+
+	client.Do(ctx, "RPUSH", list, "name", "alice", "role", "admin")
+	vals, err := client.Do(ctx, "LRANGE", list, 0, -1).Slice()
+	if err != nil || len(vals) != 4 || vals[1] != "alice" {
+		t.Errorf("Slice: unexpected reply %v %v", vals, err)
+	}
+
+	// Nil replies
+	missing := client.Do(ctx, "GET", "test:gibrun:do:missing")
+	if !missing.IsNil() || missing.Err() == nil {
+		t.Errorf("expected a nil reply, got %v", missing.Val())
+	}
+	if _, err := missing.String(); err == nil {
+		t.Error("expected String to fail on a nil reply")
+	}
+	var none TestStruct
+	if err := missing.Scan(&none); err == nil {
+		t.Error("expected Scan to fail on a nil reply")
+	}
+
+	// Scan decodes strings with the codec and flat arrays as maps
+	client.Gib(ctx, doc).Value(TestStruct{Name: "scanned", Value: 9}).Exec()
+	var got TestStruct
+	if err := client.Do(ctx, "GET", doc).Scan(&got); err != nil || got.Name != "scanned" || got.Value != 9 {
+		t.Errorf("Scan string: unexpected %+v %v", got, err)
+	}
+	var fields map[string]string
+	if err := client.Do(ctx, "LRANGE", list, 0, -1).Scan(&fields); err != nil || fields["role"] != "admin" {
+		t.Errorf("Scan flat pairs into a map: unexpected %v %v", fields, err)
+	}
+	var user struct {
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+	if err := client.Do(ctx, "LRANGE", list, 0, -1).Scan(&user); err != nil || user.Name != "alice" || user.Role != "admin" {
+		t.Errorf("Scan flat pairs into a struct: unexpected %+v %v", user, err)
+	}
+	var items []string
+	if err := client.Do(ctx, "LRANGE", list, 0, -1).Scan(&items); err != nil || len(items) != 4 {
+		t.Errorf("Scan into a slice: unexpected %v %v", items, err)
+	}
+}
+
+func TestAtomicScriptAndFallback(t *testing.T) {
+	ctx := context.Background()
+
+	for _, scripting := range []bool{true, false} {
+		client := gibrun.New(gibrun.Config{
+			Addr: "localhost:6379",
+		})
+		defer client.Close()
+
+		if err := client.Ping(ctx); err != nil {
+			t.Skip("Redis not available, skipping integration test")
+		}
+
+		// The probe result is cached on the client, so this forces the path
+		info, err := gibrun.ProbeServer(ctx, client)
+		if err != nil {
+			t.Fatalf("ProbeServer failed: %v", err)
+		}
+		if scripting && !info.Supports(gibrun.CapScripting) {
+			t.Log("server has scripting disabled, skipping the script path")
+			continue
+		}
+		info.Capabilities[gibrun.CapScripting] = scripting
+
+		user, page, counter, bad, after := "test:gibrun:atomic:user", "test:gibrun:atomic:page",
+			"test:gibrun:atomic:counter", "test:gibrun:atomic:bad", "test:gibrun:atomic:after"
+		client.Del(ctx, user, page, counter, bad, after)
+		client.Do(ctx, "SET", page, "<html>")
+
+		results, err := client.Atomic(ctx).
+			Gib(user, TestStruct{Name: "atomic", Value: 1}, time.Minute).
+			Del(page).
+			IncrBy(counter, 5).
+			Expire(counter, time.Minute).
+			Exec()
+		if err != nil {
+			t.Fatalf("scripting=%v: Exec failed: %v", scripting, err)
+		}
+		if want := []int64{1, 1, 5, 1}; !reflect.DeepEqual(results, want) {
+			t.Errorf("scripting=%v: expected %v, got %v", scripting, want, results)
+		}
+		var got TestStruct
+		if found, _ := client.Run(ctx, user).Bind(&got); !found || got.Name != "atomic" {
+			t.Errorf("scripting=%v: expected the user written, got %+v", scripting, got)
+		}
+		if ok, _ := client.Exists(ctx, page); ok {
+			t.Errorf("scripting=%v: expected the page deleted", scripting)
+		}
+		if ttl, _ := client.Do(ctx, "PTTL", counter).Int(); ttl <= 0 {
+			t.Errorf("scripting=%v: expected the counter to expire, PTTL %d", scripting, ttl)
+		}
+
+		// A failing step stops the script but not MULTI/EXEC
+		client.Do(ctx, "SET", bad, "not a number")
+		if _, err := client.Atomic(ctx).Incr(bad).Incr(after).Exec(); err == nil {
+			t.Errorf("scripting=%v: expected INCR on a string to fail", scripting)
+		}
+		n, _ := client.Do(ctx, "GET", after).Int()
+		if want := map[bool]int64{true: 0, false: 1}[scripting]; n != want {
+			t.Errorf("scripting=%v: expected the step after the failure at %d, got %d", scripting, want, n)
+		}
+
+		client.Del(ctx, user, page, counter, bad, after)
+	}
+}
+
+func TestShadowReads(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+	shadow := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+		DB:   1,
+	})
+	defer shadow.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	prefix := "test:gibrun:shadow:read:"
+	same, missing, unexpected, value, ttl := prefix+"same", prefix+"missing", prefix+"unexpected", prefix+"value", prefix+"ttl"
+	keys := []string{same, missing, unexpected, value, ttl}
+	client.Del(ctx, keys...)
+	shadow.Del(ctx, keys...)
+	defer client.Del(ctx, keys...)
+	defer shadow.Del(ctx, keys...)
+
+	client.Gib(ctx, same).Value("v").TTL(time.Minute).Exec()
+	shadow.Gib(ctx, same).Value("v").TTL(time.Minute).Exec()
+	client.Gib(ctx, missing).Value("v").Exec()
+	shadow.Gib(ctx, unexpected).Value("v").Exec()
+	client.Gib(ctx, value).Value("new").Exec()
+	shadow.Gib(ctx, value).Value("old").Exec()
+	client.Gib(ctx, ttl).Value("v").TTL(time.Minute).Exec()
+	shadow.Gib(ctx, ttl).Value("v").TTL(time.Hour).Exec()
+
+	var mu sync.Mutex
+	reasons := map[string]string{}
+	var shadowErrs []error
+	client.ShadowReads(gibrun.ShadowReadConfig{
+		Target:      shadow,
+		Percent:     100,
+		MaxInFlight: 1000,
+		CompareTTL:  true,
+		OnMismatch: func(m gibrun.ShadowMismatch) {
+			mu.Lock()
+			reasons[m.Key] = m.Reason
+			mu.Unlock()
+		},
+		OnError: func(key string, err error) {
+			mu.Lock()
+			shadowErrs = append(shadowErrs, err)
+			mu.Unlock()
+		},
+	})
+
+	var got string
+	for _, k := range keys {
+		client.Run(ctx, k).Bind(&got)
+	}
+
+	want := map[string]string{missing: "missing", unexpected: "unexpected", value: "value", ttl: "ttl"}
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		mu.Lock()
+		n := len(reasons)
+		mu.Unlock()
+		if n >= len(want) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	if !reflect.DeepEqual(reasons, want) {
+		t.Errorf("expected mismatches %v, got %v", want, reasons)
+	}
+	if len(shadowErrs) != 0 {
+		t.Errorf("unexpected shadow errors: %v", shadowErrs)
+	}
+	mu.Unlock()
+
+	// Roughly Percent of the reads are compared
+	var compared atomic.Int32
+	client.ShadowReads(gibrun.ShadowReadConfig{
+		Target:      shadow,
+		Percent:     50,
+		MaxInFlight: 1000,
+		OnMismatch:  func(m gibrun.ShadowMismatch) { compared.Add(1) },
+	})
+	for i := 0; i < 200; i++ {
+		client.Run(ctx, missing).Bind(&got)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if n := compared.Load(); n < 50 || n > 150 {
+		t.Errorf("expected about half of 200 reads compared, got %d", n)
+	}
+
+	// A nil Target stops comparing
+	client.ShadowReads(gibrun.ShadowReadConfig{})
+	before := compared.Load()
+	client.Run(ctx, missing).Bind(&got)
+	time.Sleep(50 * time.Millisecond)
+	if compared.Load() != before {
+		t.Error("expected no comparisons after ShadowReads was turned off")
+	}
+}
+
+func TestReplicatedClient(t *testing.T) {
+	ctx := context.Background()
+
+	probe := gibrun.New(gibrun.Config{Addr: "localhost:6379"})
+	defer probe.Close()
+	if err := probe.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	// The configured primary is down and the only "replica" reports
+	// itself as master, as after a failover
+	var mu sync.Mutex
+	var promoted []string
+	r := gibrun.NewReplicated(gibrun.ReplicatedConfig{
+		Primary:        gibrun.Config{Addr: "localhost:1"},
+		Replicas:       []string{"localhost:6379"},
+		HealthInterval: time.Hour,
+		OnPromotion: func(addr string) {
+			mu.Lock()
+			promoted = append(promoted, addr)
+			mu.Unlock()
+		},
+	})
+	defer r.Close()
+
+	r.Refresh(ctx)
+	r.Refresh(ctx)
+
+	mu.Lock()
+	if len(promoted) != 1 || promoted[0] != "localhost:6379" {
+		t.Errorf("expected one promotion to localhost:6379, got %v", promoted)
+	}
+	mu.Unlock()
+	if err := r.Primary().Ping(ctx); err != nil {
+		t.Fatalf("expected the promoted node as primary: %v", err)
+	}
+	if r.Replica() != r.Primary() {
+		t.Error("expected reads to fall back to the primary with no healthy replica")
+	}
+
+	key := "test:gibrun:replicated"
+	r.Del(ctx, key)
+	defer r.Del(ctx, key)
+
+	if err := r.Gib(ctx, key).Value(TestStruct{Name: "split", Value: 3}).Exec(); err != nil {
+		t.Fatalf("Gib failed: %v", err)
+	}
+	var got TestStruct
+	if found, err := r.Run(ctx, key).Bind(&got); err != nil || !found || got.Value != 3 {
+		t.Errorf("unexpected read: %+v %v %v", got, found, err)
+	}
+	if ok, _ := r.Exists(ctx, key); !ok {
+		t.Error("expected Exists to see the key")
+	}
+}
+
+func TestReplicatedOrElseWritesPrimary(t *testing.T) {
+	r := gibrun.NewReplicated(gibrun.ReplicatedConfig{
+		Primary:        gibrun.Config{Addr: "localhost:6379"},
+		Replicas:       []string{"localhost:6379"},
+		HealthInterval: time.Hour,
+	})
+	defer r.Close()
+
+	ctx := context.Background()
+
+	if err := r.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	// writes counts the Gib writes a client made for the test prefix
+	writes := func(c *gibrun.Client) int64 {
+		for _, s := range c.Stats() {
+			if s.Prefix == "test:" {
+				return s.Writes
+			}
+		}
+		return 0
+	}
+
+	key := "test:gibrun:replicated:orelse"
+	r.Del(ctx, key)
+	defer r.Del(ctx, key)
+
+	replica := r.Replica()
+	b := r.Run(ctx, key).OrElse(func(ctx context.Context) (any, time.Duration, error) {
+		return TestStruct{Name: "loaded", Value: 7}, time.Minute, nil
+	})
+	var got TestStruct
+	if found, err := b.Bind(&got); err != nil || !found || got.Value != 7 {
+		t.Fatalf("unexpected load: %+v %v %v", got, found, err)
+	}
+
+	if n := writes(r.Primary()); n != 1 {
+		t.Errorf("expected the backfill on the primary, got %d writes", n)
+	}
+	if replica != r.Primary() {
+		if n := writes(replica); n != 0 {
+			t.Errorf("expected no writes on the replica, got %d", n)
+		}
+	}
+	if found, _ := r.Primary().Run(ctx, key).Bind(&got); !found {
+		t.Error("expected the loaded value cached on the primary")
+	}
+}
+
+func TestGibWriteThrough(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:writethrough"
+	defer client.Del(ctx, key)
+	errDB := errors.New("db down")
+
+	// cached returns the value in Redis, "" when missing
+	cached := func() string {
+		val, _, _ := client.Run(ctx, key).Raw()
+		return val
+	}
+	reset := func() {
+		client.Del(ctx, key)
+		client.Gib(ctx, key).Value("old").TTL(time.Hour).Exec()
+	}
+
+	cases := []struct {
+		name      string
+		policy    gibrun.WritePolicy
+		seenByDB  string // cache value while persisting
+		afterFail string // cache value after a persist failure
+	}{
+		{"DBFirst", gibrun.WriteDBFirst, "old", "old"},
+		{"CacheFirst", gibrun.WriteCacheFirst, "new", "new"},
+		{"BothOrRollback", gibrun.WriteBothOrRollback, "new", "old"},
+	}
+	for _, tc := range cases {
+		reset()
+		var seen string
+		err := client.Gib(ctx, key).Value("new").WritePolicy(tc.policy).
+			WriteThrough(func(ctx context.Context) error {
+				seen = cached()
+				return nil
+			}).Exec()
+		if err != nil {
+			t.Errorf("%s: Exec failed: %v", tc.name, err)
+		}
+		if seen != tc.seenByDB {
+			t.Errorf("%s: expected %q in the cache while persisting, got %q", tc.name, tc.seenByDB, seen)
+		}
+		if got := cached(); got != "new" {
+			t.Errorf("%s: expected new after success, got %q", tc.name, got)
+		}
+
+		reset()
+		err = client.Gib(ctx, key).Value("new").WritePolicy(tc.policy).
+			WriteThrough(func(ctx context.Context) error { return errDB }).Exec()
+		if !errors.Is(err, errDB) {
+			t.Errorf("%s: expected the persist error, got %v", tc.name, err)
+		}
+		if got := cached(); got != tc.afterFail {
+			t.Errorf("%s: expected %q after a persist failure, got %q", tc.name, tc.afterFail, got)
+		}
+	}
+
+	// Rollback restores the TTL, and removes keys that didn't exist
+	reset()
+	client.Gib(ctx, key).Value("new").WritePolicy(gibrun.WriteBothOrRollback).
+		WriteThrough(func(ctx context.Context) error { return errDB }).Exec()
+	if ttl, _ := client.Do(ctx, "PTTL", key).Int(); ttl <= 59*60*1000 {
+		t.Errorf("expected the old TTL restored, got %d", ttl)
+	}
+	client.Del(ctx, key)
+	client.Gib(ctx, key).Value("new").WritePolicy(gibrun.WriteBothOrRollback).
+		WriteThrough(func(ctx context.Context) error { return errDB }).Exec()
+	if ok, _ := client.Exists(ctx, key); ok {
+		t.Error("expected rollback to remove a key that didn't exist")
+	}
+
+	// DBFirst drops the cached entry when the cache write fails after persisting
+	reset()
+	persisted := false
+	err := client.Gib(ctx, key).Value(make(chan int)).
+		WriteThrough(func(ctx context.Context) error {
+			persisted = true
+			return nil
+		}).Exec()
+	if err == nil || !persisted {
+		t.Errorf("expected a cache write error after persisting, got %v (persisted %v)", err, persisted)
+	}
+	if ok, _ := client.Exists(ctx, key); ok {
+		t.Error("expected the stale entry removed after a failed cache write")
+	}
+}
+
+func TestGibWriteThroughRollbackUnsupported(t *testing.T) {
+	client := gibrun.New(gibrun.Config{
+		Addr:      "localhost:6379",
+		ChunkSize: 16,
+	})
+	defer client.Close()
+
+	ctx := context.Background()
+
+	if err := client.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	key := "test:gibrun:writethrough:unsupported"
+	client.Del(ctx, key)
+	defer client.Del(ctx, key)
+	long := strings.Repeat("gibrun-rollback-", 4)
+
+	cases := map[string]struct {
+		setup func()
+		b     *gibrun.GibBuilder
+	}{
+		"AsHash":       {nil, client.Gib(ctx, key).Value(TestStruct{Name: "h", Value: 1}).AsHash()},
+		"ChunkedValue": {nil, client.Gib(ctx, key).Value(long)},
+		"OverChunked":  {func() { client.Gib(ctx, key).Value(long).Exec() }, client.Gib(ctx, key).Value("small")},
+		"OverHash":     {func() { client.Do(ctx, "HSET", key, "f", "v") }, client.Gib(ctx, key).Value("small")},
+	}
+	for name, tc := range cases {
+		client.Del(ctx, key)
+		if tc.setup != nil {
+			tc.setup()
+		}
+		persisted := false
+		err := tc.b.WritePolicy(gibrun.WriteBothOrRollback).
+			WriteThrough(func(ctx context.Context) error {
+				persisted = true
+				return nil
+			}).Exec()
+		if !errors.Is(err, gibrun.ErrUnsupported) {
+			t.Errorf("%s: expected ErrUnsupported, got %v", name, err)
+		}
+		if persisted {
+			t.Errorf("%s: expected nothing persisted", name)
+		}
+	}
+}
+
+func TestMigrateProgress(t *testing.T) {
+	src := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+	})
+	defer src.Close()
+	dst := gibrun.New(gibrun.Config{
+		Addr: "localhost:6379",
+		DB:   2,
+	})
+	defer dst.Close()
+
+	ctx := context.Background()
+
+	if err := src.Ping(ctx); err != nil {
+		t.Skip("Redis not available, skipping integration test")
+	}
+
+	prefix := "test:gibrun:migrate:progress:"
+	keys := make([]string, 5)
+	for i := range keys {
+		keys[i] = prefix + strconv.Itoa(i)
+	}
+	list := prefix + "list"
+	src.Del(ctx, append(keys, list)...)
+	dst.Del(ctx, append(keys, list)...)
+	defer src.Del(ctx, append(keys, list)...)
+	defer dst.Del(ctx, append(keys, list)...)
+
+	for _, k := range keys {
+		src.Do(ctx, "SET", k, "abcd")
+	}
+	// GET fails on a list, so this key is reported as failed
+	src.Do(ctx, "RPUSH", list, "x")
+
+	var dones, totals []int
+	var details []gibrun.MigrateProgress
+	tracker := &gibrun.MigrationTracker{}
+	result, err := gibrun.Migrate(ctx, src, dst, gibrun.MigrateOptions{
+		Pattern:   prefix + "*",
+		BatchSize: 2,
+		Tracker:   tracker,
+		OnError:   func(key string, err error) bool { return true },
+		OnProgress: func(done, total int) {
+			dones = append(dones, done)
+			totals = append(totals, total)
+		},
+		OnProgressDetail: func(p gibrun.MigrateProgress) { details = append(details, p) },
+	})
+	if err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	if result.TotalKeys != 6 || result.MigratedKeys != 5 || result.FailedKeys != 1 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	if want := []int{2, 4, 6}; !reflect.DeepEqual(dones, want) {
+		t.Errorf("expected done counts %v, got %v", want, dones)
+	}
+	if want := []int{6, 6, 6}; !reflect.DeepEqual(totals, want) {
+		t.Errorf("expected totals %v, got %v", want, totals)
+	}
+
+	if len(details) != 3 {
+		t.Fatalf("expected 3 detailed reports, got %d", len(details))
+	}
+	batchErrs := 0
+	for i, p := range details {
+		if p.Done != dones[i] || p.MigratedKeys+p.FailedKeys != p.Done || p.Finished {
+			t.Errorf("report %d inconsistent: %+v", i, p)
+		}
+		if p.Bytes != int64(p.MigratedKeys*4) {
+			t.Errorf("report %d: expected %d bytes, got %d", i, p.MigratedKeys*4, p.Bytes)
+		}
+		for _, e := range p.BatchErrors {
+			if e.Key != list {
+				t.Errorf("report %d: unexpected batch error for %s", i, e.Key)
+			}
+			batchErrs++
+		}
+	}
+	if batchErrs != 1 {
+		t.Errorf("expected the failed key in exactly one batch, got %d", batchErrs)
+	}
+	if last := details[2]; last.MigratedKeys != 5 || last.FailedKeys != 1 || last.ETA != 0 {
+		t.Errorf("unexpected last report: %+v", last)
+	}
+
+	status := tracker.MigrationStatus()
+	if !status.Finished || status.Done != 6 || status.Total != 6 || status.Bytes != 20 {
+		t.Errorf("unexpected final tracker status: %+v", status)
+	}
+}
@@ -0,0 +1,332 @@
+package gibrun
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// AsHash stores the value as a Redis hash instead of a single blob.
+// Structs are mapped field by field using `redis:"name"` tags (untagged
+// fields are skipped, "-" skips explicitly, ",omitempty" skips zero values);
+// maps with string keys are stored entry by entry. Scalars are stored in
+// their text form, nested values go through the codec.
+//
+// Only the given fields are written, so callers can update part of an
+// object without rewriting the whole JSON document.
+//
+// Example:
+//
+//	type User struct {
+//	    Name  string `redis:"name"`
+//	    Email string `redis:"email"`
+//	    Age   int    `redis:"age,omitempty"`
+//	}
+//	err := app.Gib(ctx, "user:123").AsHash().Value(user).Exec()
+//	err = app.Gib(ctx, "user:123").AsHash().Value(map[string]any{"email": "new@example.com"}).Exec()
+func (b *GibBuilder) AsHash() *GibBuilder {
+	b.asHash = true
+	return b
+}
+
+// execHash writes the value with HSET, applying the TTL to the whole hash.
+func (b *GibBuilder) execHash() error {
+	fields, err := b.client.enc.hashFields(b.value, b.codec)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return ErrNilValue
+	}
+
+	ttl := b.client.ttl.apply(b.key, b.ttl)
+	if b.detail != nil {
+		for _, v := range fields {
+			b.detail.Size += len(v.([]byte))
+		}
+		b.detail.TTL = ttl
+	}
+	_, err = b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(b.ctx, b.key, fields)
+		if ttl > 0 {
+			pipe.PExpire(b.ctx, b.key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// hashFields converts a struct or string-keyed map into hash fields.
+func (e *encoding) hashFields(v any, override Codec) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, ErrNilValue
+		}
+		rv = rv.Elem()
+	}
+
+	fields := make(map[string]any)
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("gibrun: AsHash needs string map keys, got %s", rv.Type().Key())
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			data, err := e.hashValue(iter.Value(), override)
+			if err != nil {
+				return nil, err
+			}
+			fields[iter.Key().String()] = data
+		}
+
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name, omitEmpty, ok := hashTag(sf)
+			if !ok {
+				continue
+			}
+			fv := rv.Field(i)
+			if omitEmpty && fv.IsZero() {
+				continue
+			}
+			data, err := e.hashValue(fv, override)
+			if err != nil {
+				return nil, fmt.Errorf("gibrun: field %s: %w", sf.Name, err)
+			}
+			fields[name] = data
+		}
+
+	default:
+		return nil, fmt.Errorf("gibrun: AsHash needs a struct or map, got %s", rv.Kind())
+	}
+
+	return fields, nil
+}
+
+// hashTag parses the `redis` tag of an exported struct field.
+func hashTag(sf reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	if !sf.IsExported() {
+		return "", false, false
+	}
+	tag, has := sf.Tag.Lookup("redis")
+	if !has || tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = sf.Name
+	}
+	return name, opts == "omitempty", true
+}
+
+// hashValue converts a single field to its stored bytes.
+func (e *encoding) hashValue(v reflect.Value, override Codec) ([]byte, error) {
+	if b, ok := v.Interface().([]byte); ok {
+		return e.wrap(b)
+	}
+	text, ok := scalarText(v)
+	if !ok {
+		return e.marshal(v.Interface(), override)
+	}
+	return e.wrap([]byte(text))
+}
+
+// scalarText renders times, strings, bools and numbers in their text form.
+func scalarText(v reflect.Value) (string, bool) {
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339Nano), true
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+	}
+	return "", false
+}
+
+// HashRunBuilder reads a key stored with Gib().AsHash(), see RunBuilder.FromHash.
+type HashRunBuilder struct {
+	run *RunBuilder
+}
+
+// FromHash reads the key as a Redis hash instead of a single blob,
+// pairing with Gib().AsHash(). Struct fields are matched by their
+// `redis:"name"` tags, the same way AsHash writes them.
+//
+// Example:
+//
+//	var user User
+//	found, err := app.Run(ctx, "user:123").FromHash().Bind(&user)
+//
+//	var email string
+//	found, err = app.Run(ctx, "user:123").FromHash().BindField("email", &email)
+func (b *RunBuilder) FromHash() *HashRunBuilder {
+	return &HashRunBuilder{run: b}
+}
+
+// Bind reads every field with HGETALL into dest, a pointer to a struct
+// or to a string-keyed map. Fields missing from the hash are left as is.
+// Returns (false, nil) if the key doesn't exist.
+func (h *HashRunBuilder) Bind(dest any) (bool, error) {
+	rv := reflect.ValueOf(dest)
+	if dest == nil || rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return false, ErrNilPointer
+	}
+
+	b := h.run
+	vals, err := b.client.rdb.HGetAll(b.ctx, b.key).Result()
+	if err != nil {
+		return false, err
+	}
+	if len(vals) == 0 {
+		return false, nil
+	}
+
+	if err := b.client.enc.bindHash(vals, rv.Elem(), b.codec); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// bindHash decodes hash fields into rv, a struct or string-keyed map.
+func (e *encoding) bindHash(vals map[string]string, rv reflect.Value, override Codec) error {
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("gibrun: FromHash needs string map keys, got %s", rv.Type().Key())
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMapWithSize(rv.Type(), len(vals)))
+		}
+		for name, data := range vals {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := e.setHashValue(elem, []byte(data), override); err != nil {
+				return fmt.Errorf("gibrun: field %s: %w", name, err)
+			}
+			rv.SetMapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()), elem)
+		}
+
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			name, _, ok := hashTag(sf)
+			if !ok {
+				continue
+			}
+			data, ok := vals[name]
+			if !ok {
+				continue
+			}
+			if err := e.setHashValue(rv.Field(i), []byte(data), override); err != nil {
+				return fmt.Errorf("gibrun: field %s: %w", sf.Name, err)
+			}
+		}
+
+	default:
+		return fmt.Errorf("gibrun: FromHash needs a struct or map, got %s", rv.Kind())
+	}
+
+	return nil
+}
+
+// BindField reads a single hash field with HGET into dest.
+// Returns (false, nil) if the key or the field doesn't exist.
+func (h *HashRunBuilder) BindField(field string, dest any) (bool, error) {
+	rv := reflect.ValueOf(dest)
+	if dest == nil || rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return false, ErrNilPointer
+	}
+
+	b := h.run
+	data, err := b.client.rdb.HGet(b.ctx, b.key, field).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := b.client.enc.setHashValue(rv.Elem(), data, b.codec); err != nil {
+		return false, fmt.Errorf("gibrun: field %s: %w", field, err)
+	}
+	return true, nil
+}
+
+// setHashValue decodes a stored field into v, the reverse of hashValue.
+func (e *encoding) setHashValue(v reflect.Value, data []byte, override Codec) error {
+	data, err := e.open(data)
+	if err != nil {
+		return err
+	}
+	return setText(v, data, e.codecFor(override))
+}
+
+// setText decodes text written by scalarText into v, falling back to
+// codec for other types.
+func setText(v reflect.Value, data []byte, codec Codec) error {
+	text := string(data)
+
+	switch v.Interface().(type) {
+	case time.Time:
+		t, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case []byte:
+		v.SetBytes(data)
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Interface:
+		// No type to go by, e.g. map[string]any
+		v.Set(reflect.ValueOf(text))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return unmarshal(data, v.Addr().Interface(), codec)
+	}
+	return nil
+}
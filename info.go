@@ -0,0 +1,132 @@
+package gibrun
+
+import (
+	"encoding/binary"
+	"reflect"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// HitInfo describes a cache read for logging and freshness decisions.
+type HitInfo struct {
+	// Found is true on a cache hit.
+	Found bool
+
+	// TTLRemaining is the time left before expiry, -1 if the key has no
+	// expiry and 0 on a miss.
+	TTLRemaining time.Duration
+
+	// Size is the stored size in bytes, including envelopes.
+	Size int
+
+	// Codec is the name of the codec used to decode, e.g. "JSONCodec",
+	// or "raw" for string and []byte destinations.
+	Codec string
+
+	// StoredAt is when the value was written. It is only known for values
+	// written with Config.TrackWriteTime, zero otherwise.
+	StoredAt time.Time
+}
+
+// BindWithInfo is like Bind but also reports how the read went. The
+// value and its TTL are fetched in one round trip.
+//
+// Example:
+//
+//	var user User
+//	info, err := app.Run(ctx, "user:123").BindWithInfo(&user)
+//	if err == nil && info.Found && time.Since(info.StoredAt) > 10*time.Minute {
+//	    go refresh(ctx, 123)
+//	}
+func (b *RunBuilder) BindWithInfo(dest any) (*HitInfo, error) {
+	if dest == nil {
+		return nil, ErrNilPointer
+	}
+
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+	_, err := b.client.rdb.Pipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(b.ctx, b.key)
+		pttl = pipe.PTTL(b.ctx, b.key)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	info := &HitInfo{Codec: codecName(dest, b.client.enc.codecFor(b.codec))}
+	data, err := b.finishGet(get.Bytes())
+	if err != nil {
+		if err == redis.Nil {
+			return info, nil
+		}
+		return nil, err
+	}
+
+	info.Size = len(data)
+	o, err := b.client.enc.openAll(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.client.enc.decode(o, dest, b.codec); err != nil {
+		return nil, err
+	}
+
+	info.Found = true
+	info.TTLRemaining = pttl.Val()
+	info.StoredAt = o.storedAt
+	return info, nil
+}
+
+// BindWithTTL is like Bind but also returns the remaining TTL, fetched in
+// the same round trip. The TTL is -1 if the key has no expiry and 0 on a
+// miss. Use it to refresh entries proactively before they expire.
+//
+// Example:
+//
+//	var page Page
+//	ttl, found, err := app.Run(ctx, "page:home").BindWithTTL(&page)
+//	if found && ttl > 0 && ttl < time.Minute {
+//	    go refresh(ctx, "home")
+//	}
+func (b *RunBuilder) BindWithTTL(dest any) (time.Duration, bool, error) {
+	info, err := b.BindWithInfo(dest)
+	if err != nil {
+		return 0, false, err
+	}
+	return info.TTLRemaining, info.Found, nil
+}
+
+// codecName names the codec that decodes into dest.
+func codecName(dest any, codec Codec) string {
+	switch dest.(type) {
+	case *string, *[]byte:
+		return "raw"
+	}
+	t := reflect.TypeOf(codec)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// addTimestamp wraps data in a write-time envelope:
+// envelope header | Unix milliseconds (big-endian int64) | payload.
+func addTimestamp(data []byte, at time.Time) []byte {
+	out := make([]byte, 0, envelopeHeaderLen+8+len(data))
+	out = append(out, envelopeMagic...)
+	out = append(out, envelopeStamped)
+	out = binary.BigEndian.AppendUint64(out, uint64(at.UnixMilli()))
+	return append(out, data...)
+}
+
+// splitTimestamp strips a write-time envelope, returning the time
+// (zero if absent) and the payload.
+func splitTimestamp(data []byte) (time.Time, []byte) {
+	if envelopeKind(data) != envelopeStamped || len(data) < envelopeHeaderLen+8 {
+		return time.Time{}, data
+	}
+	ms := int64(binary.BigEndian.Uint64(data[envelopeHeaderLen:]))
+	return time.UnixMilli(ms), data[envelopeHeaderLen+8:]
+}
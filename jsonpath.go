@@ -0,0 +1,109 @@
+package gibrun
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// BindPath extracts a single field from a stored JSON document into dest,
+// without unmarshalling the rest of the document. Path segments are
+// separated by dots; numeric segments index into arrays.
+// Returns (false, nil) if the key or the path doesn't exist.
+// The value must have been stored as JSON (the default codec).
+//
+// Example:
+//
+//	var price float64
+//	found, err := app.Run(ctx, "catalog:full").BindPath("items.0.price", &price)
+func (b *RunBuilder) BindPath(path string, dest any) (bool, error) {
+	if dest == nil {
+		return false, ErrNilPointer
+	}
+
+	data, err := b.get()
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+	if data, err = b.client.enc.open(data); err != nil {
+		return false, err
+	}
+
+	raw, found, err := extractPath(data, path)
+	if err != nil || !found {
+		return false, err
+	}
+	if err := json.Unmarshal(raw, dest); err != nil {
+		return false, fmt.Errorf("gibrun: decode %s at %q: %w", b.key, path, err)
+	}
+	return true, nil
+}
+
+// extractPath walks the document token by token, skipping every value
+// off the path, and returns the raw JSON at path.
+func extractPath(data []byte, path string) (json.RawMessage, bool, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var skip json.RawMessage
+
+	for _, seg := range strings.Split(path, ".") {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false, fmt.Errorf("gibrun: bad JSON at %q: %w", seg, err)
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			found := false
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, false, err
+				}
+				if key == seg {
+					found = true
+					break
+				}
+				if err := dec.Decode(&skip); err != nil {
+					return nil, false, err
+				}
+			}
+			if !found {
+				return nil, false, nil
+			}
+
+		case json.Delim('['):
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 {
+				return nil, false, nil
+			}
+			for i := 0; i < idx; i++ {
+				if !dec.More() {
+					return nil, false, nil
+				}
+				if err := dec.Decode(&skip); err != nil {
+					return nil, false, err
+				}
+			}
+			if !dec.More() {
+				return nil, false, nil
+			}
+
+		default:
+			// A scalar has no fields to descend into
+			return nil, false, nil
+		}
+	}
+
+	var raw json.RawMessage
+	if err := dec.Decode(&raw); err != nil {
+		return nil, false, err
+	}
+	return raw, true, nil
+}
@@ -0,0 +1,179 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KabinetBuilder provides a fluent API for objects stored as Redis
+// hashes, one field per struct field. "Kabinet" is a cabinet with a
+// drawer per field: fields can be read, updated and removed one at a
+// time, and small hashes take far less memory than JSON strings.
+//
+// Struct fields are mapped with `redis:"name"` tags exactly like
+// Gib().AsHash() and Run().FromHash(), so the three can be mixed.
+type KabinetBuilder struct {
+	ctx    context.Context
+	client *Client
+	key    string
+	value  any
+	ttl    time.Duration
+	codec  Codec
+}
+
+// Kabinet starts a hash operation.
+//
+// Example:
+//
+//	err := app.Kabinet(ctx, "user:123").Value(user).TTL(time.Hour).Exec()
+//
+//	// later, only touch the fields that changed
+//	user.Email = "new@example.com"
+//	err = app.Kabinet(ctx, "user:123").Value(user).SetFields("email")
+//
+//	var u User
+//	found, err := app.Kabinet(ctx, "user:123").Bind(&u)
+func (c *Client) Kabinet(ctx context.Context, key string) *KabinetBuilder {
+	return &KabinetBuilder{
+		ctx:    ctx,
+		client: c,
+		key:    key,
+	}
+}
+
+// Value sets the struct or string-keyed map to write.
+func (b *KabinetBuilder) Value(v any) *KabinetBuilder {
+	b.value = v
+	return b
+}
+
+// TTL sets the time-to-live of the whole hash on write.
+func (b *KabinetBuilder) TTL(d time.Duration) *KabinetBuilder {
+	b.ttl = d
+	return b
+}
+
+// Codec overrides the client codec for nested field values.
+func (b *KabinetBuilder) Codec(c Codec) *KabinetBuilder {
+	b.codec = c
+	return b
+}
+
+// Exec writes every field of the value. Fields already in the hash but
+// not in the value are kept.
+func (b *KabinetBuilder) Exec() error {
+	return b.client.Gib(b.ctx, b.key).AsHash().Value(b.value).TTL(b.ttl).Codec(b.codec).Exec()
+}
+
+// SetFields writes only the named fields of the value, leaving the rest
+// of the hash untouched. Named fields the value leaves out (",omitempty"
+// zero values, absent map keys) are deleted. Naming a field the struct
+// doesn't map returns an error without writing anything.
+func (b *KabinetBuilder) SetFields(names ...string) error {
+	if b.value == nil {
+		return ErrNilValue
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	if err := checkHashFields(b.value, names); err != nil {
+		return err
+	}
+	all, err := b.client.enc.hashFields(b.value, b.codec)
+	if err != nil {
+		return err
+	}
+
+	set := make(map[string]any, len(names))
+	var del []string
+	for _, name := range names {
+		if v, ok := all[name]; ok {
+			set[name] = v
+		} else {
+			del = append(del, name)
+		}
+	}
+
+	ttl := b.client.ttl.apply(b.key, b.ttl)
+	_, err = b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		if len(set) > 0 {
+			pipe.HSet(b.ctx, b.key, set)
+		}
+		if len(del) > 0 {
+			pipe.HDel(b.ctx, b.key, del...)
+		}
+		if ttl > 0 {
+			pipe.PExpire(b.ctx, b.key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// Bind reads every field into dest, a pointer to a struct or to a
+// string-keyed map. Returns (false, nil) if the key doesn't exist.
+func (b *KabinetBuilder) Bind(dest any) (bool, error) {
+	return b.client.Run(b.ctx, b.key).Codec(b.codec).FromHash().Bind(dest)
+}
+
+// BindField reads a single field into dest.
+// Returns (false, nil) if the key or the field doesn't exist.
+func (b *KabinetBuilder) BindField(field string, dest any) (bool, error) {
+	return b.client.Run(b.ctx, b.key).Codec(b.codec).FromHash().BindField(field, dest)
+}
+
+// DelFields removes fields and returns how many existed.
+func (b *KabinetBuilder) DelFields(fields ...string) (int64, error) {
+	if len(fields) == 0 {
+		return 0, nil
+	}
+	return b.client.rdb.HDel(b.ctx, b.key, fields...).Result()
+}
+
+// Fields returns the names of every field in the hash.
+func (b *KabinetBuilder) Fields() ([]string, error) {
+	return b.client.rdb.HKeys(b.ctx, b.key).Result()
+}
+
+// Len returns the number of fields in the hash.
+func (b *KabinetBuilder) Len() (int64, error) {
+	return b.client.rdb.HLen(b.ctx, b.key).Result()
+}
+
+// RandomFields returns up to n distinct field names picked at random
+// with HRANDFIELD, for sampling large hashes. Requires Redis 6.2+.
+func (b *KabinetBuilder) RandomFields(n int) ([]string, error) {
+	if err := b.client.requireVersion(b.ctx, "HRANDFIELD", Version{Major: 6, Minor: 2}); err != nil {
+		return nil, err
+	}
+	return b.client.rdb.HRandField(b.ctx, b.key, n).Result()
+}
+
+// checkHashFields returns an error if v is a struct not mapping every name.
+func checkHashFields(v any, names []string) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	known := make(map[string]bool)
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, ok := hashTag(t.Field(i)); ok {
+			known[name] = true
+		}
+	}
+	for _, name := range names {
+		if !known[name] {
+			return fmt.Errorf("gibrun: %s has no field tagged %q", t, name)
+		}
+	}
+	return nil
+}
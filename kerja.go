@@ -0,0 +1,344 @@
+package gibrun
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// KerjaConfig configures a Kerja job queue.
+// "Kerja" means work - jobs are handed to workers, at least once each.
+type KerjaConfig struct {
+	// Name is the queue's key prefix in Redis. Required.
+	// In a cluster, wrap it in a hash tag (e.g. "{jobs:email}") so the
+	// queue's keys share a slot.
+	Name string
+
+	// Visibility is how long a claimed job stays hidden from other
+	// workers. Running jobs are kept hidden by a heartbeat; a job whose
+	// worker crashed is handed out again once it runs out.
+	// Default is 30 seconds; values under 3ms are rejected.
+	Visibility time.Duration
+
+	// MaxAttempts is how many times a job is run before it is recorded in
+	// the client FailureLog (source "kerja:<name>") and dropped.
+	// Default is 5.
+	MaxAttempts int64
+
+	// RetryDelay is how long a failed job waits before its next attempt.
+	// Default is 10 seconds.
+	RetryDelay time.Duration
+
+	// PollInterval is how long an idle worker waits before looking for
+	// jobs again. Default is 1 second.
+	PollInterval time.Duration
+
+	// Codec overrides the client codec for job payloads.
+	Codec Codec
+}
+
+// Kerja is a reliable job queue on Redis sorted sets, built on Claim,
+// Ack and Nack. Payloads live in a hash next to the queue.
+type Kerja struct {
+	client *Client
+	cfg    KerjaConfig
+	// err holds configuration problems found at construction.
+	err error
+}
+
+// Job is a job handed to a JobHandler.
+type Job struct {
+	// ID is the job ID returned by Enqueue.
+	ID string
+	// Data is the stored payload; use Bind to decode it.
+	Data []byte
+	// Attempt counts the runs of this job, starting at 1.
+	Attempt int64
+
+	enc   *encoding
+	codec Codec
+}
+
+// Bind unmarshals the payload into dest.
+func (j Job) Bind(dest any) error {
+	if dest == nil {
+		return ErrNilPointer
+	}
+	return j.enc.unmarshal(j.Data, dest, j.codec)
+}
+
+// JobHandler runs one job. Returning nil completes it; an error retries
+// it after RetryDelay until MaxAttempts is reached.
+type JobHandler func(ctx context.Context, job Job) error
+
+// NewKerja creates a job queue. An invalid configuration (e.g. a
+// missing Name) is reported by every call; check it up front with Err.
+//
+// Example:
+//
+//	emails := gibrun.NewKerja(app, gibrun.KerjaConfig{Name: "{jobs:email}"})
+//	id, err := emails.Enqueue(ctx, WelcomeEmail{UserID: 42})
+//
+//	// in a worker process, until ctx is canceled
+//	err = emails.Work(ctx, 8, func(ctx context.Context, job gibrun.Job) error {
+//	    var msg WelcomeEmail
+//	    if err := job.Bind(&msg); err != nil {
+//	        return err
+//	    }
+//	    return send(ctx, msg)
+//	})
+func NewKerja(client *Client, cfg KerjaConfig) *Kerja {
+	err := cfg.Validate()
+	if cfg.Visibility == 0 {
+		cfg.Visibility = 30 * time.Second
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.RetryDelay == 0 {
+		cfg.RetryDelay = 10 * time.Second
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = time.Second
+	}
+	return &Kerja{client: client, cfg: cfg, err: err}
+}
+
+// Err returns the configuration error found at construction, if any.
+func (q *Kerja) Err() error {
+	return q.err
+}
+
+// Enqueue adds a job, marshalled exactly like Gib, and returns its ID.
+// The job is due immediately.
+func (q *Kerja) Enqueue(ctx context.Context, payload any) (string, error) {
+	return q.enqueue(ctx, payload, q.client.clock.Now())
+}
+
+// EnqueueAt adds a job that becomes due at runAt, for reminders and
+// deferred work. Due jobs are moved to a worker by the same atomic claim
+// as immediate ones, so each run goes to exactly one worker however many
+// are polling. A time in the past makes the job due immediately.
+//
+// Example:
+//
+//	id, err := reminders.EnqueueAt(ctx, Reminder{UserID: 42}, appt.Start.Add(-time.Hour))
+func (q *Kerja) EnqueueAt(ctx context.Context, payload any, runAt time.Time) (string, error) {
+	return q.enqueue(ctx, payload, runAt)
+}
+
+// EnqueueIn adds a job that becomes due after d, see EnqueueAt.
+//
+// Example:
+//
+//	id, err := followups.EnqueueIn(ctx, Followup{OrderID: 7}, 24*time.Hour)
+func (q *Kerja) EnqueueIn(ctx context.Context, payload any, d time.Duration) (string, error) {
+	return q.enqueue(ctx, payload, q.client.clock.Now().Add(d))
+}
+
+// enqueue stores the payload and schedules the job at the given time.
+func (q *Kerja) enqueue(ctx context.Context, payload any, at time.Time) (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	if payload == nil {
+		return "", ErrNilValue
+	}
+	data, err := q.client.enc.marshal(payload, q.cfg.Codec)
+	if err != nil {
+		return "", err
+	}
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	id := hex.EncodeToString(raw)
+
+	_, err = q.client.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, q.jobsKey(), id, data)
+		pipe.ZAdd(ctx, q.cfg.Name, redis.Z{Score: float64(at.UnixMilli()), Member: id})
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Len returns the number of jobs waiting, including scheduled and
+// retrying ones but not those currently running.
+func (q *Kerja) Len(ctx context.Context) (int64, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
+	return q.client.rdb.ZCard(ctx, q.cfg.Name).Result()
+}
+
+// Work runs handler on jobs with concurrency workers until ctx is
+// canceled, then waits for running jobs to finish and returns nil.
+// Handlers receive ctx and should stop early when it is canceled; a job
+// interrupted that way goes back to the queue without using an attempt.
+//
+// Delivery is at least once: a job whose worker crashed, or whose
+// handler outlived a lost heartbeat, runs again. Transient Redis errors
+// are retried after a second; other errors stop all workers and are
+// returned.
+func (q *Kerja) Work(ctx context.Context, concurrency int, handler JobHandler) error {
+	if q.err != nil {
+		return q.err
+	}
+	concurrency = max(concurrency, 1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.workLoop(ctx, handler); err != nil {
+				once.Do(func() { firstErr = err })
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// workLoop claims and runs jobs one at a time until ctx is canceled.
+func (q *Kerja) workLoop(ctx context.Context, handler JobHandler) error {
+	for ctx.Err() == nil {
+		ids, err := q.client.Claim(ctx, q.cfg.Name, 1, q.cfg.Visibility)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			if !isTransient(err) {
+				return err
+			}
+			sleepCtx(ctx, q.client.clock, time.Second)
+			continue
+		}
+		if len(ids) == 0 {
+			sleepCtx(ctx, q.client.clock, q.cfg.PollInterval)
+			continue
+		}
+		if err := q.run(ctx, ids[0], handler); err != nil && !isTransient(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// run loads a claimed job, runs handler with a heartbeat and settles it.
+func (q *Kerja) run(ctx context.Context, id string, handler JobHandler) error {
+	// Bookkeeping must finish even when shutting down
+	bg := context.WithoutCancel(ctx)
+
+	var data *redis.StringCmd
+	var attempt *redis.IntCmd
+	_, err := q.client.rdb.TxPipelined(bg, func(pipe redis.Pipeliner) error {
+		data = pipe.HGet(bg, q.jobsKey(), id)
+		attempt = pipe.HIncrBy(bg, q.attemptsKey(), id, 1)
+		return nil
+	})
+	if err == redis.Nil {
+		// Payload gone, e.g. deleted by hand
+		return q.finish(bg, id)
+	}
+	if err != nil {
+		return err
+	}
+
+	job := Job{
+		ID:      id,
+		Data:    []byte(data.Val()),
+		Attempt: attempt.Val(),
+		enc:     &q.client.enc,
+		codec:   q.cfg.Codec,
+	}
+
+	stop := q.heartbeat(bg, id)
+	err = handler(ctx, job)
+	stop()
+
+	switch {
+	case err == nil:
+		return q.finish(bg, id)
+	case ctx.Err() != nil:
+		// Interrupted by shutdown, hand it back untouched
+		if err := q.client.rdb.HIncrBy(bg, q.attemptsKey(), id, -1).Err(); err != nil {
+			return err
+		}
+		_, err := q.client.Nack(bg, q.cfg.Name, id, 0)
+		return err
+	case job.Attempt >= q.cfg.MaxAttempts:
+		err = fmt.Errorf("after %d attempts: %w", job.Attempt, err)
+		q.client.Failures().Record(bg, "kerja:"+q.cfg.Name, id, job.Data, err)
+		return q.finish(bg, id)
+	default:
+		_, err := q.client.Nack(bg, q.cfg.Name, id, q.cfg.RetryDelay)
+		return err
+	}
+}
+
+// heartbeat pushes the job's visibility deadline forward while it runs.
+// The returned func stops it.
+//
+// Beats are paced on wall time, not the client clock: they keep pace with
+// the handler, which runs in real time, and a ManualClock's Sleep returns
+// at once, so a clock-paced loop would spin and race the clock forward.
+// Deadlines are still read from the client clock, like the reaper's.
+func (q *Kerja) heartbeat(ctx context.Context, id string) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(q.cfg.Visibility / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				deadline := q.client.clock.Now().Add(q.cfg.Visibility).UnixMilli()
+				// XX: don't resurrect a reservation that was already settled
+				q.client.rdb.ZAddXX(ctx, q.cfg.Name+inflightSuffix, redis.Z{Score: float64(deadline), Member: id})
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// finish acknowledges a job and drops its payload.
+func (q *Kerja) finish(ctx context.Context, id string) error {
+	_, err := q.client.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, q.cfg.Name+inflightSuffix, id)
+		pipe.HDel(ctx, q.jobsKey(), id)
+		pipe.HDel(ctx, q.attemptsKey(), id)
+		return nil
+	})
+	return err
+}
+
+// jobsKey is the hash of job payloads by ID.
+func (q *Kerja) jobsKey() string {
+	return q.cfg.Name + ":jobs"
+}
+
+// attemptsKey is the hash of attempt counts by ID.
+func (q *Kerja) attemptsKey() string {
+	return q.cfg.Name + ":attempts"
+}
@@ -0,0 +1,92 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keyspaceFlags are the notify-keyspace-events flags WatchExpirations
+// needs: keyspace channels (K), generic commands such as DEL (g) and
+// expirations (x).
+const keyspaceFlags = "Kgx"
+
+// WatchExpirations calls fn with every key matching pattern (a glob, as
+// in KEYS) that expires or is deleted, for TTL-driven workflows such as
+// session cleanup or refilling a cache entry when it expires. It returns
+// once the subscription is active; it stops when ctx is canceled or the
+// subscription is closed.
+//
+// Keyspace notifications are enabled with CONFIG SET when needed.
+// Returns ErrKeyspaceEventsDisabled if CONFIG GET shows them off and
+// CONFIG SET can't turn them on. On managed servers without CONFIG the
+// flags can't be checked and are assumed set: enable them in the
+// provider settings ("Kgx"), or no events arrive. Like all pub/sub,
+// events that fire while the connection is down are lost, and
+// expirations are reported when Redis notices them, which can lag the
+// TTL.
+//
+// Example:
+//
+//	sub, err := app.WatchExpirations(ctx, "session:*", func(key string) {
+//	    cleanupSession(strings.TrimPrefix(key, "session:"))
+//	})
+//	defer sub.Close()
+func (c *Client) WatchExpirations(ctx context.Context, pattern string, fn func(key string)) (*Subscription, error) {
+	if err := c.enableKeyspaceEvents(ctx); err != nil {
+		return nil, err
+	}
+
+	prefix := "__keyspace@" + strconv.Itoa(c.rdb.Options().DB) + "__:"
+	ps := c.rdb.PSubscribe(ctx, prefix+pattern)
+	if _, err := ps.Receive(ctx); err != nil {
+		ps.Close()
+		return nil, err
+	}
+
+	s := &Subscription{ps: ps, done: make(chan struct{})}
+	s.stop = context.AfterFunc(ctx, func() { s.close() })
+
+	go func() {
+		defer close(s.done)
+		for msg := range ps.Channel() {
+			if msg.Payload == "expired" || msg.Payload == "del" {
+				fn(strings.TrimPrefix(msg.Channel, prefix))
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// enableKeyspaceEvents makes sure keyspaceFlags are enabled. When CONFIG
+// GET is unavailable the flags can't be verified and are assumed set.
+func (c *Client) enableKeyspaceEvents(ctx context.Context) error {
+	cfg, err := c.rdb.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		if _, ok := err.(redis.Error); ok {
+			return nil
+		}
+		return err
+	}
+
+	current := cfg["notify-keyspace-events"]
+	missing := ""
+	for _, f := range keyspaceFlags {
+		// "A" is an alias for every event class, including g and x
+		if !strings.ContainsRune(current, f) && !(f != 'K' && strings.ContainsRune(current, 'A')) {
+			missing += string(f)
+		}
+	}
+	if missing == "" {
+		return nil
+	}
+
+	if err := c.rdb.ConfigSet(ctx, "notify-keyspace-events", current+missing).Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrKeyspaceEventsDisabled, err)
+	}
+	return nil
+}
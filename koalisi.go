@@ -0,0 +1,210 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// KoalisiBuilder provides a fluent API for Redis sets.
+// "Koalisi" means coalition: members join, leave and combine with other
+// sets for audience and segment computations.
+//
+// Members are stored in plain text for strings, numbers, bools and times
+// so they compare as expected and stay readable from redis-cli; other
+// types go through the codec without compression or encryption, since
+// membership needs the same value to always encode to the same bytes.
+type KoalisiBuilder struct {
+	ctx    context.Context
+	client *Client
+	key    string
+	codec  Codec
+}
+
+// KoalisiMembers is a set of members waiting to be read, see Members,
+// Union, Intersect and Diff.
+type KoalisiMembers struct {
+	b    *KoalisiBuilder
+	op   string
+	keys []string
+}
+
+// Koalisi starts a set operation.
+//
+// Example:
+//
+//	app.Koalisi(ctx, "segment:premium").Add(101, 102, 103)
+//	app.Koalisi(ctx, "segment:active").Add(102, 103, 104)
+//
+//	var ids []int
+//	err := app.Koalisi(ctx, "segment:premium").Intersect("segment:active").Bind(&ids)
+func (c *Client) Koalisi(ctx context.Context, key string) *KoalisiBuilder {
+	return &KoalisiBuilder{
+		ctx:    ctx,
+		client: c,
+		key:    key,
+	}
+}
+
+// Codec overrides the client codec for non-scalar members.
+func (b *KoalisiBuilder) Codec(c Codec) *KoalisiBuilder {
+	b.codec = c
+	return b
+}
+
+// Add adds members and returns how many were not already in the set.
+func (b *KoalisiBuilder) Add(members ...any) (int64, error) {
+	vals, err := b.client.enc.members(members, b.codec)
+	if err != nil {
+		return 0, err
+	}
+	return b.client.rdb.SAdd(b.ctx, b.key, vals...).Result()
+}
+
+// Remove removes members and returns how many were in the set.
+func (b *KoalisiBuilder) Remove(members ...any) (int64, error) {
+	vals, err := b.client.enc.members(members, b.codec)
+	if err != nil {
+		return 0, err
+	}
+	return b.client.rdb.SRem(b.ctx, b.key, vals...).Result()
+}
+
+// IsMember reports whether member is in the set.
+func (b *KoalisiBuilder) IsMember(member any) (bool, error) {
+	vals, err := b.client.enc.members([]any{member}, b.codec)
+	if err != nil {
+		return false, err
+	}
+	return b.client.rdb.SIsMember(b.ctx, b.key, vals[0]).Result()
+}
+
+// Len returns the number of members.
+func (b *KoalisiBuilder) Len() (int64, error) {
+	return b.client.rdb.SCard(b.ctx, b.key).Result()
+}
+
+// Members selects every member of the set.
+func (b *KoalisiBuilder) Members() *KoalisiMembers {
+	return &KoalisiMembers{b: b, op: "members", keys: []string{b.key}}
+}
+
+// Union selects members of this set or any of others.
+func (b *KoalisiBuilder) Union(others ...string) *KoalisiMembers {
+	return &KoalisiMembers{b: b, op: "union", keys: append([]string{b.key}, others...)}
+}
+
+// Intersect selects members of this set that are in all of others.
+func (b *KoalisiBuilder) Intersect(others ...string) *KoalisiMembers {
+	return &KoalisiMembers{b: b, op: "inter", keys: append([]string{b.key}, others...)}
+}
+
+// Diff selects members of this set that are in none of others.
+func (b *KoalisiBuilder) Diff(others ...string) *KoalisiMembers {
+	return &KoalisiMembers{b: b, op: "diff", keys: append([]string{b.key}, others...)}
+}
+
+// Strings returns the selected members as stored, in no particular order.
+func (m *KoalisiMembers) Strings() ([]string, error) {
+	ctx, rdb := m.b.ctx, m.b.client.rdb
+	switch m.op {
+	case "union":
+		return rdb.SUnion(ctx, m.keys...).Result()
+	case "inter":
+		return rdb.SInter(ctx, m.keys...).Result()
+	case "diff":
+		return rdb.SDiff(ctx, m.keys...).Result()
+	default:
+		return rdb.SMembers(ctx, m.keys[0]).Result()
+	}
+}
+
+// Bind decodes the selected members into dest, a pointer to a slice.
+// Order is unspecified.
+func (m *KoalisiMembers) Bind(dest any) error {
+	members, err := m.Strings()
+	if err != nil {
+		return err
+	}
+	return bindMembers(members, dest, m.b.client.enc.codecFor(m.b.codec))
+}
+
+// Store writes the selected members to the set at key, replacing it,
+// and returns its size. Useful to cache a computed segment.
+func (m *KoalisiMembers) Store(key string) (int64, error) {
+	ctx, rdb := m.b.ctx, m.b.client.rdb
+	switch m.op {
+	case "union":
+		return rdb.SUnionStore(ctx, key, m.keys...).Result()
+	case "inter":
+		return rdb.SInterStore(ctx, key, m.keys...).Result()
+	case "diff":
+		return rdb.SDiffStore(ctx, key, m.keys...).Result()
+	default:
+		return rdb.SUnionStore(ctx, key, m.keys[0]).Result()
+	}
+}
+
+// members encodes set or sorted set members deterministically.
+func (e *encoding) members(members []any, override Codec) ([]any, error) {
+	if len(members) == 0 {
+		return nil, ErrNilValue
+	}
+	vals := make([]any, len(members))
+	for i, member := range members {
+		s, err := e.member(member, override)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = s
+	}
+	return vals, nil
+}
+
+// member encodes a single member, see KoalisiBuilder.
+func (e *encoding) member(member any, override Codec) (string, error) {
+	if member == nil {
+		return "", ErrNilValue
+	}
+	if b, ok := member.([]byte); ok {
+		return string(b), nil
+	}
+	if text, ok := scalarText(reflect.ValueOf(member)); ok {
+		return text, nil
+	}
+	data, err := marshal(member, e.codecFor(override))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// bindMembers decodes members into dest, a pointer to a slice.
+func bindMembers(members []string, dest any, codec Codec) error {
+	rv := reflect.ValueOf(dest)
+	if dest == nil || rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrNilPointer
+	}
+	s := rv.Elem()
+	if s.Kind() != reflect.Slice {
+		return fmt.Errorf("gibrun: Bind needs a pointer to a slice, got %T", dest)
+	}
+
+	out := reflect.MakeSlice(s.Type(), len(members), len(members))
+	for i, member := range members {
+		if err := setText(out.Index(i), []byte(member), codec); err != nil {
+			return fmt.Errorf("gibrun: member %q: %w", member, err)
+		}
+	}
+	s.Set(out)
+	return nil
+}
+
+// bindMember decodes a single member into dest.
+func bindMember(member string, dest any, codec Codec) error {
+	rv := reflect.ValueOf(dest)
+	if dest == nil || rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrNilPointer
+	}
+	return setText(rv.Elem(), []byte(member), codec)
+}
@@ -0,0 +1,296 @@
+package gibrun
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// L1Config configures the in-process cache in front of Redis.
+//
+// Reads through Run (Bind, Raw, Bytes and friends) are served from memory
+// when possible. Every write command sent by the client drops the written
+// keys locally and publishes them on Channel, so other instances with the
+// same Channel drop them too. Entries can be stale for up to TTL if an
+// invalidation is missed (e.g. while the subscriber reconnects), if the
+// key is written by something other than a gibrun client, or if it
+// expires in Redis first, so keep TTL short.
+type L1Config struct {
+	// MaxEntries bounds the number of cached keys; the least recently used
+	// entry is evicted first. Default is 10000.
+	MaxEntries int
+
+	// TTL bounds how long an entry is served from memory. Default is 1 minute.
+	TTL time.Duration
+
+	// Prefixes limits the L1 to keys with one of these prefixes, so only
+	// hot, read-mostly keys take memory. Empty caches every key.
+	Prefixes []string
+
+	// Channel is the pub/sub channel used for cross-instance invalidation.
+	// Default is "gibrun:l1:invalidate".
+	Channel string
+
+	// StaleWhileDegraded keeps serving entries up to this long past TTL
+	// while the client is degraded (see Config.Degradation), taking load
+	// off a struggling Redis. Zero disables it.
+	StaleWhileDegraded time.Duration
+}
+
+// l1Cache is a size- and TTL-bounded LRU of stored bytes.
+type l1Cache struct {
+	cfg   L1Config
+	clock Clock
+
+	// id tags published invalidations so an instance skips its own.
+	id string
+
+	// degraded reports the client degraded state, see StaleWhileDegraded.
+	degraded func() bool
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+
+	sub *redis.PubSub
+}
+
+type l1Entry struct {
+	key     string
+	data    []byte
+	expires time.Time
+}
+
+func newL1Cache(cfg L1Config, clock Clock) *l1Cache {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 10000
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = time.Minute
+	}
+	if cfg.Channel == "" {
+		cfg.Channel = "gibrun:l1:invalidate"
+	}
+
+	id := make([]byte, 8)
+	rand.Read(id)
+
+	return &l1Cache{
+		cfg:     cfg,
+		clock:   clock,
+		id:      hex.EncodeToString(id),
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// eligible reports whether key may be cached.
+func (l *l1Cache) eligible(key string) bool {
+	if len(l.cfg.Prefixes) == 0 {
+		return true
+	}
+	for _, p := range l.cfg.Prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// get returns the cached bytes for key.
+func (l *l1Cache) get(key string) ([]byte, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	el, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*l1Entry)
+	if now := l.clock.Now(); !now.Before(e.expires) && !l.serveStale(now, e) {
+		l.order.Remove(el)
+		delete(l.entries, key)
+		return nil, false
+	}
+	l.order.MoveToFront(el)
+	// Callers may keep and mutate the bytes, e.g. when binding into *[]byte
+	return append([]byte(nil), e.data...), true
+}
+
+// serveStale reports whether an expired entry may still be served
+// because the client is degraded.
+func (l *l1Cache) serveStale(now time.Time, e *l1Entry) bool {
+	return l.cfg.StaleWhileDegraded > 0 && l.degraded != nil && l.degraded() &&
+		now.Before(e.expires.Add(l.cfg.StaleWhileDegraded))
+}
+
+// set caches data for key, for at most ttl when positive.
+func (l *l1Cache) set(key string, data []byte, ttl time.Duration) {
+	if !l.eligible(key) {
+		return
+	}
+	if ttl <= 0 || ttl > l.cfg.TTL {
+		ttl = l.cfg.TTL
+	}
+	expires := l.clock.Now().Add(ttl)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if el, ok := l.entries[key]; ok {
+		e := el.Value.(*l1Entry)
+		e.data, e.expires = data, expires
+		l.order.MoveToFront(el)
+		return
+	}
+	l.entries[key] = l.order.PushFront(&l1Entry{key: key, data: data, expires: expires})
+	for l.order.Len() > l.cfg.MaxEntries {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*l1Entry).key)
+	}
+}
+
+// drop removes keys from the cache.
+func (l *l1Cache) drop(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		if el, ok := l.entries[key]; ok {
+			l.order.Remove(el)
+			delete(l.entries, key)
+		}
+	}
+}
+
+// subscribe starts listening for invalidations from other instances.
+func (l *l1Cache) subscribe(rdb *redis.Client) {
+	l.sub = rdb.Subscribe(context.Background(), l.cfg.Channel)
+	go func() {
+		for msg := range l.sub.Channel() {
+			from, keys, ok := strings.Cut(msg.Payload, "\n")
+			if !ok || from == l.id {
+				continue
+			}
+			l.drop(strings.Split(keys, "\n")...)
+		}
+	}()
+}
+
+// close stops the subscriber.
+func (l *l1Cache) close() error {
+	if l.sub == nil {
+		return nil
+	}
+	return l.sub.Close()
+}
+
+// l1Hook drops written keys and publishes them to other instances.
+type l1Hook struct {
+	l1  *l1Cache
+	rdb *redis.Client
+}
+
+func (h l1Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h l1Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		h.invalidate(ctx, writtenKeys(cmd))
+		return err
+	}
+}
+
+func (h l1Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		var keys []string
+		for _, cmd := range cmds {
+			keys = append(keys, writtenKeys(cmd)...)
+		}
+		h.invalidate(ctx, keys)
+		return err
+	}
+}
+
+// invalidate drops keys locally, then publishes them. Publishing before
+// the write returns keeps "write, then notify" flows from reading stale
+// data on other instances.
+func (h l1Hook) invalidate(ctx context.Context, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	h.l1.drop(keys...)
+	h.rdb.Publish(context.WithoutCancel(ctx), h.l1.cfg.Channel, h.l1.id+"\n"+strings.Join(keys, "\n"))
+}
+
+// l1SingleKeyWrites are write commands whose only key is the first argument.
+var l1SingleKeyWrites = map[string]bool{
+	"set": true, "setex": true, "psetex": true, "setnx": true, "getset": true,
+	"getdel": true, "append": true, "setrange": true, "restore": true,
+	"incr": true, "incrby": true, "incrbyfloat": true, "decr": true, "decrby": true,
+	"expire": true, "pexpire": true, "expireat": true, "pexpireat": true,
+	"hset": true, "hsetnx": true, "hdel": true, "hincrby": true, "hincrbyfloat": true, "hmset": true,
+}
+
+// writtenKeys returns the keys a command may modify.
+func writtenKeys(cmd redis.Cmder) []string {
+	args := cmd.Args()
+	name := strings.ToLower(cmd.Name())
+	switch {
+	case l1SingleKeyWrites[name] && len(args) > 1:
+		return []string{argString(args[1])}
+	case name == "del" || name == "unlink":
+		keys := make([]string, 0, len(args)-1)
+		for _, a := range args[1:] {
+			keys = append(keys, argString(a))
+		}
+		return keys
+	case name == "mset" || name == "msetnx":
+		var keys []string
+		for i := 1; i < len(args); i += 2 {
+			keys = append(keys, argString(args[i]))
+		}
+		return keys
+	case name == "rename" || name == "renamenx":
+		if len(args) > 2 {
+			return []string{argString(args[1]), argString(args[2])}
+		}
+	case name == "eval" || name == "evalsha" || name == "fcall":
+		// Scripts may write any of their keys
+		if len(args) > 2 {
+			n, _ := strconv.Atoi(argString(args[2]))
+			var keys []string
+			for i := 3; i < 3+n && i < len(args); i++ {
+				keys = append(keys, argString(args[i]))
+			}
+			return keys
+		}
+	}
+	return nil
+}
+
+// argString renders a command argument as Redis sees it.
+func argString(a any) string {
+	switch v := a.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	default:
+		return ""
+	}
+}
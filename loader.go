@@ -0,0 +1,108 @@
+package gibrun
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// LoaderFunc loads a value on a cache miss, returning it with the TTL to
+// cache it for. Returning a nil value or ErrNotFound means "not found":
+// Bind reports a miss, and nothing is cached unless WithNegativeCache
+// is set.
+type LoaderFunc func(ctx context.Context) (any, time.Duration, error)
+
+// OrElse sets a loader used by Bind on a cache miss. Concurrent misses
+// for the same key in this process share a single loader call
+// (singleflight); its result is stored with the returned TTL and bound
+// into every caller's destination. A failed cache write doesn't fail
+// the read - the loaded value is still returned.
+//
+// Example:
+//
+//	var user User
+//	found, err := app.Run(ctx, "user:123").OrElse(func(ctx context.Context) (any, time.Duration, error) {
+//	    u, err := db.GetUser(ctx, 123)
+//	    return u, time.Hour, err
+//	}).Bind(&user)
+func (b *RunBuilder) OrElse(loader LoaderFunc) *RunBuilder {
+	b.loader = loader
+	return b
+}
+
+// load runs the loader through the client's singleflight group and binds
+// the result into dest.
+func (b *RunBuilder) load(dest any) (bool, error) {
+	data, err := b.client.flights.do(b.key, b.loadAndStore)
+	if err != nil || data == nil {
+		return false, err
+	}
+
+	if err := unmarshal(data, dest, b.client.enc.codecFor(b.codec)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// loadAndStore runs the loader and caches its result, returning the
+// encoded value. With stale-while-revalidate the value is kept maxStale
+// past the loader's TTL.
+func (b *RunBuilder) loadAndStore() ([]byte, error) {
+	v, ttl, err := b.loader(b.ctx)
+	if (err == nil && v == nil) || isNotFound(err) {
+		b.storeNegative()
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	data, err := marshal(v, b.client.enc.codecFor(b.codec))
+	if err != nil {
+		return nil, err
+	}
+	if ttl > 0 {
+		ttl += b.maxStale
+	}
+	_ = b.primary().Gib(b.ctx, b.key).Value(v).TTL(ttl).Codec(b.codec).Exec()
+	return data, nil
+}
+
+// flightGroup deduplicates concurrent calls by key.
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall is an in-progress or completed call.
+type flightCall struct {
+	wg   sync.WaitGroup
+	data []byte
+	err  error
+}
+
+// do runs fn once for all concurrent callers with the same key.
+func (g *flightGroup) do(key string, fn func() ([]byte, error)) ([]byte, error) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[string]*flightCall)
+	}
+	if c, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.data, c.err
+	}
+	c := &flightCall{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		delete(g.calls, key)
+		g.mu.Unlock()
+		c.wg.Done()
+	}()
+
+	c.data, c.err = fn()
+	return c.data, c.err
+}
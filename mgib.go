@@ -0,0 +1,160 @@
+package gibrun
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MGibBuilder provides a fluent API for storing many keys in a single
+// round trip. Values are marshalled exactly like Gib.
+type MGibBuilder struct {
+	ctx     context.Context
+	client  *Client
+	entries []mgibEntry
+}
+
+// mgibEntry is a single queued write in a batch.
+type mgibEntry struct {
+	key   string
+	value any
+	ttl   time.Duration
+}
+
+// MGib starts a batch storage operation.
+// Writing 500 entries costs one round trip instead of 500.
+//
+// Example:
+//
+//	err := app.MGib(ctx).
+//	    Add("user:1", user1, 5*time.Minute).
+//	    Add("user:2", user2, 5*time.Minute).
+//	    Exec()
+func (c *Client) MGib(ctx context.Context) *MGibBuilder {
+	return &MGibBuilder{
+		ctx:    ctx,
+		client: c,
+	}
+}
+
+// Add queues a value to be stored under key.
+// A zero ttl means the data will persist indefinitely.
+func (b *MGibBuilder) Add(key string, value any, ttl time.Duration) *MGibBuilder {
+	b.entries = append(b.entries, mgibEntry{key: key, value: value, ttl: ttl})
+	return b
+}
+
+// Len returns the number of queued writes.
+func (b *MGibBuilder) Len() int {
+	return len(b.entries)
+}
+
+// Exec executes all queued writes in one round trip.
+// Uses MSET when no entry has a TTL, otherwise a pipeline of SETs.
+// Returns ErrNilValue without writing anything if any value is nil.
+// With Config.ChunkSize, values above it are stored chunked like Gib,
+// one transaction each, and the rest replace any chunked value in a
+// single transaction.
+func (b *MGibBuilder) Exec() error {
+	if len(b.entries) == 0 {
+		return nil
+	}
+
+	// Marshal everything up front so a bad value aborts the whole batch
+	data := make([][]byte, len(b.entries))
+	ttls := make([]time.Duration, len(b.entries))
+	for i, e := range b.entries {
+		if e.value == nil {
+			return ErrNilValue
+		}
+		if err := b.client.validate(e.key, e.value); err != nil {
+			return err
+		}
+		d, err := b.client.enc.marshal(e.value, nil)
+		if err != nil {
+			return err
+		}
+		data[i] = d
+		ttls[i] = b.client.ttl.apply(e.key, e.ttl)
+	}
+	// Only count writes once the whole batch is known to be valid
+	for _, e := range b.entries {
+		if err := b.client.guardWrite(e.key); err != nil {
+			return err
+		}
+	}
+
+	var small []int
+	for i, e := range b.entries {
+		if b.client.chunkSize > 0 && len(data[i]) > b.client.chunkSize {
+			if err := b.client.Gib(b.ctx, e.key).execChunked(data[i], ttls[i]); err != nil {
+				return err
+			}
+			continue
+		}
+		small = append(small, i)
+	}
+	if err := b.write(small, data, ttls); err != nil {
+		return err
+	}
+
+	for i, e := range b.entries {
+		b.client.mirrorWrite(e.key, data[i], ttls[i])
+	}
+	return nil
+}
+
+// write stores the entries at idx unchunked.
+func (b *MGibBuilder) write(idx []int, data [][]byte, ttls []time.Duration) error {
+	if len(idx) == 0 {
+		return nil
+	}
+	if b.client.chunkSize > 0 {
+		return b.writeOverChunks(idx, data, ttls)
+	}
+
+	hasTTL := false
+	for _, i := range idx {
+		hasTTL = hasTTL || ttls[i] > 0
+	}
+	if !hasTTL {
+		pairs := make([]any, 0, len(idx)*2)
+		for _, i := range idx {
+			pairs = append(pairs, b.entries[i].key, data[i])
+		}
+		return b.client.rdb.MSet(b.ctx, pairs...).Err()
+	}
+	_, err := b.client.rdb.Pipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		for _, i := range idx {
+			pipe.Set(b.ctx, b.entries[i].key, data[i], ttls[i])
+		}
+		return nil
+	})
+	return err
+}
+
+// writeOverChunks is write for clients with chunking enabled: chunks of
+// values being replaced are dropped in the same MULTI, like setOverChunks.
+func (b *MGibBuilder) writeOverChunks(idx []int, data [][]byte, ttls []time.Duration) error {
+	keys := make([]string, len(idx))
+	for j, i := range idx {
+		keys[j] = b.entries[i].key
+	}
+	return b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		stale, err := storedChunks(b.ctx, tx, keys, 0)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			for _, i := range idx {
+				pipe.Set(b.ctx, b.entries[i].key, data[i], ttls[i])
+			}
+			if len(stale) > 0 {
+				pipe.Del(b.ctx, stale...)
+			}
+			return nil
+		})
+		return err
+	}, keys...)
+}
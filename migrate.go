@@ -3,6 +3,7 @@ package gibrun
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -31,12 +32,24 @@ type MigrateOptions struct {
 	// total is the estimated total (-1 if unknown).
 	OnProgress func(done, total int)
 
+	// OnProgressDetail is called after each batch with throughput, ETA
+	// and the errors of the batch just processed.
+	OnProgressDetail func(p MigrateProgress)
+
+	// Tracker receives a live snapshot after each batch.
+	// Read it from other goroutines via MigrationStatus().
+	Tracker *MigrationTracker
+
 	// OnError is called when a key fails to migrate.
 	// Return true to continue, false to abort.
 	OnError func(key string, err error) bool
 
 	// DryRun if true, only counts keys without actually migrating.
 	DryRun bool
+
+	// FailureLog, if set, records every failed key under the "migration"
+	// source for later inspection and retry.
+	FailureLog *FailureLog
 }
 
 // MigrateResult contains the result of a migration operation.
@@ -63,6 +76,113 @@ type MigrateError struct {
 	Error error
 }
 
+// MigrateProgress is a point-in-time view of a running migration.
+type MigrateProgress struct {
+	// Done is the number of keys processed so far (migrated + failed).
+	Done int
+
+	// Total is the number of keys found matching the pattern.
+	Total int
+
+	// MigratedKeys is the number of keys successfully migrated so far.
+	MigratedKeys int
+
+	// FailedKeys is the number of keys that failed so far.
+	FailedKeys int
+
+	// Bytes is the number of value bytes transferred so far.
+	Bytes int64
+
+	// Elapsed is the time since the migration started.
+	Elapsed time.Duration
+
+	// KeysPerSec is the average key throughput since start.
+	KeysPerSec float64
+
+	// BytesPerSec is the average byte throughput since start.
+	BytesPerSec float64
+
+	// ETA is the estimated time remaining, zero if unknown.
+	ETA time.Duration
+
+	// BatchErrors contains the failures of the most recent batch.
+	BatchErrors []MigrateError
+
+	// Finished is true once the migration has returned.
+	Finished bool
+}
+
+// MigrationTracker holds a live snapshot of a migration so dashboards
+// can poll it while Migrate runs. The zero value is ready to use.
+//
+// Example:
+//
+//	tracker := &gibrun.MigrationTracker{}
+//	go gibrun.Migrate(ctx, src, dst, gibrun.MigrateOptions{Tracker: tracker})
+//	status := tracker.MigrationStatus()
+type MigrationTracker struct {
+	mu     sync.RWMutex
+	status MigrateProgress
+}
+
+// MigrationStatus returns the latest migration snapshot.
+func (t *MigrationTracker) MigrationStatus() MigrateProgress {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.status
+}
+
+func (t *MigrationTracker) update(p MigrateProgress) {
+	t.mu.Lock()
+	t.status = p
+	t.mu.Unlock()
+}
+
+// migrateMeter accumulates throughput numbers for progress reporting.
+type migrateMeter struct {
+	opts  MigrateOptions
+	start time.Time
+	bytes int64
+}
+
+// report builds a MigrateProgress snapshot and delivers it to the callbacks and tracker.
+func (m *migrateMeter) report(result *MigrateResult, batchErrs []MigrateError, finished bool) {
+	done := result.MigratedKeys + result.FailedKeys
+	elapsed := time.Since(m.start)
+
+	p := MigrateProgress{
+		Done:         done,
+		Total:        result.TotalKeys,
+		MigratedKeys: result.MigratedKeys,
+		FailedKeys:   result.FailedKeys,
+		Bytes:        m.bytes,
+		Elapsed:      elapsed,
+		BatchErrors:  batchErrs,
+		Finished:     finished,
+	}
+
+	if secs := elapsed.Seconds(); secs > 0 {
+		p.KeysPerSec = float64(done) / secs
+		p.BytesPerSec = float64(m.bytes) / secs
+	}
+	if p.KeysPerSec > 0 && done < result.TotalKeys {
+		remaining := float64(result.TotalKeys - done)
+		p.ETA = time.Duration(remaining / p.KeysPerSec * float64(time.Second))
+	}
+
+	if m.opts.Tracker != nil {
+		m.opts.Tracker.update(p)
+	}
+	if !finished {
+		if m.opts.OnProgress != nil {
+			m.opts.OnProgress(done, result.TotalKeys)
+		}
+		if m.opts.OnProgressDetail != nil {
+			m.opts.OnProgressDetail(p)
+		}
+	}
+}
+
 // Migrate transfers data from source to destination Redis.
 // This is the "hilirisasi" of data - moving raw resources (keys)
 // from one region to another for localized processing.
@@ -88,6 +208,8 @@ func Migrate(ctx context.Context, src, dst *Client, opts MigrateOptions) (*Migra
 	}
 
 	result := &MigrateResult{}
+	meter := &migrateMeter{opts: opts, start: startTime}
+	defer meter.report(result, nil, true)
 
 	// Scan all keys matching pattern
 	keys, err := scanAllKeys(ctx, src, opts.Pattern)
@@ -118,10 +240,17 @@ func Migrate(ctx context.Context, src, dst *Client, opts MigrateOptions) (*Migra
 		batch := keys[i:end]
 
 		// Migrate batch
+		var batchErrs []MigrateError
 		for _, key := range batch {
-			if err := migrateKey(ctx, src, dst, key, opts); err != nil {
+			n, err := migrateKey(ctx, src, dst, key, opts)
+			meter.bytes += int64(n)
+			if err != nil {
 				result.FailedKeys++
 				result.Errors = append(result.Errors, MigrateError{Key: key, Error: err})
+				batchErrs = append(batchErrs, MigrateError{Key: key, Error: err})
+				if opts.FailureLog != nil {
+					opts.FailureLog.Record(ctx, "migration", key, nil, err)
+				}
 
 				if opts.OnError != nil {
 					if !opts.OnError(key, err) {
@@ -135,9 +264,7 @@ func Migrate(ctx context.Context, src, dst *Client, opts MigrateOptions) (*Migra
 		}
 
 		// Report progress
-		if opts.OnProgress != nil {
-			opts.OnProgress(result.MigratedKeys+result.FailedKeys, result.TotalKeys)
-		}
+		meter.report(result, batchErrs, false)
 	}
 
 	result.Duration = time.Since(startTime)
@@ -157,6 +284,8 @@ func MigrateCluster(ctx context.Context, src *ClusterClient, dst *Client, opts M
 	}
 
 	result := &MigrateResult{}
+	meter := &migrateMeter{opts: opts, start: startTime}
+	defer meter.report(result, nil, true)
 
 	// Scan all keys from cluster
 	keys, err := scanAllClusterKeys(ctx, src, opts.Pattern)
@@ -186,10 +315,17 @@ func MigrateCluster(ctx context.Context, src *ClusterClient, dst *Client, opts M
 		}
 		batch := keys[i:end]
 
+		var batchErrs []MigrateError
 		for _, key := range batch {
-			if err := migrateClusterKey(ctx, src, dst, key, opts); err != nil {
+			n, err := migrateClusterKey(ctx, src, dst, key, opts)
+			meter.bytes += int64(n)
+			if err != nil {
 				result.FailedKeys++
 				result.Errors = append(result.Errors, MigrateError{Key: key, Error: err})
+				batchErrs = append(batchErrs, MigrateError{Key: key, Error: err})
+				if opts.FailureLog != nil {
+					opts.FailureLog.Record(ctx, "migration", key, nil, err)
+				}
 
 				if opts.OnError != nil {
 					if !opts.OnError(key, err) {
@@ -202,9 +338,7 @@ func MigrateCluster(ctx context.Context, src *ClusterClient, dst *Client, opts M
 			}
 		}
 
-		if opts.OnProgress != nil {
-			opts.OnProgress(result.MigratedKeys+result.FailedKeys, result.TotalKeys)
-		}
+		meter.report(result, batchErrs, false)
 	}
 
 	result.Duration = time.Since(startTime)
@@ -256,11 +390,12 @@ func scanAllClusterKeys(ctx context.Context, client *ClusterClient, pattern stri
 }
 
 // migrateKey migrates a single key from src to dst.
-func migrateKey(ctx context.Context, src, dst *Client, key string, opts MigrateOptions) error {
+// Returns the number of value bytes transferred.
+func migrateKey(ctx context.Context, src, dst *Client, key string, opts MigrateOptions) (int, error) {
 	// Get value
 	data, err := src.rdb.Get(ctx, key).Bytes()
 	if err != nil {
-		return fmt.Errorf("get failed: %w", err)
+		return 0, fmt.Errorf("get failed: %w", err)
 	}
 
 	// Get TTL if preserving
@@ -268,7 +403,7 @@ func migrateKey(ctx context.Context, src, dst *Client, key string, opts MigrateO
 	if opts.PreserveTTL {
 		ttl, err = src.rdb.TTL(ctx, key).Result()
 		if err != nil {
-			return fmt.Errorf("ttl failed: %w", err)
+			return 0, fmt.Errorf("ttl failed: %w", err)
 		}
 		// TTL returns -1 for no expiration, -2 for key not found
 		if ttl < 0 {
@@ -280,18 +415,19 @@ func migrateKey(ctx context.Context, src, dst *Client, key string, opts MigrateO
 
 	// Set value
 	if err := dst.rdb.Set(ctx, key, data, ttl).Err(); err != nil {
-		return fmt.Errorf("set failed: %w", err)
+		return 0, fmt.Errorf("set failed: %w", err)
 	}
 
-	return nil
+	return len(data), nil
 }
 
 // migrateClusterKey migrates a single key from cluster src to dst.
-func migrateClusterKey(ctx context.Context, src *ClusterClient, dst *Client, key string, opts MigrateOptions) error {
+// Returns the number of value bytes transferred.
+func migrateClusterKey(ctx context.Context, src *ClusterClient, dst *Client, key string, opts MigrateOptions) (int, error) {
 	// Get value from cluster
 	data, err := src.rdb.Get(ctx, key).Bytes()
 	if err != nil {
-		return fmt.Errorf("get failed: %w", err)
+		return 0, fmt.Errorf("get failed: %w", err)
 	}
 
 	// Get TTL if preserving
@@ -299,7 +435,7 @@ func migrateClusterKey(ctx context.Context, src *ClusterClient, dst *Client, key
 	if opts.PreserveTTL {
 		ttl, err = src.rdb.TTL(ctx, key).Result()
 		if err != nil {
-			return fmt.Errorf("ttl failed: %w", err)
+			return 0, fmt.Errorf("ttl failed: %w", err)
 		}
 		if ttl < 0 {
 			ttl = 0
@@ -310,8 +446,8 @@ func migrateClusterKey(ctx context.Context, src *ClusterClient, dst *Client, key
 
 	// Set value to single-node destination
 	if err := dst.rdb.Set(ctx, key, data, ttl).Err(); err != nil {
-		return fmt.Errorf("set failed: %w", err)
+		return 0, fmt.Errorf("set failed: %w", err)
 	}
 
-	return nil
+	return len(data), nil
 }
@@ -0,0 +1,345 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// MRunBuilder provides a fluent API for retrieving many keys in a single
+// round trip. Values are decoded exactly like Run.
+type MRunBuilder struct {
+	ctx    context.Context
+	client *Client
+	keys   []string
+	codec  Codec
+}
+
+// MRun starts a batch retrieval operation backed by MGET.
+// Fetching 200 users costs one round trip instead of 200.
+//
+// Example:
+//
+//	users := map[string]User{}
+//	found, err := app.MRun(ctx, "user:1", "user:2", "user:3").BindMap(&users)
+func (c *Client) MRun(ctx context.Context, keys ...string) *MRunBuilder {
+	return &MRunBuilder{
+		ctx:    ctx,
+		client: c,
+		keys:   keys,
+	}
+}
+
+// Codec overrides the client codec for this operation.
+func (b *MRunBuilder) Codec(c Codec) *MRunBuilder {
+	b.codec = c
+	return b
+}
+
+// BindMap decodes every found value into dest, a pointer to a map keyed
+// by string, using the Redis key as map key. A nil map is allocated.
+// Returns the found keys in request order; misses are left out of the map.
+//
+// Example:
+//
+//	var users map[string]User
+//	found, err := app.MRun(ctx, keys...).BindMap(&users)
+func (b *MRunBuilder) BindMap(dest any) ([]string, error) {
+	vals, err := b.fetch()
+	if err != nil {
+		return nil, err
+	}
+	return bindMap(&b.client.enc, b.codec, b.keys, vals, dest)
+}
+
+// BindSlice decodes every found value into dest, a pointer to a slice.
+// The slice is replaced with the found values in request order, so
+// (*dest)[i] belongs to the i-th returned key.
+//
+// Example:
+//
+//	var users []User
+//	found, err := app.MRun(ctx, keys...).BindSlice(&users)
+func (b *MRunBuilder) BindSlice(dest any) ([]string, error) {
+	vals, err := b.fetch()
+	if err != nil {
+		return nil, err
+	}
+	return bindSlice(&b.client.enc, b.codec, b.keys, vals, dest)
+}
+
+// fetch runs MGET and returns stored bytes aligned with keys, nil for
+// misses. Negative-cache markers count as misses and chunked values are
+// reassembled.
+func (b *MRunBuilder) fetch() ([][]byte, error) {
+	if len(b.keys) == 0 {
+		return nil, nil
+	}
+
+	raw, err := b.client.rdb.MGet(b.ctx, b.keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([][]byte, len(raw))
+	for i, v := range raw {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		data := []byte(s)
+		if isNegative(data) {
+			continue
+		}
+		if envelopeKind(data) == envelopeChunked {
+			if data, err = b.client.reassemble(b.ctx, b.keys[i], data); err != nil {
+				return nil, err
+			}
+		}
+		vals[i] = data
+	}
+	return vals, nil
+}
+
+// ClusterMRunBuilder provides a fluent API for retrieving many keys from
+// Redis Cluster in a single pipelined round trip per node.
+type ClusterMRunBuilder struct {
+	ctx    context.Context
+	client *ClusterClient
+	keys   []string
+	codec  Codec
+}
+
+// MRun starts a batch retrieval operation. Keys may live in different
+// slots; the GETs are pipelined and grouped by node, so there's no
+// CROSSSLOT error.
+//
+// Example:
+//
+//	var users []User
+//	found, err := cluster.MRun(ctx, "user:1", "user:2").BindSlice(&users)
+func (c *ClusterClient) MRun(ctx context.Context, keys ...string) *ClusterMRunBuilder {
+	return &ClusterMRunBuilder{
+		ctx:    ctx,
+		client: c,
+		keys:   keys,
+	}
+}
+
+// Codec overrides the client codec for this operation.
+func (b *ClusterMRunBuilder) Codec(c Codec) *ClusterMRunBuilder {
+	b.codec = c
+	return b
+}
+
+// BindMap behaves like MRunBuilder.BindMap.
+func (b *ClusterMRunBuilder) BindMap(dest any) ([]string, error) {
+	vals, err := b.fetch()
+	if err != nil {
+		return nil, err
+	}
+	return bindMap(&b.client.enc, b.codec, b.keys, vals, dest)
+}
+
+// BindSlice behaves like MRunBuilder.BindSlice.
+func (b *ClusterMRunBuilder) BindSlice(dest any) ([]string, error) {
+	vals, err := b.fetch()
+	if err != nil {
+		return nil, err
+	}
+	return bindSlice(&b.client.enc, b.codec, b.keys, vals, dest)
+}
+
+// fetch pipelines one GET per key and returns stored bytes aligned with
+// keys, nil for misses.
+func (b *ClusterMRunBuilder) fetch() ([][]byte, error) {
+	if len(b.keys) == 0 {
+		return nil, nil
+	}
+
+	cmds := make([]*redis.StringCmd, len(b.keys))
+	_, err := b.client.rdb.Pipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range b.keys {
+			cmds[i] = pipe.Get(b.ctx, key)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	vals := make([][]byte, len(cmds))
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isNegative(data) {
+			continue
+		}
+		vals[i] = data
+	}
+	return vals, nil
+}
+
+// bindMap decodes the found values into a *map[string]T.
+func bindMap(enc *encoding, codec Codec, keys []string, vals [][]byte, dest any) ([]string, error) {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil, ErrNilPointer
+	}
+	m := rv.Elem()
+	if m.Kind() != reflect.Map || m.Type().Key().Kind() != reflect.String {
+		return nil, fmt.Errorf("gibrun: BindMap needs a pointer to a string-keyed map, got %T", dest)
+	}
+	if m.IsNil() {
+		m.Set(reflect.MakeMapWithSize(m.Type(), len(keys)))
+	}
+
+	found := make([]string, 0, len(keys))
+	for i, data := range vals {
+		if data == nil {
+			continue
+		}
+		elem := reflect.New(m.Type().Elem())
+		if err := enc.unmarshal(data, elem.Interface(), codec); err != nil {
+			return nil, fmt.Errorf("gibrun: decode %s: %w", keys[i], err)
+		}
+		m.SetMapIndex(reflect.ValueOf(keys[i]).Convert(m.Type().Key()), elem.Elem())
+		found = append(found, keys[i])
+	}
+	return found, nil
+}
+
+// bindSlice decodes the found values into a *[]T.
+func bindSlice(enc *encoding, codec Codec, keys []string, vals [][]byte, dest any) ([]string, error) {
+	rv := reflect.ValueOf(dest)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return nil, ErrNilPointer
+	}
+	s := rv.Elem()
+	if s.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("gibrun: BindSlice needs a pointer to a slice, got %T", dest)
+	}
+
+	out := reflect.MakeSlice(s.Type(), 0, len(keys))
+	found := make([]string, 0, len(keys))
+	for i, data := range vals {
+		if data == nil {
+			continue
+		}
+		elem := reflect.New(s.Type().Elem())
+		if err := enc.unmarshal(data, elem.Interface(), codec); err != nil {
+			return nil, fmt.Errorf("gibrun: decode %s: %w", keys[i], err)
+		}
+		out = reflect.Append(out, elem.Elem())
+		found = append(found, keys[i])
+	}
+	s.Set(out)
+	return found, nil
+}
+
+// BatchLoaderFunc loads the values for keys missing from the cache,
+// returning them by key with the TTL to write them back with. Keys absent
+// from the map are treated as not found and are not cached. Values must
+// be assignable to the element type of the BindEach destination.
+type BatchLoaderFunc func(ctx context.Context, keys []string) (map[string]any, time.Duration, error)
+
+// MRunEach is the result of BindEach, see MRunBuilder.BindEach.
+type MRunEach struct {
+	b     *MRunBuilder
+	dest  reflect.Value
+	found map[string]bool
+	err   error
+}
+
+// BindEach decodes every found value into dest, a pointer to a
+// string-keyed map, and records per key whether it was in the cache.
+// Chain LoadMissing to backfill the misses in one call.
+//
+// Example:
+//
+//	users := map[string]User{}
+//	found, err := app.MRun(ctx, keys...).BindEach(&users).LoadMissing(
+//	    func(ctx context.Context, missing []string) (map[string]any, time.Duration, error) {
+//	        return db.UsersByKey(ctx, missing)
+//	    })
+func (b *MRunBuilder) BindEach(dest any) *MRunEach {
+	e := &MRunEach{b: b}
+	keys, err := b.BindMap(dest)
+	if err != nil {
+		e.err = err
+		return e
+	}
+
+	e.dest = reflect.ValueOf(dest).Elem()
+	e.found = make(map[string]bool, len(b.keys))
+	for _, key := range b.keys {
+		e.found[key] = false
+	}
+	for _, key := range keys {
+		e.found[key] = true
+	}
+	return e
+}
+
+// Found reports per key whether it was found in the cache.
+func (e *MRunEach) Found() (map[string]bool, error) {
+	return e.found, e.err
+}
+
+// Missing returns the keys that weren't in the cache, in request order.
+func (e *MRunEach) Missing() []string {
+	var missing []string
+	for _, key := range e.b.keys {
+		if !e.found[key] && !slices.Contains(missing, key) {
+			missing = append(missing, key)
+		}
+	}
+	return missing
+}
+
+// LoadMissing calls loader once with the missing keys, adds the loaded
+// values to the destination and writes them back in one batch. Returns
+// the per-key found map from before loading, so callers see exactly which
+// keys were backfilled. The loader isn't called when nothing is missing.
+func (e *MRunEach) LoadMissing(loader BatchLoaderFunc) (map[string]bool, error) {
+	if e.err != nil {
+		return nil, e.err
+	}
+	missing := e.Missing()
+	if len(missing) == 0 {
+		return e.found, nil
+	}
+
+	loaded, ttl, err := loader(e.b.ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+
+	elemType := e.dest.Type().Elem()
+	batch := e.b.client.MGib(e.b.ctx)
+	for _, key := range missing {
+		v, ok := loaded[key]
+		if !ok || v == nil {
+			continue
+		}
+		rv := reflect.ValueOf(v)
+		if !rv.Type().AssignableTo(elemType) {
+			return nil, fmt.Errorf("gibrun: loader returned %T for %s, want %s", v, key, elemType)
+		}
+		e.dest.SetMapIndex(reflect.ValueOf(key).Convert(e.dest.Type().Key()), rv)
+		batch.Add(key, v, ttl)
+	}
+	if err := batch.Exec(); err != nil {
+		return nil, err
+	}
+	return e.found, nil
+}
@@ -0,0 +1,133 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ScanSource is one client taking part in a multi-source scan.
+type ScanSource struct {
+	// Name attributes keys to this source in results, e.g. "shard-3".
+	Name string
+
+	// Target is a *Client or *ClusterClient.
+	Target Target
+}
+
+// SourcedKey is a scanned key with the source it came from.
+type SourcedKey struct {
+	Source string
+	Key    string
+	// Value is set when ScanOptions.Where is used.
+	Value []byte
+}
+
+// MultiScanner scans many clients or logical DBs in parallel.
+type MultiScanner struct {
+	ctx     context.Context
+	sources []ScanSource
+	opts    ScanOptions
+}
+
+// BlusukanMany scans several clients or logical DBs in parallel - one
+// goroutine per source - and merges the keys with source attribution,
+// for fleets running many small Redis instances. The same ScanOptions
+// apply to every source, including Type and Where.
+//
+// Example:
+//
+//	scan := gibrun.BlusukanMany(ctx, []gibrun.ScanSource{
+//	    {Name: "tenant-a", Target: tenantA},
+//	    {Name: "tenant-b", Target: tenantB},
+//	}, gibrun.ScanOptions{Pattern: "session:*"})
+//	err := scan.Each(func(k gibrun.SourcedKey) bool {
+//	    fmt.Println(k.Source, k.Key)
+//	    return true
+//	})
+func BlusukanMany(ctx context.Context, sources []ScanSource, opts ScanOptions) *MultiScanner {
+	return &MultiScanner{ctx: ctx, sources: sources, opts: opts}
+}
+
+// Each calls fn for every matching key across all sources. Calls are
+// serialized, so fn needs no locking; order across sources is not
+// defined. Return false to stop all scans early. The first error stops
+// the scan and names the failing source.
+func (s *MultiScanner) Each(fn func(SourcedKey) bool) error {
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		stopped  bool
+		firstErr error
+	)
+
+	for _, src := range s.sources {
+		wg.Add(1)
+		go func(src ScanSource) {
+			defer wg.Done()
+			enc := src.Target.encoder()
+			rdb := src.Target.cmdable()
+
+			err := src.Target.scanBatches(ctx, s.opts, func(keys []string) error {
+				var values [][]byte
+				if s.opts.Where != nil {
+					var err error
+					if keys, values, err = filterWhere(ctx, rdb, enc, keys, s.opts.Where); err != nil {
+						return err
+					}
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+				for i, key := range keys {
+					if stopped {
+						return context.Canceled
+					}
+					k := SourcedKey{Source: src.Name, Key: key}
+					if values != nil {
+						k.Value = values[i]
+					}
+					if !fn(k) {
+						stopped = true
+						cancel()
+					}
+				}
+				return nil
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && !stopped && firstErr == nil {
+				firstErr = fmt.Errorf("gibrun: scan %s: %w", src.Name, err)
+				cancel()
+			}
+		}(src)
+	}
+
+	wg.Wait()
+	return firstErr
+}
+
+// All collects matching keys from every source.
+// Use with caution on large fleets - prefer Each.
+func (s *MultiScanner) All() ([]SourcedKey, error) {
+	var keys []SourcedKey
+	err := s.Each(func(k SourcedKey) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys, err
+}
+
+// Count returns the number of matching keys per source.
+func (s *MultiScanner) Count() (map[string]int, error) {
+	counts := make(map[string]int, len(s.sources))
+	err := s.Each(func(k SourcedKey) bool {
+		counts[k.Source]++
+		return true
+	})
+	return counts, err
+}
@@ -0,0 +1,59 @@
+package gibrun
+
+import (
+	"errors"
+	"time"
+)
+
+// negativeMarker is the stored sentinel for a confirmed miss.
+var negativeMarker = append(append([]byte{}, envelopeMagic...), envelopeNegative)
+
+// NotFound stores a negative-cache marker instead of a value, recording
+// that the entity is known not to exist. Run treats the marker as a
+// miss, and OrElse loaders are not called while it lives. Set a short
+// TTL so entities created later become visible.
+//
+// Example:
+//
+//	// user was deleted
+//	err := app.Gib(ctx, "user:123").NotFound().TTL(30 * time.Second).Exec()
+func (b *GibBuilder) NotFound() *GibBuilder {
+	b.value = negativeMarker
+	b.negative = true
+	return b
+}
+
+// WithNegativeCache caches confirmed misses from the OrElse or
+// StaleWhileRevalidate loader for ttl: when the loader returns a nil
+// value or ErrNotFound, a marker is stored so repeated lookups for
+// nonexistent entities (404s, deleted users) don't hit the database on
+// every request.
+//
+// Example:
+//
+//	found, err := app.Run(ctx, "user:404").
+//	    OrElse(loadUser).
+//	    WithNegativeCache(30 * time.Second).
+//	    Bind(&user)
+func (b *RunBuilder) WithNegativeCache(ttl time.Duration) *RunBuilder {
+	b.negativeTTL = ttl
+	return b
+}
+
+// storeNegative writes the negative marker after a confirmed miss.
+func (b *RunBuilder) storeNegative() {
+	if b.negativeTTL <= 0 {
+		return
+	}
+	_ = b.primary().Gib(b.ctx, b.key).NotFound().TTL(b.negativeTTL).Exec()
+}
+
+// isNegative reports whether stored data is the negative marker.
+func isNegative(data []byte) bool {
+	return envelopeKind(data) == envelopeNegative
+}
+
+// isNotFound reports whether a loader signalled a confirmed miss.
+func isNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
@@ -0,0 +1,421 @@
+package gibrun
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// outboxTopicField is the entry field holding the event topic; the payload
+// goes in streamField like Stream().Add.
+const outboxTopicField = "topic"
+
+// OutboxConfig configures an Outbox.
+type OutboxConfig struct {
+	// Stream is the Redis stream holding pending events. Required.
+	// In a cluster, wrap it in a hash tag (e.g. "{outbox:orders}") so the
+	// relay's bookkeeping keys share its slot.
+	Stream string
+
+	// BatchSize is how many events the relay reads at once. Default is 100.
+	BatchSize int64
+
+	// PollInterval is how long the relay waits when there is nothing to
+	// deliver, or while another relay holds the lease. Default is 1 second.
+	PollInterval time.Duration
+
+	// Lease is how long a relay stays the only active one without
+	// checking in. It is renewed while the publisher runs, so a slow
+	// publish doesn't hand the outbox to a standby; a crashed relay is
+	// replaced once it runs out. Default is 30 seconds; values under 3ms
+	// are rejected.
+	Lease time.Duration
+
+	// MaxAttempts is how many times an event is offered to the publisher
+	// before it is recorded in the client FailureLog (source
+	// "outbox:<stream>") and skipped. Default is 10.
+	MaxAttempts int
+
+	// RetryDelay is the wait before the second attempt, doubling up to a
+	// minute after that. Default is 1 second.
+	RetryDelay time.Duration
+
+	// Codec overrides the client codec for event payloads.
+	Codec Codec
+}
+
+// Outbox is a transactional outbox on a Redis stream. Requests append
+// events next to their own writes, and Relay delivers them in order to a
+// publisher such as a message broker.
+type Outbox struct {
+	client *Client
+	cfg    OutboxConfig
+	// err holds configuration problems found at construction.
+	err error
+}
+
+// OutboxEvent is an event handed to an OutboxPublisher.
+type OutboxEvent struct {
+	// ID is the stream entry ID. It is stable across redeliveries, so
+	// consumers can use it to drop duplicates.
+	ID    string
+	Topic string
+	// Data is the stored payload; use Bind to decode it.
+	Data []byte
+
+	enc   *encoding
+	codec Codec
+}
+
+// Bind unmarshals the payload into dest.
+func (e OutboxEvent) Bind(dest any) error {
+	if dest == nil {
+		return ErrNilPointer
+	}
+	return e.enc.unmarshal(e.Data, dest, e.codec)
+}
+
+// OutboxPublisher delivers one event. Returning nil marks it delivered;
+// an error retries it, holding back the events after it.
+type OutboxPublisher func(ctx context.Context, event OutboxEvent) error
+
+// NewOutbox creates an outbox. An invalid configuration (e.g. a missing
+// Stream) is reported by every call; check it up front with Err.
+//
+// Example:
+//
+//	outbox := gibrun.NewOutbox(app, gibrun.OutboxConfig{Stream: "{outbox:orders}"})
+//
+//	// in the request, atomically with the order itself
+//	err := app.Tx(ctx).Run(func(tx *gibrun.TxOps) error {
+//	    tx.Gib("order:42", order, 0)
+//	    tx.Outbox(outbox, "order.placed", OrderPlaced{ID: 42})
+//	    return nil
+//	})
+//
+//	// in a background process, until ctx is canceled
+//	err = outbox.Relay(ctx, func(ctx context.Context, ev gibrun.OutboxEvent) error {
+//	    return broker.Publish(ctx, ev.Topic, ev.ID, ev.Data)
+//	})
+func NewOutbox(client *Client, cfg OutboxConfig) *Outbox {
+	err := cfg.Validate()
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.PollInterval == 0 {
+		cfg.PollInterval = time.Second
+	}
+	if cfg.Lease == 0 {
+		cfg.Lease = 30 * time.Second
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.RetryDelay == 0 {
+		cfg.RetryDelay = time.Second
+	}
+	return &Outbox{client: client, cfg: cfg, err: err}
+}
+
+// Err returns the configuration error found at construction, if any.
+func (o *Outbox) Err() error {
+	return o.err
+}
+
+// Append adds an event on its own. Use TxOps.Outbox to add it in the
+// same transaction as the writes it describes.
+func (o *Outbox) Append(ctx context.Context, topic string, payload any) (string, error) {
+	args, err := o.addArgs(topic, payload)
+	if err != nil {
+		return "", err
+	}
+	return o.client.rdb.XAdd(ctx, args).Result()
+}
+
+// Outbox queues appending an event to outbox, committed or discarded
+// together with the other writes of the transaction.
+func (t *TxOps) Outbox(o *Outbox, topic string, payload any) *TxOps {
+	args, err := o.addArgs(topic, payload)
+	if err != nil {
+		t.err = err
+		return t
+	}
+	t.writes = append(t.writes, func(pipe redis.Pipeliner) {
+		pipe.XAdd(t.ctx, args)
+	})
+	return t
+}
+
+// addArgs builds the XADD for an event.
+func (o *Outbox) addArgs(topic string, payload any) (*redis.XAddArgs, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	if payload == nil {
+		return nil, ErrNilValue
+	}
+	data, err := o.client.enc.marshal(payload, o.cfg.Codec)
+	if err != nil {
+		return nil, err
+	}
+	return &redis.XAddArgs{
+		Stream: o.cfg.Stream,
+		Values: []any{outboxTopicField, topic, streamField, data},
+	}, nil
+}
+
+// Len returns the number of events not yet delivered.
+func (o *Outbox) Len(ctx context.Context) (int64, error) {
+	if o.err != nil {
+		return 0, o.err
+	}
+	return o.client.rdb.XLen(ctx, o.cfg.Stream).Result()
+}
+
+// Checkpoint returns the ID of the last delivered event, or "" if none
+// was delivered yet.
+func (o *Outbox) Checkpoint(ctx context.Context) (string, error) {
+	if o.err != nil {
+		return "", o.err
+	}
+	id, err := o.client.rdb.Get(ctx, o.checkpointKey()).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return id, err
+}
+
+// outboxCommitScript records a delivered event, only while the caller
+// still holds the relay lease, and renews the lease.
+//
+// KEYS[1] = lease, KEYS[2] = checkpoint, KEYS[3] = stream
+// ARGV[1] = relay token, ARGV[2] = event ID ("" to only renew), ARGV[3] = lease in milliseconds
+var outboxCommitScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) ~= ARGV[1] then
+  return 0
+end
+if ARGV[2] ~= '' then
+  redis.call('SET', KEYS[2], ARGV[2])
+  redis.call('XDEL', KEYS[3], ARGV[2])
+end
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return 1
+`)
+
+// Relay delivers events to publisher in stream order until ctx is
+// canceled, then returns nil. Only one relay per outbox is active at a
+// time; others wait for its lease, so extra relays can run as standbys.
+//
+// Each delivered event is checkpointed and removed from the stream in
+// one atomic step. A relay that lost its lease can't move the
+// checkpoint, so an event is only published twice when a relay stops
+// between publishing and checkpointing. Transient Redis errors are
+// retried after a second; other errors are returned.
+func (o *Outbox) Relay(ctx context.Context, publisher OutboxPublisher) error {
+	if o.err != nil {
+		return o.err
+	}
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+	r := &outboxRelay{o: o, token: hex.EncodeToString(raw), publish: publisher}
+
+	for ctx.Err() == nil {
+		busy, err := r.step(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			if !isTransient(err) {
+				return err
+			}
+			sleepCtx(ctx, o.client.clock, time.Second)
+			continue
+		}
+		if !busy {
+			sleepCtx(ctx, o.client.clock, o.cfg.PollInterval)
+		}
+	}
+
+	// Let a standby take over right away
+	if r.leader {
+		r.commit(context.WithoutCancel(ctx), "", time.Millisecond)
+	}
+	return nil
+}
+
+// outboxRelay is the state of one Relay call.
+type outboxRelay struct {
+	o       *Outbox
+	token   string
+	publish OutboxPublisher
+	leader  bool
+}
+
+// step delivers one batch. Reports whether there may be more to do.
+func (r *outboxRelay) step(ctx context.Context) (bool, error) {
+	o := r.o
+	if !r.leader {
+		ok, err := o.client.rdb.SetNX(ctx, o.leaseKey(), r.token, o.cfg.Lease).Result()
+		if err != nil || !ok {
+			return false, err
+		}
+		r.leader = true
+	}
+
+	msgs, err := o.client.rdb.XRangeN(ctx, o.cfg.Stream, "-", "+", o.cfg.BatchSize).Result()
+	if err != nil {
+		return false, err
+	}
+	if len(msgs) == 0 {
+		return false, r.commit(ctx, "", o.cfg.Lease)
+	}
+
+	for _, m := range msgs {
+		if ctx.Err() != nil || !r.leader {
+			return false, nil
+		}
+		if err := r.deliver(ctx, r.event(m)); err != nil {
+			return false, err
+		}
+	}
+	return r.leader, nil
+}
+
+// deliver publishes ev with retries and checkpoints it.
+func (r *outboxRelay) deliver(ctx context.Context, ev OutboxEvent) error {
+	o := r.o
+	delay := o.cfg.RetryDelay
+	for attempt := 1; ; attempt++ {
+		err := errStreamNoData
+		if ev.Data != nil {
+			stop := r.heartbeat(ctx)
+			err = r.publish(ctx, ev)
+			stop()
+		}
+		if err == nil {
+			break
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if attempt >= o.cfg.MaxAttempts {
+			err = fmt.Errorf("after %d attempts: %w", attempt, err)
+			o.client.Failures().Record(context.WithoutCancel(ctx), "outbox:"+o.cfg.Stream, ev.ID, ev.Data, err)
+			break
+		}
+
+		// Keep the lease while waiting out the publisher
+		if err := r.commit(ctx, "", o.cfg.Lease); err != nil || !r.leader {
+			return err
+		}
+		sleepCtx(ctx, o.client.clock, delay)
+		if ctx.Err() != nil {
+			return nil
+		}
+		delay = min(delay*2, time.Minute)
+	}
+	return r.commit(context.WithoutCancel(ctx), ev.ID, o.cfg.Lease)
+}
+
+// heartbeat renews the lease while the publisher runs, paced on wall
+// time like the Kerja heartbeat. The returned func stops it and notes a
+// lease lost meanwhile.
+func (r *outboxRelay) heartbeat(ctx context.Context) func() {
+	// A copy, so the beats don't race the relay's own leader flag
+	beat := &outboxRelay{o: r.o, token: r.token, leader: true}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(r.o.cfg.Lease / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				// Transient errors are retried on the next beat
+				if err := beat.commit(ctx, "", r.o.cfg.Lease); err == nil && !beat.leader {
+					return
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+		if !beat.leader {
+			r.leader = false
+		}
+	}
+}
+
+// commit checkpoints id (or only renews the lease for ""), sets the lease
+// to expire after lease and notes whether it is still held.
+func (r *outboxRelay) commit(ctx context.Context, id string, lease time.Duration) error {
+	o := r.o
+	if !o.client.scripting(ctx) {
+		return r.commitFallback(ctx, id, lease)
+	}
+	keys := []string{o.leaseKey(), o.checkpointKey(), o.cfg.Stream}
+	n, err := outboxCommitScript.Run(ctx, o.client.rdb, keys, r.token, id, lease.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+	r.leader = n == 1
+	return nil
+}
+
+// commitFallback mirrors outboxCommitScript with WATCH/MULTI.
+func (r *outboxRelay) commitFallback(ctx context.Context, id string, lease time.Duration) error {
+	o := r.o
+	return o.client.watchRetry(ctx, func(tx *redis.Tx) error {
+		token, err := tx.Get(ctx, o.leaseKey()).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		r.leader = token == r.token
+		if !r.leader {
+			return nil
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if id != "" {
+				pipe.Set(ctx, o.checkpointKey(), id, 0)
+				pipe.XDel(ctx, o.cfg.Stream, id)
+			}
+			pipe.PExpire(ctx, o.leaseKey(), lease)
+			return nil
+		})
+		return err
+	}, o.leaseKey())
+}
+
+// event converts an entry into an OutboxEvent.
+func (r *outboxRelay) event(m redis.XMessage) OutboxEvent {
+	ev := OutboxEvent{
+		ID:    m.ID,
+		enc:   &r.o.client.enc,
+		codec: r.o.cfg.Codec,
+	}
+	ev.Topic, _ = m.Values[outboxTopicField].(string)
+	if s, ok := m.Values[streamField].(string); ok {
+		ev.Data = []byte(s)
+	}
+	return ev
+}
+
+// leaseKey holds the token of the active relay.
+func (o *Outbox) leaseKey() string {
+	return o.cfg.Stream + ":relay"
+}
+
+// checkpointKey holds the ID of the last delivered event.
+func (o *Outbox) checkpointKey() string {
+	return o.cfg.Stream + ":checkpoint"
+}
@@ -0,0 +1,186 @@
+package gibrun
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Pipeline queues mixed operations and sends them in a single round trip.
+// Unlike Atomic, commands are not isolated from other clients; use it to
+// cut latency in request handlers that touch many keys.
+type Pipeline struct {
+	ctx    context.Context
+	client *Client
+	ops    []*pipelineOp
+}
+
+// pipelineOp is a single queued command.
+type pipelineOp struct {
+	op    string
+	keys  []string
+	value any
+	ttl   time.Duration
+	n     int64
+}
+
+// PipelineResult is the outcome of one queued command, in queue order.
+type PipelineResult struct {
+	// Op is the operation name, e.g. "gib", "incrby", "del".
+	Op  string
+	Key string
+
+	// Int is the integer reply for counters and Del, 0 otherwise.
+	Int int64
+
+	Err error
+}
+
+// PipelineGib configures a queued write. It embeds the Pipeline so more
+// operations can be chained after it.
+type PipelineGib struct {
+	*Pipeline
+	op *pipelineOp
+}
+
+// PipelineSprint queues a counter operation.
+type PipelineSprint struct {
+	p   *Pipeline
+	key string
+}
+
+// Pipeline starts a pipelined batch.
+//
+// Example:
+//
+//	results, err := app.Pipeline(ctx).
+//	    Gib("user:123").Value(user).TTL(time.Hour).
+//	    Sprint("stats:user-updates").Incr().
+//	    Del("user:123:profile-page").
+//	    Exec()
+func (c *Client) Pipeline(ctx context.Context) *Pipeline {
+	return &Pipeline{
+		ctx:    ctx,
+		client: c,
+	}
+}
+
+// Gib queues a write; set the value with Value and optionally TTL.
+func (p *Pipeline) Gib(key string) *PipelineGib {
+	op := &pipelineOp{op: "gib", keys: []string{key}}
+	p.ops = append(p.ops, op)
+	return &PipelineGib{Pipeline: p, op: op}
+}
+
+// Value sets the data to be stored, marshalled exactly like Gib.
+func (g *PipelineGib) Value(v any) *PipelineGib {
+	g.op.value = v
+	return g
+}
+
+// TTL sets the time-to-live for the queued write.
+func (g *PipelineGib) TTL(d time.Duration) *PipelineGib {
+	g.op.ttl = d
+	return g
+}
+
+// Sprint queues a counter operation on key.
+func (p *Pipeline) Sprint(key string) *PipelineSprint {
+	return &PipelineSprint{p: p, key: key}
+}
+
+// Incr queues incrementing the counter by 1.
+func (s *PipelineSprint) Incr() *Pipeline {
+	return s.IncrBy(1)
+}
+
+// IncrBy queues incrementing the counter by n.
+func (s *PipelineSprint) IncrBy(n int64) *Pipeline {
+	s.p.ops = append(s.p.ops, &pipelineOp{op: "incrby", keys: []string{s.key}, n: n})
+	return s.p
+}
+
+// Decr queues decrementing the counter by 1.
+func (s *PipelineSprint) Decr() *Pipeline {
+	return s.IncrBy(-1)
+}
+
+// Expire queues setting the counter TTL.
+func (s *PipelineSprint) Expire(ttl time.Duration) *Pipeline {
+	s.p.ops = append(s.p.ops, &pipelineOp{op: "expire", keys: []string{s.key}, ttl: ttl})
+	return s.p
+}
+
+// Del queues deleting keys.
+func (p *Pipeline) Del(keys ...string) *Pipeline {
+	p.ops = append(p.ops, &pipelineOp{op: "del", keys: keys})
+	return p
+}
+
+// Len returns the number of queued operations.
+func (p *Pipeline) Len() int {
+	return len(p.ops)
+}
+
+// Exec sends all queued operations in one round trip and returns one
+// result per operation. Values are marshalled and validated up front, so
+// a bad value aborts the batch before anything is sent. The returned
+// error is the first command error, if any.
+func (p *Pipeline) Exec() ([]PipelineResult, error) {
+	data := make([][]byte, len(p.ops))
+	for i, op := range p.ops {
+		if op.op != "gib" {
+			continue
+		}
+		if op.value == nil {
+			return nil, ErrNilValue
+		}
+		if err := p.client.validate(op.keys[0], op.value); err != nil {
+			return nil, err
+		}
+		if err := p.client.guardWrite(op.keys[0]); err != nil {
+			return nil, err
+		}
+		d, err := p.client.enc.marshal(op.value, nil)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = d
+		op.ttl = p.client.ttl.apply(op.keys[0], op.ttl)
+	}
+
+	cmds := make([]redis.Cmder, len(p.ops))
+	_, err := p.client.rdb.Pipelined(p.ctx, func(pipe redis.Pipeliner) error {
+		for i, op := range p.ops {
+			switch op.op {
+			case "gib":
+				cmds[i] = pipe.Set(p.ctx, op.keys[0], data[i], op.ttl)
+			case "incrby":
+				cmds[i] = pipe.IncrBy(p.ctx, op.keys[0], op.n)
+			case "expire":
+				cmds[i] = pipe.PExpire(p.ctx, op.keys[0], op.ttl)
+			case "del":
+				cmds[i] = pipe.Del(p.ctx, op.keys...)
+			}
+		}
+		return nil
+	})
+
+	results := make([]PipelineResult, len(p.ops))
+	for i, op := range p.ops {
+		res := PipelineResult{Op: op.op, Err: cmds[i].Err()}
+		if len(op.keys) > 0 {
+			res.Key = op.keys[0]
+		}
+		if c, ok := cmds[i].(*redis.IntCmd); ok {
+			res.Int = c.Val()
+		}
+		results[i] = res
+
+		if op.op == "gib" && res.Err == nil {
+			p.client.mirrorWrite(op.keys[0], data[i], op.ttl)
+		}
+	}
+	return results, err
+}
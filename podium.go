@@ -0,0 +1,263 @@
+package gibrun
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PodiumBuilder provides a fluent API for leaderboards on Redis sorted
+// sets. Ranks are 0-based and, by default, the highest score comes first;
+// use Ascending for lowest-first boards such as race times. Members are
+// encoded like Koalisi members.
+type PodiumBuilder struct {
+	ctx       context.Context
+	client    *Client
+	key       string
+	codec     Codec
+	ascending bool
+
+	// stableTies packs the time a score was reached into the stored
+	// score, see StableTies.
+	stableTies bool
+}
+
+// PodiumEntry is one ranked member.
+type PodiumEntry struct {
+	// Member is the member as stored; use Bind to decode it.
+	Member string
+	Score  float64
+	// Rank is the 0-based position in the board order.
+	Rank int64
+
+	codec Codec
+}
+
+// Bind decodes the member into dest.
+func (e PodiumEntry) Bind(dest any) error {
+	return bindMember(e.Member, dest, e.codec)
+}
+
+// PodiumRange is a slice of the board waiting to be read, see Top,
+// Around and RangeByScore.
+type PodiumRange struct {
+	b     *PodiumBuilder
+	fetch func() ([]redis.Z, int64, error)
+}
+
+// Podium starts a leaderboard operation.
+//
+// Example:
+//
+//	board := app.Podium(ctx, "leaderboard:weekly")
+//	board.AddScore("alice", 320)
+//	board.IncrScore("bob", 15)
+//
+//	top, err := board.Top(10).Entries()
+//	for _, e := range top {
+//	    fmt.Printf("#%d %s %.0f\n", e.Rank+1, e.Member, e.Score)
+//	}
+//	rank, found, err := board.Rank("alice")
+func (c *Client) Podium(ctx context.Context, key string) *PodiumBuilder {
+	return &PodiumBuilder{
+		ctx:    ctx,
+		client: c,
+		key:    key,
+	}
+}
+
+// Codec overrides the client codec for non-scalar members.
+func (b *PodiumBuilder) Codec(c Codec) *PodiumBuilder {
+	b.codec = c
+	return b
+}
+
+// Ascending orders the board lowest score first.
+func (b *PodiumBuilder) Ascending() *PodiumBuilder {
+	b.ascending = true
+	return b
+}
+
+// AddScore sets the score of member, adding it if needed.
+func (b *PodiumBuilder) AddScore(member any, score float64) error {
+	m, err := b.client.enc.member(member, b.codec)
+	if err != nil {
+		return err
+	}
+	if b.stableTies {
+		score = math.Floor(score) + b.tieFraction()
+	}
+	return b.client.rdb.ZAdd(b.ctx, b.key, redis.Z{Score: score, Member: m}).Err()
+}
+
+// IncrScore adds delta to the score of member and returns the new score.
+func (b *PodiumBuilder) IncrScore(member any, delta float64) (float64, error) {
+	m, err := b.client.enc.member(member, b.codec)
+	if err != nil {
+		return 0, err
+	}
+	if b.stableTies {
+		return b.incrStable(m, math.Floor(delta))
+	}
+	return b.client.rdb.ZIncrBy(b.ctx, b.key, delta, m).Result()
+}
+
+// Remove removes members from the board.
+func (b *PodiumBuilder) Remove(members ...any) (int64, error) {
+	vals, err := b.client.enc.members(members, b.codec)
+	if err != nil {
+		return 0, err
+	}
+	return b.client.rdb.ZRem(b.ctx, b.key, vals...).Result()
+}
+
+// Score returns the score of member.
+// Returns (0, false, nil) if the member isn't on the board.
+func (b *PodiumBuilder) Score(member any) (float64, bool, error) {
+	m, err := b.client.enc.member(member, b.codec)
+	if err != nil {
+		return 0, false, err
+	}
+	score, err := b.client.rdb.ZScore(b.ctx, b.key, m).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return b.visible(score), true, nil
+}
+
+// Rank returns the 0-based position of member in the board order.
+// Returns (0, false, nil) if the member isn't on the board.
+func (b *PodiumBuilder) Rank(member any) (int64, bool, error) {
+	m, err := b.client.enc.member(member, b.codec)
+	if err != nil {
+		return 0, false, err
+	}
+	return b.rank(m)
+}
+
+// Len returns the number of members on the board.
+func (b *PodiumBuilder) Len() (int64, error) {
+	return b.client.rdb.ZCard(b.ctx, b.key).Result()
+}
+
+// Top selects the first n members in board order.
+func (b *PodiumBuilder) Top(n int64) *PodiumRange {
+	return &PodiumRange{b: b, fetch: func() ([]redis.Z, int64, error) {
+		if n <= 0 {
+			return nil, 0, nil
+		}
+		zs, err := b.byRank(0, n-1)
+		return zs, 0, err
+	}}
+}
+
+// Around selects member with up to k neighbours on each side, for "your
+// position" views. Selects nothing if the member isn't on the board.
+func (b *PodiumBuilder) Around(member any, k int64) *PodiumRange {
+	return &PodiumRange{b: b, fetch: func() ([]redis.Z, int64, error) {
+		m, err := b.client.enc.member(member, b.codec)
+		if err != nil {
+			return nil, 0, err
+		}
+		rank, found, err := b.rank(m)
+		if err != nil || !found {
+			return nil, 0, err
+		}
+		start := max(rank-k, 0)
+		zs, err := b.byRank(start, rank+k)
+		return zs, start, err
+	}}
+}
+
+// RangeByScore selects members scoring between min and max inclusive,
+// in board order.
+func (b *PodiumBuilder) RangeByScore(min, max float64) *PodiumRange {
+	return &PodiumRange{b: b, fetch: func() ([]redis.Z, int64, error) {
+		by := &redis.ZRangeBy{Min: formatScore(min), Max: formatScore(max)}
+		if b.stableTies {
+			// Stored scores carry a fraction in [0, 1) past the integer score
+			by.Min, by.Max = formatScore(math.Ceil(min)), "("+formatScore(math.Floor(max)+1)
+		}
+		var zs []redis.Z
+		var err error
+		if b.ascending {
+			zs, err = b.client.rdb.ZRangeByScoreWithScores(b.ctx, b.key, by).Result()
+		} else {
+			zs, err = b.client.rdb.ZRevRangeByScoreWithScores(b.ctx, b.key, by).Result()
+		}
+		if err != nil || len(zs) == 0 {
+			return nil, 0, err
+		}
+		start, _, err := b.rank(zs[0].Member.(string))
+		return zs, start, err
+	}}
+}
+
+// Entries returns the selected members with their scores and ranks.
+func (r *PodiumRange) Entries() ([]PodiumEntry, error) {
+	zs, start, err := r.fetch()
+	if err != nil {
+		return nil, err
+	}
+	codec := r.b.client.enc.codecFor(r.b.codec)
+	entries := make([]PodiumEntry, len(zs))
+	for i, z := range zs {
+		entries[i] = PodiumEntry{
+			Member: z.Member.(string),
+			Score:  r.b.visible(z.Score),
+			Rank:   start + int64(i),
+			codec:  codec,
+		}
+	}
+	return entries, nil
+}
+
+// Bind decodes the selected members, in board order, into dest, a
+// pointer to a slice.
+func (r *PodiumRange) Bind(dest any) error {
+	zs, _, err := r.fetch()
+	if err != nil {
+		return err
+	}
+	members := make([]string, len(zs))
+	for i, z := range zs {
+		members[i] = z.Member.(string)
+	}
+	return bindMembers(members, dest, r.b.client.enc.codecFor(r.b.codec))
+}
+
+// rank returns the position of an encoded member.
+func (b *PodiumBuilder) rank(m string) (int64, bool, error) {
+	var rank int64
+	var err error
+	if b.ascending {
+		rank, err = b.client.rdb.ZRank(b.ctx, b.key, m).Result()
+	} else {
+		rank, err = b.client.rdb.ZRevRank(b.ctx, b.key, m).Result()
+	}
+	if err != nil {
+		if err == redis.Nil {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return rank, true, nil
+}
+
+// byRank returns members from start to stop in board order.
+func (b *PodiumBuilder) byRank(start, stop int64) ([]redis.Z, error) {
+	if b.ascending {
+		return b.client.rdb.ZRangeWithScores(b.ctx, b.key, start, stop).Result()
+	}
+	return b.client.rdb.ZRevRangeWithScores(b.ctx, b.key, start, stop).Result()
+}
+
+// formatScore renders a score bound for ZRANGEBYSCORE.
+func formatScore(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
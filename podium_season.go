@@ -0,0 +1,168 @@
+package gibrun
+
+import (
+	"math"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tieScale is the width of the tie-breaking fraction, in seconds.
+const tieScale = 1 << 32
+
+// StableTies breaks score ties by who got there first instead of by
+// member name. Scores are kept as integers and the time of the last
+// update is packed into the fraction of the stored score, so ZRANGE order
+// stays correct without a second lookup. Score, Entries and RangeByScore
+// report the integer score; IncrScore drops fractional deltas.
+//
+// Every write to the board must go through a StableTies builder. Ties
+// resolve to the second for scores up to about one million; larger scores
+// still sort correctly but ties resolve more coarsely.
+//
+// Example:
+//
+//	board := app.Podium(ctx, "leaderboard:season:3").StableTies()
+//	board.IncrScore("alice", 50) // alice reaches 50 first
+//	board.IncrScore("bob", 50)   // bob ranks below alice
+func (b *PodiumBuilder) StableTies() *PodiumBuilder {
+	b.stableTies = true
+	return b
+}
+
+// tieFraction encodes the current time in [0, 1) so that earlier updates
+// come first in board order.
+func (b *PodiumBuilder) tieFraction() float64 {
+	sec := float64(b.client.clock.Now().Unix())
+	if b.ascending {
+		return sec / tieScale
+	}
+	return (tieScale - 1 - sec) / tieScale
+}
+
+// visible strips the tie-breaking fraction from a stored score.
+func (b *PodiumBuilder) visible(score float64) float64 {
+	if b.stableTies {
+		return math.Floor(score)
+	}
+	return score
+}
+
+// stableIncrScript adds to the integer part of a member's score and
+// replaces the fraction. Returns the new integer score as a string, since
+// Lua numbers come back truncated.
+//
+// KEYS[1] = board
+// ARGV[1] = member, ARGV[2] = delta, ARGV[3] = fraction
+var stableIncrScript = redis.NewScript(`
+local cur = tonumber(redis.call('ZSCORE', KEYS[1], ARGV[1]) or '0')
+local v = math.floor(cur) + tonumber(ARGV[2])
+redis.call('ZADD', KEYS[1], v + tonumber(ARGV[3]), ARGV[1])
+return tostring(v)
+`)
+
+// incrStable runs IncrScore for StableTies boards.
+func (b *PodiumBuilder) incrStable(m string, delta float64) (float64, error) {
+	frac := b.tieFraction()
+	if !b.client.scripting(b.ctx) {
+		return b.incrStableFallback(m, delta, frac)
+	}
+	return stableIncrScript.Run(b.ctx, b.client.rdb, []string{b.key}, m, delta, frac).Float64()
+}
+
+// incrStableFallback mirrors stableIncrScript with WATCH/MULTI.
+func (b *PodiumBuilder) incrStableFallback(m string, delta, frac float64) (float64, error) {
+	var val float64
+	err := b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		cur, err := tx.ZScore(b.ctx, b.key, m).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		val = math.Floor(cur) + delta
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZAdd(b.ctx, b.key, redis.Z{Score: val + frac, Member: m})
+			return nil
+		})
+		return err
+	}, b.key)
+	return val, err
+}
+
+// Page selects one page of the board, 1-based, with size members per
+// page. Entries carry their absolute ranks.
+//
+// Example:
+//
+//	entries, err := app.Podium(ctx, "leaderboard:weekly").Page(2, 25).Entries()
+//	// ranks 25 to 49
+func (b *PodiumBuilder) Page(page, size int64) *PodiumRange {
+	return &PodiumRange{b: b, fetch: func() ([]redis.Z, int64, error) {
+		if page < 1 || size <= 0 {
+			return nil, 0, nil
+		}
+		start := (page - 1) * size
+		zs, err := b.byRank(start, start+size-1)
+		return zs, start, err
+	}}
+}
+
+// PercentileRank returns the share of the other members that member
+// ranks ahead of, from 0 (last) to 100 (first). A member alone on the
+// board is at 100. Returns (0, false, nil) if the member isn't on the board.
+//
+// Example:
+//
+//	pct, found, err := board.PercentileRank("alice")
+//	// 90 means ahead of 90% of the other players
+func (b *PodiumBuilder) PercentileRank(member any) (float64, bool, error) {
+	m, err := b.client.enc.member(member, b.codec)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var rank, card *redis.IntCmd
+	_, err = b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		if b.ascending {
+			rank = pipe.ZRank(b.ctx, b.key, m)
+		} else {
+			rank = pipe.ZRevRank(b.ctx, b.key, m)
+		}
+		card = pipe.ZCard(b.ctx, b.key)
+		return nil
+	})
+	if err != nil {
+		if err == redis.Nil {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	n := card.Val()
+	if n <= 1 {
+		return 100, true, nil
+	}
+	return float64(n-1-rank.Val()) / float64(n-1) * 100, true, nil
+}
+
+// ArchiveTo ends a season by renaming the board to archiveKey in one
+// atomic step, so the next write starts a fresh board under the same key.
+// Returns (false, nil) if the board is empty, and ErrArchiveExists rather
+// than overwrite an existing archive. In Redis Cluster both keys must hash
+// to the same slot, e.g. "{lb}:season:3" and "{lb}:current".
+//
+// Example:
+//
+//	archived, err := app.Podium(ctx, "leaderboard:current").ArchiveTo("leaderboard:season:3")
+func (b *PodiumBuilder) ArchiveTo(archiveKey string) (bool, error) {
+	renamed, err := b.client.rdb.RenameNX(b.ctx, b.key, archiveKey).Result()
+	if err != nil {
+		if strings.Contains(err.Error(), "no such key") {
+			return false, nil
+		}
+		return false, err
+	}
+	if !renamed {
+		return false, ErrArchiveExists
+	}
+	return true, nil
+}
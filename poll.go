@@ -0,0 +1,177 @@
+package gibrun
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// voteScript records one vote per voter while the poll is open.
+//
+// KEYS[1] = meta hash, KEYS[2] = voters hash, KEYS[3] = tally hash
+// ARGV[1] = voter, ARGV[2] = option, ARGV[3] = now in milliseconds
+// Returns 1 voted, 0 already voted, -1 closed, -2 not open, -3 unknown option.
+var voteScript = redis.NewScript(`
+local deadline = redis.call('HGET', KEYS[1], 'deadline')
+if not deadline then
+  return -2
+end
+if tonumber(ARGV[3]) >= tonumber(deadline) then
+  return -1
+end
+if redis.call('HEXISTS', KEYS[3], ARGV[2]) == 0 then
+  return -3
+end
+if redis.call('HSETNX', KEYS[2], ARGV[1], ARGV[2]) == 0 then
+  return 0
+end
+redis.call('HINCRBY', KEYS[3], ARGV[2], 1)
+return 1
+`)
+
+// Poll is a time-limited vote: one vote per voter, tallied per option
+// and frozen at the deadline. Keys share a hash tag so polls also work
+// in a cluster.
+type Poll struct {
+	client *Client
+	id     string
+}
+
+// Poll returns a handle for poll id. Call Open once to start it.
+//
+// Example:
+//
+//	poll := app.Poll("lunch:friday")
+//	err := poll.Open(ctx, time.Now().Add(2*time.Hour), "nasi-padang", "bakso", "sate")
+//	voted, err := poll.Vote(ctx, "user:123", "bakso")
+//	tally, err := poll.Tally(ctx)
+func (c *Client) Poll(id string) *Poll {
+	return &Poll{client: c, id: id}
+}
+
+func (p *Poll) key(part string) string {
+	return "gibrun:poll:{" + p.id + "}:" + part
+}
+
+// Open starts the poll with its options, accepting votes until deadline.
+// Opening again extends the deadline and adds new options, keeping votes.
+func (p *Poll) Open(ctx context.Context, deadline time.Time, options ...string) error {
+	_, err := p.client.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, p.key("meta"), "deadline", deadline.UnixMilli())
+		for _, opt := range options {
+			pipe.HSetNX(ctx, p.key("tally"), opt, 0)
+		}
+		return nil
+	})
+	return err
+}
+
+// Vote records voter's choice. Returns (true, nil) if the vote counted and
+// (false, nil) if the voter already voted. Returns ErrPollClosed after the
+// deadline (or before Open) and ErrUnknownOption for options not offered.
+func (p *Poll) Vote(ctx context.Context, voter, option string) (bool, error) {
+	now := p.client.clock.Now().UnixMilli()
+	var res int
+	var err error
+	if p.client.scripting(ctx) {
+		res, err = voteScript.Run(ctx, p.client.rdb,
+			[]string{p.key("meta"), p.key("voters"), p.key("tally")},
+			voter, option, now).Int()
+	} else {
+		res, err = p.voteFallback(ctx, voter, option, now)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch res {
+	case 1:
+		return true, nil
+	case 0:
+		return false, nil
+	case -3:
+		return false, ErrUnknownOption
+	default:
+		return false, ErrPollClosed
+	}
+}
+
+// voteFallback mirrors voteScript with WATCH/MULTI.
+func (p *Poll) voteFallback(ctx context.Context, voter, option string, now int64) (int, error) {
+	res := 0
+	err := p.client.watchRetry(ctx, func(tx *redis.Tx) error {
+		deadline, err := tx.HGet(ctx, p.key("meta"), "deadline").Int64()
+		switch {
+		case err == redis.Nil:
+			res = -2
+			return nil
+		case err != nil:
+			return err
+		case now >= deadline:
+			res = -1
+			return nil
+		}
+		if ok, err := tx.HExists(ctx, p.key("tally"), option).Result(); err != nil || !ok {
+			res = -3
+			return err
+		}
+		if voted, err := tx.HExists(ctx, p.key("voters"), voter).Result(); err != nil || voted {
+			res = 0
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, p.key("voters"), voter, option)
+			pipe.HIncrBy(ctx, p.key("tally"), option, 1)
+			return nil
+		})
+		res = 1
+		return err
+	}, p.key("meta"), p.key("voters"), p.key("tally"))
+	return res, err
+}
+
+// Tally returns the vote count per option.
+func (p *Poll) Tally(ctx context.Context) (map[string]int64, error) {
+	vals, err := p.client.rdb.HGetAll(ctx, p.key("tally")).Result()
+	if err != nil {
+		return nil, err
+	}
+	tally := make(map[string]int64, len(vals))
+	for opt, n := range vals {
+		tally[opt], _ = strconv.ParseInt(n, 10, 64)
+	}
+	return tally, nil
+}
+
+// VoteOf returns the option voter chose.
+// Returns (option, true, nil) if they voted, ("", false, nil) if not.
+func (p *Poll) VoteOf(ctx context.Context, voter string) (string, bool, error) {
+	opt, err := p.client.rdb.HGet(ctx, p.key("voters"), voter).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+	return opt, true, nil
+}
+
+// Closed reports whether the deadline has passed (or the poll was never opened).
+func (p *Poll) Closed(ctx context.Context) (bool, error) {
+	ms, err := p.client.rdb.HGet(ctx, p.key("meta"), "deadline").Int64()
+	if err != nil {
+		if err == redis.Nil {
+			return true, nil
+		}
+		return false, err
+	}
+	return p.client.clock.Now().UnixMilli() >= ms, nil
+}
+
+// Delete removes the poll and all its votes.
+func (p *Poll) Delete(ctx context.Context) error {
+	return p.client.rdb.Del(ctx, p.key("meta"), p.key("voters"), p.key("tally")).Err()
+}
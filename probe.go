@@ -0,0 +1,214 @@
+package gibrun
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Capability names reported by ProbeServer.
+const (
+	// CapRESP3 means the server speaks the RESP3 protocol (Redis 6.0+).
+	CapRESP3 = "resp3"
+
+	// CapModules means MODULE LIST is available and at least one module is loaded.
+	CapModules = "modules"
+
+	// CapFunctions means FUNCTION/FCALL is available (Redis 7.0+).
+	CapFunctions = "functions"
+
+	// CapScanType means SCAN accepts the TYPE filter (Redis 6.0+).
+	CapScanType = "scan_type"
+
+	// CapObjectFreq means OBJECT FREQ works, which requires an LFU eviction policy.
+	CapObjectFreq = "object_freq"
+
+	// CapScripting means EVAL is allowed. Managed offerings and ACLs may
+	// disable it; gibrun then falls back to WATCH/MULTI, see scripting.go.
+	CapScripting = "scripting"
+)
+
+// Version is a parsed Redis server version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses a version string such as "7.2.4" or "6.2".
+func ParseVersion(s string) (Version, error) {
+	var v Version
+	parts := strings.SplitN(strings.TrimSpace(s), ".", 3)
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return Version{}, fmt.Errorf("gibrun: invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, nil
+}
+
+// AtLeast reports whether v is greater than or equal to other.
+func (v Version) AtLeast(other Version) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// String returns the version in "major.minor.patch" form.
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// ServerInfo describes a Redis server and the features it supports.
+type ServerInfo struct {
+	// Version is the server version from INFO.
+	Version Version
+
+	// Mode is "standalone", "cluster" or "sentinel".
+	Mode string
+
+	// Modules lists loaded module names (e.g. "search", "ReJSON").
+	Modules []string
+
+	// Capabilities maps capability names (CapRESP3, ...) to availability.
+	Capabilities map[string]bool
+}
+
+// Supports reports whether the server has the given capability.
+func (i *ServerInfo) Supports(capability string) bool {
+	return i.Capabilities[capability]
+}
+
+// HasModule reports whether a module with the given name is loaded.
+// The comparison is case-insensitive.
+func (i *ServerInfo) HasModule(name string) bool {
+	for _, m := range i.Modules {
+		if strings.EqualFold(m, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProbeServer inspects the server behind client and reports its version
+// and capabilities. The result is cached on the client so gibrun
+// subsystems can pick an implementation or fail with a clear error.
+//
+// Example:
+//
+//	info, err := gibrun.ProbeServer(ctx, app)
+//	if info.Supports(gibrun.CapScanType) {
+//	    // SCAN ... TYPE is available
+//	}
+func ProbeServer(ctx context.Context, client *Client) (*ServerInfo, error) {
+	raw, err := client.rdb.Info(ctx).Result()
+	if err != nil {
+		return nil, fmt.Errorf("gibrun: probe failed: %w", err)
+	}
+
+	fields := parseInfo(raw)
+	version, err := ParseVersion(fields["redis_version"])
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ServerInfo{
+		Version:      version,
+		Mode:         fields["redis_mode"],
+		Capabilities: make(map[string]bool),
+	}
+
+	// MODULE LIST may be disabled on managed offerings - treat as no modules
+	if reply, err := client.rdb.Do(ctx, "MODULE", "LIST").Slice(); err == nil {
+		info.Modules = parseModuleList(reply)
+	}
+
+	redis6 := Version{Major: 6}
+	info.Capabilities[CapRESP3] = version.AtLeast(redis6)
+	info.Capabilities[CapScanType] = version.AtLeast(redis6)
+	info.Capabilities[CapFunctions] = version.AtLeast(Version{Major: 7})
+	info.Capabilities[CapModules] = len(info.Modules) > 0
+	info.Capabilities[CapObjectFreq] = version.AtLeast(Version{Major: 4}) &&
+		strings.Contains(fields["maxmemory_policy"], "lfu")
+
+	// EVAL may be renamed, blocked by ACLs or disabled outright
+	info.Capabilities[CapScripting] = client.rdb.Eval(ctx, "return 1", nil).Err() == nil
+
+	client.probeMu.Lock()
+	client.probed = info
+	client.probeMu.Unlock()
+
+	return info, nil
+}
+
+// serverInfo returns the cached probe result, probing on first use.
+func (c *Client) serverInfo(ctx context.Context) (*ServerInfo, error) {
+	c.probeMu.Lock()
+	info := c.probed
+	c.probeMu.Unlock()
+	if info != nil {
+		return info, nil
+	}
+	return ProbeServer(ctx, c)
+}
+
+// requireVersion returns a VersionError if the server is older than min.
+// Probe failures are ignored so the command itself can report the problem.
+func (c *Client) requireVersion(ctx context.Context, feature string, min Version) error {
+	info, err := c.serverInfo(ctx)
+	if err != nil {
+		return nil
+	}
+	if !info.Version.AtLeast(min) {
+		return &VersionError{Feature: feature, Required: min, Actual: info.Version}
+	}
+	return nil
+}
+
+// parseInfo parses INFO output into a flat field map.
+func parseInfo(raw string) map[string]string {
+	fields := make(map[string]string)
+	sc := bufio.NewScanner(strings.NewReader(raw))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, ":"); ok {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// parseModuleList extracts module names from a MODULE LIST reply.
+// Handles both RESP2 (flat name/value arrays) and RESP3 (maps) shapes.
+func parseModuleList(reply []any) []string {
+	var names []string
+	for _, entry := range reply {
+		switch m := entry.(type) {
+		case map[any]any:
+			if name, ok := m["name"].(string); ok {
+				names = append(names, name)
+			}
+		case []any:
+			for i := 0; i+1 < len(m); i += 2 {
+				if k, _ := m[i].(string); k == "name" {
+					if name, ok := m[i+1].(string); ok {
+						names = append(names, name)
+					}
+				}
+			}
+		}
+	}
+	return names
+}
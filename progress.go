@@ -0,0 +1,197 @@
+package gibrun
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// progressPrefix namespaces progress hashes.
+const progressPrefix = "gibrun:progress:"
+
+// ProgressOptions configures a Progress tracker.
+type ProgressOptions struct {
+	// StaleAfter marks a running job stale when it hasn't reported for
+	// this long. Default is 1 minute.
+	StaleAfter time.Duration
+
+	// TTL keeps the record around after the last update. Default is 24 hours.
+	TTL time.Duration
+}
+
+// ProgressState is a snapshot of a tracked job, as returned to pollers.
+type ProgressState struct {
+	ID        string    `json:"id"`
+	Percent   float64   `json:"percent"`
+	Stage     string    `json:"stage,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Done      bool      `json:"done"`
+	Error     string    `json:"error,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Stale is true when a job that isn't done stopped heartbeating.
+	Stale bool `json:"stale"`
+}
+
+// Progress lets a long job (migration, import, report build) publish its
+// progress to Redis so any instance can answer "where is my export?".
+type Progress struct {
+	client *Client
+	id     string
+	opts   ProgressOptions
+}
+
+// NewProgress creates a tracker for job id and records its start.
+//
+// Example:
+//
+//	p, err := app.NewProgress(ctx, "export:42", gibrun.ProgressOptions{})
+//	p.Update(ctx, 10, "collect", "reading orders")
+//	p.Update(ctx, 80, "render", "building PDF")
+//	p.Finish(ctx, "ready")
+func (c *Client) NewProgress(ctx context.Context, id string, opts ProgressOptions) (*Progress, error) {
+	if opts.StaleAfter == 0 {
+		opts.StaleAfter = time.Minute
+	}
+	if opts.TTL == 0 {
+		opts.TTL = 24 * time.Hour
+	}
+
+	p := &Progress{client: c, id: id, opts: opts}
+	now := c.clock.Now()
+	err := p.write(ctx, map[string]any{
+		"percent":     0,
+		"stage":       "",
+		"message":     "",
+		"done":        0,
+		"error":       "",
+		"started_at":  now.UnixMilli(),
+		"stale_after": opts.StaleAfter.Milliseconds(),
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Update records percent (0-100), stage and message. It also counts as
+// a heartbeat.
+func (p *Progress) Update(ctx context.Context, percent float64, stage, message string) error {
+	return p.write(ctx, map[string]any{
+		"percent": percent,
+		"stage":   stage,
+		"message": message,
+	}, p.client.clock.Now())
+}
+
+// Heartbeat marks the job alive without changing its progress.
+// Call it during long steps to avoid being reported stale.
+func (p *Progress) Heartbeat(ctx context.Context) error {
+	return p.write(ctx, nil, p.client.clock.Now())
+}
+
+// Finish marks the job done at 100%.
+func (p *Progress) Finish(ctx context.Context, message string) error {
+	return p.write(ctx, map[string]any{
+		"percent": 100,
+		"message": message,
+		"done":    1,
+	}, p.client.clock.Now())
+}
+
+// Fail marks the job done with an error.
+func (p *Progress) Fail(ctx context.Context, cause error) error {
+	return p.write(ctx, map[string]any{
+		"done":  1,
+		"error": cause.Error(),
+	}, p.client.clock.Now())
+}
+
+// write updates fields and the heartbeat, refreshing the TTL.
+func (p *Progress) write(ctx context.Context, fields map[string]any, now time.Time) error {
+	if fields == nil {
+		fields = make(map[string]any, 1)
+	}
+	fields["updated_at"] = now.UnixMilli()
+
+	key := progressPrefix + p.id
+	_, err := p.client.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, fields)
+		pipe.PExpire(ctx, key, p.opts.TTL)
+		return nil
+	})
+	return err
+}
+
+// ProgressOf returns the state of job id.
+// Returns (state, true, nil) if tracked, (nil, false, nil) if unknown.
+//
+// Example:
+//
+//	state, found, err := app.ProgressOf(ctx, "export:42")
+func (c *Client) ProgressOf(ctx context.Context, id string) (*ProgressState, bool, error) {
+	vals, err := c.rdb.HGetAll(ctx, progressPrefix+id).Result()
+	if err != nil {
+		return nil, false, err
+	}
+	if len(vals) == 0 {
+		return nil, false, nil
+	}
+
+	millis := func(field string) int64 {
+		n, _ := strconv.ParseInt(vals[field], 10, 64)
+		return n
+	}
+
+	state := &ProgressState{
+		ID:        id,
+		Stage:     vals["stage"],
+		Message:   vals["message"],
+		Done:      vals["done"] == "1",
+		Error:     vals["error"],
+		StartedAt: time.UnixMilli(millis("started_at")),
+		UpdatedAt: time.UnixMilli(millis("updated_at")),
+	}
+	state.Percent, _ = strconv.ParseFloat(vals["percent"], 64)
+
+	staleAfter := time.Duration(millis("stale_after")) * time.Millisecond
+	if !state.Done && staleAfter > 0 && c.clock.Now().Sub(state.UpdatedAt) > staleAfter {
+		state.Stale = true
+	}
+	return state, true, nil
+}
+
+// ProgressHandler serves job states as JSON for polling endpoints.
+// The job ID is read from the "id" query parameter; unknown jobs get 404.
+//
+// Example:
+//
+//	http.Handle("/exports/status", app.ProgressHandler())
+//	// GET /exports/status?id=export:42
+func (c *Client) ProgressHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Query().Get("id")
+		if id == "" {
+			http.Error(w, "missing id", http.StatusBadRequest)
+			return
+		}
+
+		state, found, err := c.ProgressOf(r.Context(), id)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(state)
+	})
+}
@@ -0,0 +1,71 @@
+package gibrun
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxRateSamples bounds the sample ring kept by Rate.
+const maxRateSamples = 64
+
+// Rate samples the counter and returns its increments per second over
+// window. Samples are kept in a small sorted set next to the counter
+// ("key:rate"), trimmed to the window and to 64 entries, so call Rate
+// periodically (e.g. from the dashboard poll) to keep the ring fed.
+// Returns 0 until a second sample inside the window exists.
+//
+// Example:
+//
+//	perSec, err := app.Sprint(ctx, "stats:orders").Rate(time.Minute)
+func (b *SprintBuilder) Rate(window time.Duration) (float64, error) {
+	ring := b.key + ":rate"
+	now := b.client.clock.Now().UnixMilli()
+
+	var get *redis.StringCmd
+	var oldest *redis.ZSliceCmd
+	_, err := b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(b.ctx, b.key)
+		pipe.ZRemRangeByScore(b.ctx, ring, "-inf", "("+strconv.FormatInt(now-window.Milliseconds(), 10))
+		oldest = pipe.ZRangeWithScores(b.ctx, ring, 0, 0)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+	current, _, err := counterValue(get)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(b.ctx, ring, redis.Z{
+			Score:  float64(now),
+			Member: strconv.FormatInt(now, 10) + ":" + strconv.FormatInt(current, 10),
+		})
+		pipe.ZRemRangeByRank(b.ctx, ring, 0, -maxRateSamples-1)
+		pipe.PExpire(b.ctx, ring, 2*window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	samples := oldest.Val()
+	if len(samples) == 0 {
+		return 0, nil
+	}
+	member, _ := samples[0].Member.(string)
+	_, val, _ := strings.Cut(member, ":")
+	then, err := strconv.ParseInt(val, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := now - int64(samples[0].Score)
+	if elapsed <= 0 {
+		return 0, nil
+	}
+	return float64(current-then) * 1000 / float64(elapsed), nil
+}
@@ -37,6 +37,8 @@ type RateLimitConfig struct {
 type RateLimiter struct {
 	client *Client
 	config RateLimitConfig
+	// err holds configuration problems found at construction.
+	err error
 }
 
 // RateLimitResult contains the result of a rate limit check.
@@ -55,6 +57,8 @@ type RateLimitResult struct {
 }
 
 // NewRateLimiter creates a new Bansos rate limiter.
+// An invalid configuration (e.g. zero Rate or sub-second Window) is
+// reported by every Allow call; check it up front with Err.
 //
 // Example:
 //
@@ -76,6 +80,7 @@ func NewRateLimiter(client *Client, config RateLimitConfig) *RateLimiter {
 	return &RateLimiter{
 		client: client,
 		config: config,
+		err:    config.Validate(),
 	}
 }
 
@@ -95,7 +100,11 @@ func (rl *RateLimiter) Allow(ctx context.Context, key string) (*RateLimitResult,
 // AllowN checks if n requests should be allowed.
 // Useful for operations that consume multiple tokens.
 func (rl *RateLimiter) AllowN(ctx context.Context, key string, n int) (*RateLimitResult, error) {
-	now := time.Now()
+	if rl.err != nil {
+		return nil, rl.err
+	}
+
+	now := rl.client.clock.Now()
 	windowKey := rl.buildKey(key, now)
 
 	// Use Redis transaction to atomically increment and get TTL
@@ -182,10 +191,19 @@ func (rl *RateLimiter) MiddlewareFunc(next http.HandlerFunc) http.HandlerFunc {
 	return rl.Middleware(next).ServeHTTP
 }
 
+// Err returns the configuration error found at construction, if any.
+func (rl *RateLimiter) Err() error {
+	return rl.err
+}
+
 // Reset clears the rate limit for a specific key.
 // Useful for admin overrides or testing.
 func (rl *RateLimiter) Reset(ctx context.Context, key string) error {
-	now := time.Now()
+	if rl.err != nil {
+		return rl.err
+	}
+
+	now := rl.client.clock.Now()
 	windowKey := rl.buildKey(key, now)
 	return rl.client.rdb.Del(ctx, windowKey).Err()
 }
@@ -215,6 +233,8 @@ func defaultKeyFunc(r *http.Request) string {
 type ClusterRateLimiter struct {
 	client *ClusterClient
 	config RateLimitConfig
+	// err holds configuration problems found at construction.
+	err error
 }
 
 // NewClusterRateLimiter creates a rate limiter for Redis Cluster.
@@ -232,9 +252,15 @@ func NewClusterRateLimiter(client *ClusterClient, config RateLimitConfig) *Clust
 	return &ClusterRateLimiter{
 		client: client,
 		config: config,
+		err:    config.Validate(),
 	}
 }
 
+// Err returns the configuration error found at construction, if any.
+func (rl *ClusterRateLimiter) Err() error {
+	return rl.err
+}
+
 // Allow checks if a request with the given key should be allowed.
 func (rl *ClusterRateLimiter) Allow(ctx context.Context, key string) (*RateLimitResult, error) {
 	return rl.AllowN(ctx, key, 1)
@@ -242,7 +268,11 @@ func (rl *ClusterRateLimiter) Allow(ctx context.Context, key string) (*RateLimit
 
 // AllowN checks if n requests should be allowed.
 func (rl *ClusterRateLimiter) AllowN(ctx context.Context, key string, n int) (*RateLimitResult, error) {
-	now := time.Now()
+	if rl.err != nil {
+		return nil, rl.err
+	}
+
+	now := rl.client.clock.Now()
 	windowKey := rl.buildKey(key, now)
 
 	pipe := rl.client.rdb.Pipeline()
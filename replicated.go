@@ -0,0 +1,245 @@
+package gibrun
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReplicatedConfig configures a read/write split over a primary and
+// replicas set up with REPLICAOF (non-cluster deployments).
+type ReplicatedConfig struct {
+	// Primary is the configuration of the write node. Replicas share its
+	// password, DB, codec and other settings.
+	Primary Config
+
+	// Replicas lists the replica addresses (host:port) serving reads.
+	Replicas []string
+
+	// MaxLag is the staleness guard: replicas whose link to the primary is
+	// down or silent for longer than MaxLag stop serving reads.
+	// Default is 10 seconds.
+	//
+	// Silence is read from master_last_io_seconds_ago, which only tells
+	// when the replica last heard from the primary, not how far its data
+	// trails it. It is a lower bound on staleness: a replica passing the
+	// check may still serve older data during a write burst.
+	MaxLag time.Duration
+
+	// HealthInterval is how often node roles and lag are checked.
+	// Default is 5 seconds.
+	HealthInterval time.Duration
+
+	// OnPromotion is called when a replica has been promoted and becomes
+	// the write node.
+	OnPromotion func(newPrimary string)
+}
+
+// ReplicatedClient sends writes to the primary and spreads reads across
+// healthy replicas. Roles are re-checked in the background, so a replica
+// promoted by failover tooling automatically becomes the write node.
+type ReplicatedClient struct {
+	cfg   ReplicatedConfig
+	nodes []*replicatedNode
+
+	mu       sync.RWMutex
+	primary  *replicatedNode
+	replicas []*replicatedNode
+
+	next atomic.Uint64
+	stop chan struct{}
+	done chan struct{}
+}
+
+// replicatedNode is a single endpoint of a replicated deployment.
+type replicatedNode struct {
+	addr   string
+	client *Client
+}
+
+// NewReplicated creates a read/write split client and starts the
+// background health checker. Until the first check completes, all
+// configured replicas serve reads.
+//
+// Example:
+//
+//	app := gibrun.NewReplicated(gibrun.ReplicatedConfig{
+//	    Primary:  gibrun.Config{Addr: "redis-primary:6379"},
+//	    Replicas: []string{"redis-replica-1:6379", "redis-replica-2:6379"},
+//	    MaxLag:   5 * time.Second,
+//	})
+//	defer app.Close()
+//
+//	app.Gib(ctx, "user:123").Value(user).Exec()        // primary
+//	found, err := app.Run(ctx, "user:123").Bind(&user) // replica
+func NewReplicated(cfg ReplicatedConfig) *ReplicatedClient {
+	if cfg.MaxLag <= 0 {
+		cfg.MaxLag = 10 * time.Second
+	}
+	if cfg.HealthInterval <= 0 {
+		cfg.HealthInterval = 5 * time.Second
+	}
+
+	r := &ReplicatedClient{
+		cfg:  cfg,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	r.primary = &replicatedNode{addr: cfg.Primary.Addr, client: New(cfg.Primary)}
+	r.nodes = append(r.nodes, r.primary)
+	for _, addr := range cfg.Replicas {
+		nodeCfg := cfg.Primary
+		nodeCfg.Addr = addr
+		node := &replicatedNode{addr: addr, client: New(nodeCfg)}
+		r.nodes = append(r.nodes, node)
+		r.replicas = append(r.replicas, node)
+	}
+
+	go r.healthLoop()
+	return r
+}
+
+// Primary returns the client for the current write node.
+func (r *ReplicatedClient) Primary() *Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.primary.client
+}
+
+// Replica returns a healthy replica client, round robin.
+// Falls back to the primary when no replica is healthy.
+func (r *ReplicatedClient) Replica() *Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.replicas) == 0 {
+		return r.primary.client
+	}
+	n := r.next.Add(1)
+	return r.replicas[n%uint64(len(r.replicas))].client
+}
+
+// Gib starts a data storage operation on the primary.
+func (r *ReplicatedClient) Gib(ctx context.Context, key string) *GibBuilder {
+	return r.Primary().Gib(ctx, key)
+}
+
+// Run starts a data retrieval operation on a healthy replica. Writes the
+// read makes (OrElse backfill, negative markers, Touch, BindAndDelete)
+// go to the primary.
+func (r *ReplicatedClient) Run(ctx context.Context, key string) *RunBuilder {
+	b := r.Replica().Run(ctx, key)
+	b.writer = r.Primary()
+	return b
+}
+
+// Sprint starts an atomic operation on the primary.
+func (r *ReplicatedClient) Sprint(ctx context.Context, key string) *SprintBuilder {
+	return r.Primary().Sprint(ctx, key)
+}
+
+// Del deletes one or more keys on the primary.
+func (r *ReplicatedClient) Del(ctx context.Context, keys ...string) error {
+	return r.Primary().Del(ctx, keys...)
+}
+
+// Exists checks if a key exists, reading from a replica.
+func (r *ReplicatedClient) Exists(ctx context.Context, key string) (bool, error) {
+	return r.Replica().Exists(ctx, key)
+}
+
+// Ping checks the connection to every node.
+func (r *ReplicatedClient) Ping(ctx context.Context) error {
+	for _, node := range r.nodes {
+		if err := node.client.Ping(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops the health checker and closes all node connections.
+func (r *ReplicatedClient) Close() error {
+	close(r.stop)
+	<-r.done
+
+	var firstErr error
+	for _, node := range r.nodes {
+		if err := node.client.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Refresh checks node roles and replica lag immediately.
+// It runs automatically every HealthInterval.
+func (r *ReplicatedClient) Refresh(ctx context.Context) {
+	var (
+		primary  *replicatedNode
+		replicas []*replicatedNode
+	)
+
+	r.mu.RLock()
+	current := r.primary
+	r.mu.RUnlock()
+
+	for _, node := range r.nodes {
+		raw, err := node.client.rdb.Info(ctx, "replication").Result()
+		if err != nil {
+			continue
+		}
+		fields := parseInfo(raw)
+
+		switch fields["role"] {
+		case "master":
+			// Prefer the current primary if several nodes claim the role
+			if primary == nil || node == current {
+				primary = node
+			}
+		case "slave":
+			if fields["master_link_status"] != "up" {
+				continue
+			}
+			lastIO, err := strconv.Atoi(fields["master_last_io_seconds_ago"])
+			if err != nil || time.Duration(lastIO)*time.Second > r.cfg.MaxLag {
+				continue
+			}
+			replicas = append(replicas, node)
+		}
+	}
+
+	r.mu.Lock()
+	promoted := primary != nil && primary != r.primary
+	if primary != nil {
+		r.primary = primary
+	}
+	r.replicas = replicas
+	r.mu.Unlock()
+
+	if promoted && r.cfg.OnPromotion != nil {
+		r.cfg.OnPromotion(primary.addr)
+	}
+}
+
+// healthLoop refreshes topology until Close.
+func (r *ReplicatedClient) healthLoop() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.cfg.HealthInterval)
+	defer ticker.Stop()
+
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), r.cfg.HealthInterval)
+		r.Refresh(ctx)
+		cancel()
+
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package gibrun
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// retryPolicy retries transient failures of a single operation.
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// Retry retries the read up to n more times on transient errors (network
+// failures, timeouts, LOADING, TRYAGAIN, ...), waiting backoff before the
+// first retry and doubling it each time. Misses and decode errors are
+// never retried.
+//
+// Example:
+//
+//	found, err := app.Run(ctx, "config:flags").Retry(3, 50*time.Millisecond).Bind(&flags)
+func (b *RunBuilder) Retry(n int, backoff time.Duration) *RunBuilder {
+	b.retry = retryPolicy{attempts: n, backoff: backoff}
+	return b
+}
+
+// Retry retries the write up to n more times on transient errors, waiting
+// backoff before the first retry and doubling it each time. Validation,
+// version conflicts and other non-transient errors are never retried.
+//
+// Example:
+//
+//	err := app.Gib(ctx, "order:42").Value(order).Retry(3, 50*time.Millisecond).Exec()
+func (b *GibBuilder) Retry(n int, backoff time.Duration) *GibBuilder {
+	b.retry = retryPolicy{attempts: n, backoff: backoff}
+	return b
+}
+
+// do runs fn, retrying transient errors while attempts and ctx allow.
+func (p retryPolicy) do(ctx context.Context, clock Clock, fn func() error) error {
+	err := fn()
+	backoff := p.backoff
+	for i := 0; i < p.attempts && isTransient(err); i++ {
+		clock.Sleep(backoff)
+		if ctx.Err() != nil {
+			return err
+		}
+		backoff *= 2
+		err = fn()
+	}
+	return err
+}
+
+// isTransient reports whether err is worth retrying: network failures and
+// server states that clear up on their own.
+func isTransient(err error) bool {
+	if err == nil || err == redis.Nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	msg := err.Error()
+	if msg == "redis: connection pool timeout" {
+		return true
+	}
+	for _, prefix := range []string{"LOADING ", "TRYAGAIN ", "CLUSTERDOWN ", "MASTERDOWN ", "READONLY "} {
+		if strings.HasPrefix(msg, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,7 @@ package gibrun
 
 import (
 	"context"
-	"encoding/json"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -13,6 +13,53 @@ type RunBuilder struct {
 	ctx    context.Context
 	client *Client
 	key    string
+	codec  Codec
+
+	// loader fills cache misses, see OrElse.
+	loader LoaderFunc
+
+	// maxStale enables stale-while-revalidate, see StaleWhileRevalidate.
+	maxStale time.Duration
+
+	// negativeTTL caches confirmed misses, see WithNegativeCache.
+	// negativeHit is set when the last read found the marker.
+	negativeTTL time.Duration
+	negativeHit bool
+
+	// touch extends the TTL on every read, see Touch; persistRead
+	// removes it, see Persist.
+	touch       time.Duration
+	persistRead bool
+
+	// retry retries transient read errors, see Retry.
+	retry retryPolicy
+
+	// writer takes the writes a read makes (loader backfill, negative
+	// markers, Touch, BindAndDelete) when client is a read replica.
+	// Nil means client itself.
+	writer *Client
+}
+
+// primary returns the client that accepts writes for this read.
+func (b *RunBuilder) primary() *Client {
+	if b.writer != nil {
+		return b.writer
+	}
+	return b.client
+}
+
+// toWriter moves the rest of the operation to the writer, for reads
+// that modify the key.
+func (b *RunBuilder) toWriter() {
+	if b.writer != nil {
+		b.client, b.writer = b.writer, nil
+	}
+}
+
+// Codec overrides the client codec for this operation.
+func (b *RunBuilder) Codec(c Codec) *RunBuilder {
+	b.codec = c
+	return b
 }
 
 // Bind retrieves the data and unmarshals it into the provided pointer.
@@ -35,18 +82,26 @@ func (b *RunBuilder) Bind(dest any) (bool, error) {
 		return false, ErrNilPointer
 	}
 
+	if b.maxStale > 0 && b.loader != nil {
+		return b.bindSWR(dest)
+	}
+
 	// Get from Redis
-	data, err := b.client.rdb.Get(b.ctx, b.key).Bytes()
+	data, err := b.get()
 	if err != nil {
 		if err == redis.Nil {
 			// Cache miss - data tidak ditemukan, mohon klarifikasi
+			if b.loader != nil && !b.negativeHit {
+				return b.load(dest)
+			}
 			return false, nil
 		}
 		return false, err
 	}
 
 	// Unmarshal based on destination type
-	if err := b.unmarshal(data, dest); err != nil {
+	if err := b.client.enc.unmarshal(data, dest, b.codec); err != nil {
+		b.client.stats.bindError(b.key)
 		return false, err
 	}
 
@@ -60,31 +115,99 @@ func (b *RunBuilder) Bind(dest any) (bool, error) {
 //
 //	value, found, err := app.Run(ctx, "simple:key").Raw()
 func (b *RunBuilder) Raw() (string, bool, error) {
-	val, err := b.client.rdb.Get(b.ctx, b.key).Result()
-	if err != nil {
-		if err == redis.Nil {
-			return "", false, nil
-		}
+	val, found, err := b.Bytes()
+	if err != nil || !found {
 		return "", false, err
 	}
-	return val, true, nil
+	return string(val), true, nil
 }
 
 // Bytes retrieves the raw byte slice without unmarshalling.
 // Returns (value, true, nil) if found, (nil, false, nil) if not found.
 func (b *RunBuilder) Bytes() ([]byte, bool, error) {
-	val, err := b.client.rdb.Get(b.ctx, b.key).Bytes()
+	val, err := b.get()
 	if err != nil {
 		if err == redis.Nil {
 			return nil, false, nil
 		}
 		return nil, false, err
 	}
+
+	val, err = b.client.enc.open(val)
+	if err != nil {
+		return nil, false, err
+	}
 	return val, true, nil
 }
 
+// StrLen returns the length of the stored string value in bytes.
+// Returns 0 if the key doesn't exist. For encrypted or enveloped values
+// this is the stored length, not the payload length.
+//
+// Example:
+//
+//	n, err := app.Run(ctx, "chat:42:tokens").StrLen()
+func (b *RunBuilder) StrLen() (int64, error) {
+	return b.client.rdb.StrLen(b.ctx, b.key).Result()
+}
+
+// get fetches the stored bytes for the key, recording stats.
+// Returns redis.Nil on a cache miss.
+func (b *RunBuilder) get() ([]byte, error) {
+	start := time.Now()
+	var data []byte
+	err := b.retry.do(b.ctx, b.client.clock, func() error {
+		var err error
+		data, err = b.fetch()
+		return err
+	})
+	if err == redis.Nil {
+		b.client.stats.read(b.key, false, nil, time.Since(start))
+	} else {
+		b.client.stats.read(b.key, err == nil, err, time.Since(start))
+	}
+	return data, err
+}
+
+// fetch reads the stored bytes from the L1 or Redis.
+func (b *RunBuilder) fetch() ([]byte, error) {
+	if b.touch > 0 || b.persistRead {
+		b.toWriter()
+		return b.finishGet(b.getTouch())
+	}
+
+	l1 := b.client.l1
+	if l1 == nil {
+		return b.finishGet(b.client.rdb.Get(b.ctx, b.key).Bytes())
+	}
+	if data, ok := l1.get(b.key); ok {
+		return b.finishGet(data, nil)
+	}
+	data, err := b.client.rdb.Get(b.ctx, b.key).Bytes()
+	if err == nil && envelopeKind(data) != envelopeChunked {
+		l1.set(b.key, data, 0)
+	}
+	return b.finishGet(data, err)
+}
+
+// finishGet runs the post-GET steps: shadow comparison, chunk
+// reassembly and turning negative-cache markers into misses.
+func (b *RunBuilder) finishGet(data []byte, err error) ([]byte, error) {
+	if err == nil || err == redis.Nil {
+		b.client.shadowRead(b.key, data, err == nil)
+	}
+	if err == nil && isNegative(data) {
+		b.negativeHit = true
+		return nil, redis.Nil
+	}
+	if err == nil && envelopeKind(data) == envelopeChunked {
+		return b.client.reassemble(b.ctx, b.key, data)
+	}
+	return data, err
+}
+
 // unmarshal converts stored data back to the target type.
-func (b *RunBuilder) unmarshal(data []byte, dest any) error {
+func unmarshal(data []byte, dest any, codec Codec) error {
 	// Handle string destination directly
 	if strPtr, ok := dest.(*string); ok {
 		*strPtr = string(data)
@@ -97,6 +220,6 @@ func (b *RunBuilder) unmarshal(data []byte, dest any) error {
 		return nil
 	}
 
-	// Default: JSON unmarshal for structs/slices/maps
-	return json.Unmarshal(data, dest)
+	// Default: codec unmarshal for structs/slices/maps
+	return codec.Unmarshal(data, dest)
 }
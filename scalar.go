@@ -0,0 +1,96 @@
+package gibrun
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Int reads the value as an integer, such as a Sprint counter or an int
+// stored with Gib. Returns (0, false, nil) on a cache miss.
+//
+// Example:
+//
+//	views, found, err := app.Run(ctx, "stats:views").Int()
+func (b *RunBuilder) Int() (int64, bool, error) {
+	return scalar(b.Bytes, b.key, parseInt)
+}
+
+// Float64 reads the value as a floating point number.
+// Returns (0, false, nil) on a cache miss.
+func (b *RunBuilder) Float64() (float64, bool, error) {
+	return scalar(b.Bytes, b.key, parseFloat)
+}
+
+// Bool reads the value as a boolean ("true", "false", "1", "0", ...).
+// Returns (false, false, nil) on a cache miss.
+func (b *RunBuilder) Bool() (bool, bool, error) {
+	return scalar(b.Bytes, b.key, parseBool)
+}
+
+// Time reads the value as a time.Time stored with Gib (JSON) or as an
+// RFC 3339 string. Returns the zero time and false on a cache miss.
+//
+// Example:
+//
+//	lastSeen, found, err := app.Run(ctx, "user:123:last-seen").Time()
+func (b *RunBuilder) Time() (time.Time, bool, error) {
+	return scalar(b.Bytes, b.key, parseTime)
+}
+
+// Int reads the value as an integer. Returns (0, false, nil) on a cache miss.
+func (b *ClusterRunBuilder) Int() (int64, bool, error) {
+	return scalar(b.Bytes, b.key, parseInt)
+}
+
+// Float64 reads the value as a floating point number.
+// Returns (0, false, nil) on a cache miss.
+func (b *ClusterRunBuilder) Float64() (float64, bool, error) {
+	return scalar(b.Bytes, b.key, parseFloat)
+}
+
+// Bool reads the value as a boolean. Returns (false, false, nil) on a cache miss.
+func (b *ClusterRunBuilder) Bool() (bool, bool, error) {
+	return scalar(b.Bytes, b.key, parseBool)
+}
+
+// Time reads the value as a time.Time stored with Gib (JSON) or as an
+// RFC 3339 string. Returns the zero time and false on a cache miss.
+func (b *ClusterRunBuilder) Time() (time.Time, bool, error) {
+	return scalar(b.Bytes, b.key, parseTime)
+}
+
+// scalar fetches the payload and parses it, naming the key on failure.
+func scalar[T any](fetch func() ([]byte, bool, error), key string, parse func([]byte) (T, error)) (T, bool, error) {
+	var zero T
+	data, found, err := fetch()
+	if err != nil || !found {
+		return zero, false, err
+	}
+	v, err := parse(bytes.TrimSpace(data))
+	if err != nil {
+		return zero, false, fmt.Errorf("gibrun: %s: %w", key, err)
+	}
+	return v, true, nil
+}
+
+func parseInt(data []byte) (int64, error) {
+	return strconv.ParseInt(string(data), 10, 64)
+}
+
+func parseFloat(data []byte) (float64, error) {
+	return strconv.ParseFloat(string(data), 64)
+}
+
+func parseBool(data []byte) (bool, error) {
+	return strconv.ParseBool(string(data))
+}
+
+// parseTime accepts JSON-quoted and bare RFC 3339 timestamps.
+func parseTime(data []byte) (time.Time, error) {
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		data = []byte(s)
+	}
+	return time.Parse(time.RFC3339Nano, string(data))
+}
@@ -2,6 +2,8 @@ package gibrun
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -27,6 +29,18 @@ type ScanOptions struct {
 	// Type filters by Redis data type: "string", "list", "set", "zset", "hash", "stream".
 	// Leave empty to scan all types.
 	Type string
+
+	// Where selects keys by content. Values of each scanned batch are
+	// fetched in one pipelined round trip and decoded from any envelope
+	// before the predicate sees them. Keys that vanished or don't hold
+	// strings are skipped.
+	//
+	// Example:
+	//
+	//	Where: func(key string, val []byte) bool {
+	//	    return bytes.Contains(val, []byte(`"plan":"trial"`))
+	//	}
+	Where func(key string, val []byte) bool
 }
 
 // ScanResult represents a single scanned key with optional metadata.
@@ -41,14 +55,15 @@ type ScanResult struct {
 // Scanner provides a safe, non-blocking way to iterate over keys.
 // It uses SCAN instead of KEYS to avoid blocking the Redis server.
 type Scanner struct {
-	ctx     context.Context
-	client  *Client
-	opts    ScanOptions
-	cursor  uint64
-	buffer  []string
-	bufIdx  int
-	done    bool
-	err     error
+	ctx    context.Context
+	client *Client
+	opts   ScanOptions
+	cursor uint64
+	buffer []string
+	values [][]byte
+	bufIdx int
+	done   bool
+	err    error
 }
 
 // Blusukan starts a safe key scanning operation.
@@ -104,11 +119,12 @@ func (s *Scanner) Next() bool {
 
 	// Need to fetch more keys
 	s.buffer = nil
+	s.values = nil
 	s.bufIdx = 0
 
 	// Apply batch delay if configured
 	if s.opts.BatchDelay > 0 && s.cursor > 0 {
-		time.Sleep(s.opts.BatchDelay)
+		s.client.clock.Sleep(s.opts.BatchDelay)
 	}
 
 	// Scan based on type filter
@@ -117,6 +133,14 @@ func (s *Scanner) Next() bool {
 	var err error
 
 	if s.opts.Type != "" {
+		// SCAN with TYPE filter needs Redis 6.0+ - fail clearly on older servers
+		if s.cursor == 0 {
+			if err := s.client.requireVersion(s.ctx, "SCAN TYPE", Version{Major: 6}); err != nil {
+				s.err = err
+				return false
+			}
+		}
+
 		// Use SCAN with TYPE filter (Redis 6.0+)
 		keys, cursor, err = s.client.rdb.ScanType(s.ctx, s.cursor, s.opts.Pattern, s.opts.Count, s.opts.Type).Result()
 	} else {
@@ -131,6 +155,14 @@ func (s *Scanner) Next() bool {
 	s.cursor = cursor
 	s.buffer = keys
 
+	if s.opts.Where != nil && len(keys) > 0 {
+		s.buffer, s.values, err = filterWhere(s.ctx, s.client.rdb, &s.client.enc, keys, s.opts.Where)
+		if err != nil {
+			s.err = err
+			return false
+		}
+	}
+
 	// Check if we're done
 	if cursor == 0 {
 		s.done = true
@@ -158,6 +190,15 @@ func (s *Scanner) Key() string {
 	return ""
 }
 
+// Value returns the current key's decoded value when ScanOptions.Where
+// is set, nil otherwise.
+func (s *Scanner) Value() []byte {
+	if s.bufIdx > 0 && s.bufIdx <= len(s.values) {
+		return s.values[s.bufIdx-1]
+	}
+	return nil
+}
+
 // Err returns any error that occurred during scanning.
 func (s *Scanner) Err() error {
 	return s.err
@@ -241,7 +282,7 @@ func (s *ClusterScanner) All() ([]string, error) {
 		for {
 			// Apply delay between batches
 			if s.opts.BatchDelay > 0 && cursor > 0 {
-				time.Sleep(s.opts.BatchDelay)
+				s.client.clock.Sleep(s.opts.BatchDelay)
 			}
 
 			var keys []string
@@ -254,6 +295,11 @@ func (s *ClusterScanner) All() ([]string, error) {
 			if err != nil {
 				return err
 			}
+			if s.opts.Where != nil && len(keys) > 0 {
+				if keys, _, err = filterWhere(ctx, master, &s.client.enc, keys, s.opts.Where); err != nil {
+					return err
+				}
+			}
 			allKeys = append(allKeys, keys...)
 			if cursor == 0 {
 				break
@@ -277,7 +323,7 @@ func (s *ClusterScanner) Each(fn func(key string) bool) error {
 		var cursor uint64
 		for {
 			if s.opts.BatchDelay > 0 && cursor > 0 {
-				time.Sleep(s.opts.BatchDelay)
+				s.client.clock.Sleep(s.opts.BatchDelay)
 			}
 
 			var keys []string
@@ -290,6 +336,11 @@ func (s *ClusterScanner) Each(fn func(key string) bool) error {
 			if err != nil {
 				return err
 			}
+			if s.opts.Where != nil && len(keys) > 0 {
+				if keys, _, err = filterWhere(ctx, master, &s.client.enc, keys, s.opts.Where); err != nil {
+					return err
+				}
+			}
 
 			for _, key := range keys {
 				if !fn(key) {
@@ -317,3 +368,103 @@ func (s *ClusterScanner) Count() (int, error) {
 	})
 	return count, err
 }
+
+// filterWhere fetches values for keys in one pipeline and keeps those
+// matching the predicate. GETs are pipelined rather than sent as MGET
+// so the same path works on cluster nodes, where keys span slots.
+func filterWhere(ctx context.Context, rdb redis.Cmdable, enc *encoding, keys []string, where func(string, []byte) bool) ([]string, [][]byte, error) {
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err := rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil && !isWrongType(err) {
+		return nil, nil, err
+	}
+
+	matched := keys[:0]
+	var values [][]byte
+	for i, cmd := range cmds {
+		val, err := cmd.Bytes()
+		if err != nil || isNegative(val) {
+			// Deleted since SCAN, not a string, or a negative-cache marker
+			continue
+		}
+		if opened, err := enc.open(val); err == nil {
+			val = opened
+		}
+		if where(keys[i], val) {
+			matched = append(matched, keys[i])
+			values = append(values, val)
+		}
+	}
+	return matched, values, nil
+}
+
+// isWrongType reports whether err is a WRONGTYPE reply.
+func isWrongType(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "WRONGTYPE")
+}
+
+// scanBatches calls fn with each SCAN batch, honoring Type and BatchDelay.
+func (c *Client) scanBatches(ctx context.Context, opts ScanOptions, fn func(keys []string) error) error {
+	if opts.Type != "" {
+		if err := c.requireVersion(ctx, "SCAN TYPE", Version{Major: 6}); err != nil {
+			return err
+		}
+	}
+	return scanNode(ctx, c.rdb, c.clock, opts, fn)
+}
+
+// scanBatches calls fn with each SCAN batch from every master. Masters
+// are scanned concurrently but fn is never called concurrently.
+func (c *ClusterClient) scanBatches(ctx context.Context, opts ScanOptions, fn func(keys []string) error) error {
+	var mu sync.Mutex
+	return c.rdb.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		return scanNode(ctx, master, c.clock, opts, func(keys []string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			return fn(keys)
+		})
+	})
+}
+
+// scanNode runs a full SCAN on a single node.
+func scanNode(ctx context.Context, node *redis.Client, clock Clock, opts ScanOptions, fn func(keys []string) error) error {
+	if opts.Pattern == "" {
+		opts.Pattern = "*"
+	}
+	if opts.Count == 0 {
+		opts.Count = 100
+	}
+
+	var cursor uint64
+	for {
+		if opts.BatchDelay > 0 && cursor > 0 {
+			clock.Sleep(opts.BatchDelay)
+		}
+
+		var keys []string
+		var next uint64
+		var err error
+		if opts.Type != "" {
+			keys, next, err = node.ScanType(ctx, cursor, opts.Pattern, opts.Count, opts.Type).Result()
+		} else {
+			keys, next, err = node.Scan(ctx, cursor, opts.Pattern, opts.Count).Result()
+		}
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,162 @@
+package gibrun
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// UpgradeFunc converts an encoded payload from the previous schema
+// version to the one it is registered for. It works on codec bytes
+// (e.g. JSON), before they are unmarshalled into the destination.
+type UpgradeFunc func(old []byte) ([]byte, error)
+
+// schemaRegistry holds upgrade steps per schema name. It is shared by
+// pointer so registrations after construction are seen by all builders.
+type schemaRegistry struct {
+	mu       sync.RWMutex
+	current  map[string]int
+	upgrades map[string]map[int]UpgradeFunc
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		current:  make(map[string]int),
+		upgrades: make(map[string]map[int]UpgradeFunc),
+	}
+}
+
+// RegisterSchema registers upgrade as the step from version-1 to version
+// of the named schema. Once a schema is registered, Gib writes values of
+// that type with a schema envelope at the highest registered version,
+// and Run().Bind upgrades older payloads step by step before
+// unmarshalling. Payloads written before the schema was registered count
+// as version 1 of the destination's type name.
+//
+// The schema name defaults to the Go type name of the value; use
+// GibBuilder.Schema to name it explicitly.
+//
+// Example:
+//
+//	// v2 renamed "name" to "full_name"
+//	app.RegisterSchema("User", 2, func(old []byte) ([]byte, error) {
+//	    var v1 map[string]any
+//	    if err := json.Unmarshal(old, &v1); err != nil {
+//	        return nil, err
+//	    }
+//	    v1["full_name"] = v1["name"]
+//	    delete(v1, "name")
+//	    return json.Marshal(v1)
+//	})
+func (c *Client) RegisterSchema(name string, version int, upgrade UpgradeFunc) {
+	c.enc.schemas.register(name, version, upgrade)
+}
+
+// RegisterSchema registers a schema upgrade step on the cluster client.
+// See Client.RegisterSchema.
+func (c *ClusterClient) RegisterSchema(name string, version int, upgrade UpgradeFunc) {
+	c.enc.schemas.register(name, version, upgrade)
+}
+
+// Schema names the schema of the stored value instead of deriving it
+// from the Go type name.
+//
+// Example:
+//
+//	app.Gib(ctx, "user:123").Value(u).Schema("User").Exec()
+func (b *GibBuilder) Schema(name string) *GibBuilder {
+	b.schema = name
+	return b
+}
+
+func (r *schemaRegistry) register(name string, version int, upgrade UpgradeFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.upgrades[name] == nil {
+		r.upgrades[name] = make(map[int]UpgradeFunc)
+	}
+	r.upgrades[name][version] = upgrade
+	if version > r.current[name] {
+		r.current[name] = version
+	}
+}
+
+// wrap adds a schema envelope when the value's schema is registered.
+func (r *schemaRegistry) wrap(v any, name string, data []byte) []byte {
+	switch v.(type) {
+	case string, []byte:
+		return data
+	}
+	if name == "" {
+		name = typeName(v)
+	}
+
+	r.mu.RLock()
+	version := r.current[name]
+	r.mu.RUnlock()
+	if version == 0 {
+		return data
+	}
+
+	out := make([]byte, 0, envelopeHeaderLen+len(name)+8+len(data))
+	out = append(out, envelopeMagic...)
+	out = append(out, envelopeSchema)
+	out = strconv.AppendInt(out, int64(version), 10)
+	out = append(out, ' ')
+	out = append(out, name...)
+	out = append(out, '\n')
+	return append(out, data...)
+}
+
+// upgrade applies the registered steps from version to the current one.
+// Payloads newer than the registry (e.g. during a rollback) are passed
+// through unchanged.
+func (r *schemaRegistry) upgrade(name string, version int, data []byte) ([]byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for v := version + 1; v <= r.current[name]; v++ {
+		step, ok := r.upgrades[name][v]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s v%d to v%d", ErrSchemaUpgrade, name, v-1, v)
+		}
+		var err error
+		if data, err = step(data); err != nil {
+			return nil, fmt.Errorf("gibrun: upgrade %s to v%d: %w", name, v, err)
+		}
+	}
+	return data, nil
+}
+
+// splitSchema parses a schema envelope into name, version and payload.
+func splitSchema(data []byte) (string, int, []byte, bool) {
+	if envelopeKind(data) != envelopeSchema {
+		return "", 0, data, false
+	}
+	rest := data[envelopeHeaderLen:]
+	line, payload, ok := bytes.Cut(rest, []byte("\n"))
+	if !ok {
+		return "", 0, data, false
+	}
+	verStr, name, ok := bytes.Cut(line, []byte(" "))
+	version, err := strconv.Atoi(string(verStr))
+	if !ok || err != nil {
+		return "", 0, data, false
+	}
+	return string(name), version, payload, true
+}
+
+// typeName returns the name of v's type with pointers removed.
+func typeName(v any) string {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil {
+		return ""
+	}
+	return t.Name()
+}
@@ -0,0 +1,40 @@
+package gibrun
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Several operations (Claim, Nack, IfVersion, DedupToken, Poll.Vote,
+// InvalidateTag and Atomic) run as Lua scripts. When the capability probe
+// reports that EVAL is unavailable, they fall back to plain commands:
+// reads happen under WATCH and writes are committed with MULTI/EXEC.
+//
+// The fallbacks never apply partial writes, but they are weaker than the
+// scripts in two ways: under heavy contention on the same keys they give
+// up with ErrTxConflict after a few retries, and Atomic no longer stops
+// at the first failing step (see AtomicBuilder.Exec).
+
+// fallbackRetries bounds WATCH retries in the scripting fallbacks.
+const fallbackRetries = 3
+
+// scripting reports whether Lua scripts can be used. Probe failures count
+// as available so the script itself reports the problem.
+func (c *Client) scripting(ctx context.Context) bool {
+	info, err := c.serverInfo(ctx)
+	return err != nil || info.Supports(CapScripting)
+}
+
+// watchRetry runs fn with keys watched, retrying when a watched key
+// changes before EXEC. Returns ErrTxConflict after the last retry.
+func (c *Client) watchRetry(ctx context.Context, fn func(tx *redis.Tx) error, keys ...string) error {
+	for attempt := 0; attempt <= fallbackRetries; attempt++ {
+		err := c.rdb.Watch(ctx, fn, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return ErrTxConflict
+}
@@ -0,0 +1,270 @@
+package gibrun
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// IndexOptions configures CreateIndex.
+type IndexOptions struct {
+	// Prefixes selects the keys to index, e.g. "user:". RediSearch indexes
+	// matching keys as they are written, so values stored with
+	// Gib().AsHash() or Kabinet (or Gib().AsJSON() with OnJSON) become
+	// searchable without extra calls.
+	Prefixes []string
+
+	// OnJSON indexes RedisJSON documents instead of hashes.
+	OnJSON bool
+}
+
+// CreateIndex declares a RediSearch index (FT.CREATE) from the `search`
+// tags of schema, a struct. The tag gives the field type - "text",
+// "tag", "numeric" or "geo" - optionally followed by ",sortable". Field
+// names follow the `redis` tags for hash indexes and the `json` tags
+// for JSON indexes. Succeeds if the index already exists; use DropIndex
+// first to change it. Returns ErrUnsupported without the search module.
+//
+// Example:
+//
+//	type User struct {
+//	    Name string `redis:"name" json:"name" search:"text,sortable"`
+//	    City string `redis:"city" json:"city" search:"tag"`
+//	    Age  int    `redis:"age" json:"age" search:"numeric"`
+//	}
+//	err := app.CreateIndex(ctx, "idx:users", User{}, gibrun.IndexOptions{Prefixes: []string{"user:"}})
+func (c *Client) CreateIndex(ctx context.Context, index string, schema any, opts IndexOptions) error {
+	fields, err := searchSchema(schema, opts.OnJSON)
+	if err != nil {
+		return err
+	}
+
+	createOpts := &redis.FTCreateOptions{OnHash: !opts.OnJSON, OnJSON: opts.OnJSON}
+	for _, p := range opts.Prefixes {
+		createOpts.Prefix = append(createOpts.Prefix, p)
+	}
+	err = c.rdb.FTCreate(ctx, index, createOpts, fields...).Err()
+	if err != nil && strings.Contains(err.Error(), "Index already exists") {
+		return nil
+	}
+	return moduleError(err, "search")
+}
+
+// DropIndex removes a RediSearch index, keeping the indexed keys.
+func (c *Client) DropIndex(ctx context.Context, index string) error {
+	return moduleError(c.rdb.FTDropIndex(ctx, index).Err(), "search")
+}
+
+// searchSchema builds FT.CREATE fields from the search tags of schema.
+func searchSchema(schema any, onJSON bool) ([]*redis.FieldSchema, error) {
+	t := reflect.TypeOf(schema)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("gibrun: CreateIndex needs a struct schema, got %T", schema)
+	}
+
+	var fields []*redis.FieldSchema
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("search")
+		if !ok || !sf.IsExported() {
+			continue
+		}
+		kind, opts, _ := strings.Cut(tag, ",")
+
+		f := &redis.FieldSchema{Sortable: opts == "sortable"}
+		switch kind {
+		case "text":
+			f.FieldType = redis.SearchFieldTypeText
+		case "tag":
+			f.FieldType = redis.SearchFieldTypeTag
+		case "numeric":
+			f.FieldType = redis.SearchFieldTypeNumeric
+		case "geo":
+			f.FieldType = redis.SearchFieldTypeGeo
+		default:
+			return nil, fmt.Errorf("gibrun: field %s: unknown search type %q", sf.Name, kind)
+		}
+
+		if onJSON {
+			name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if name == "" {
+				name = sf.Name
+			}
+			f.FieldName, f.As = "$."+name, name
+		} else {
+			name, _, ok := hashTag(sf)
+			if !ok {
+				return nil, fmt.Errorf("gibrun: field %s: search fields of hash indexes need a redis tag", sf.Name)
+			}
+			f.FieldName = name
+		}
+		fields = append(fields, f)
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("gibrun: %s has no search tags", t)
+	}
+	return fields, nil
+}
+
+// SearchBuilder provides a fluent API for RediSearch queries.
+type SearchBuilder struct {
+	ctx    context.Context
+	client *Client
+	index  string
+	query  string
+	codec  Codec
+
+	offset, limit int64
+	sortBy        string
+	ascending     bool
+}
+
+// Search starts a query on a RediSearch index, see CreateIndex.
+//
+// Example:
+//
+//	var users []User
+//	total, err := app.Search(ctx, "idx:users").
+//	    Query("@city:{Jakarta} @age:[18 +inf]").
+//	    SortBy("name", true).
+//	    Limit(0, 20).
+//	    Bind(&users)
+func (c *Client) Search(ctx context.Context, index string) *SearchBuilder {
+	return &SearchBuilder{
+		ctx:    ctx,
+		client: c,
+		index:  index,
+		query:  "*",
+		limit:  10,
+	}
+}
+
+// Query sets the RediSearch query. Default is "*", every document.
+func (b *SearchBuilder) Query(q string) *SearchBuilder {
+	b.query = q
+	return b
+}
+
+// Limit returns n results starting at offset. Default is the first 10.
+func (b *SearchBuilder) Limit(offset, n int64) *SearchBuilder {
+	b.offset, b.limit = offset, n
+	return b
+}
+
+// SortBy orders results by a sortable field.
+func (b *SearchBuilder) SortBy(field string, ascending bool) *SearchBuilder {
+	b.sortBy, b.ascending = field, ascending
+	return b
+}
+
+// Codec overrides the client codec for nested hash field values.
+func (b *SearchBuilder) Codec(c Codec) *SearchBuilder {
+	b.codec = c
+	return b
+}
+
+// Bind decodes the matching documents into dest, a pointer to a slice
+// of structs or string-keyed maps, and returns the total number of
+// matches (which may exceed the Limit).
+func (b *SearchBuilder) Bind(dest any) (int64, error) {
+	rv := reflect.ValueOf(dest)
+	if dest == nil || rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return 0, ErrNilPointer
+	}
+	s := rv.Elem()
+	if s.Kind() != reflect.Slice {
+		return 0, fmt.Errorf("gibrun: Search Bind needs a pointer to a slice, got %T", dest)
+	}
+
+	args := []any{"FT.SEARCH", b.index, b.query}
+	if b.sortBy != "" {
+		order := "DESC"
+		if b.ascending {
+			order = "ASC"
+		}
+		args = append(args, "SORTBY", b.sortBy, order)
+	}
+	args = append(args, "LIMIT", b.offset, b.limit)
+
+	reply, err := b.client.rdb.Do(b.ctx, args...).Result()
+	if err != nil {
+		return 0, moduleError(err, "search")
+	}
+	total, docs, err := parseSearchReply(reply)
+	if err != nil {
+		return 0, err
+	}
+
+	out := reflect.MakeSlice(s.Type(), len(docs), len(docs))
+	for i, doc := range docs {
+		elem := out.Index(i)
+		if raw, ok := doc.fields["$"]; ok && len(doc.fields) == 1 {
+			err = json.Unmarshal([]byte(raw), elem.Addr().Interface())
+		} else {
+			err = b.client.enc.bindHash(doc.fields, elem, b.codec)
+		}
+		if err != nil {
+			return 0, fmt.Errorf("gibrun: decode %s: %w", doc.id, err)
+		}
+	}
+	s.Set(out)
+	return total, nil
+}
+
+// searchDoc is one FT.SEARCH result.
+type searchDoc struct {
+	id     string
+	fields map[string]string
+}
+
+// parseSearchReply reads an FT.SEARCH reply in either protocol: a RESP2
+// array of [total, id, fields, id, fields, ...] or a RESP3 map.
+func parseSearchReply(reply any) (int64, []searchDoc, error) {
+	switch r := reply.(type) {
+	case []any:
+		if len(r) == 0 {
+			return 0, nil, nil
+		}
+		total, _ := r[0].(int64)
+		var docs []searchDoc
+		for i := 1; i+1 < len(r); i += 2 {
+			id, _ := r[i].(string)
+			pairs, _ := r[i+1].([]any)
+			docs = append(docs, searchDoc{id: id, fields: searchFields(pairs)})
+		}
+		return total, docs, nil
+
+	case map[any]any:
+		total, _ := r["total_results"].(int64)
+		results, _ := r["results"].([]any)
+		docs := make([]searchDoc, 0, len(results))
+		for _, res := range results {
+			m, _ := res.(map[any]any)
+			id, _ := m["id"].(string)
+			attrs, _ := m["extra_attributes"].(map[any]any)
+			fields := make(map[string]string, len(attrs))
+			for k, v := range attrs {
+				fields[fmt.Sprint(k)] = fmt.Sprint(v)
+			}
+			docs = append(docs, searchDoc{id: id, fields: fields})
+		}
+		return total, docs, nil
+	}
+	return 0, nil, fmt.Errorf("gibrun: unexpected FT.SEARCH reply %T", reply)
+}
+
+// searchFields converts a RESP2 field/value list into a map.
+func searchFields(pairs []any) map[string]string {
+	fields := make(map[string]string, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		fields[fmt.Sprint(pairs[i])] = fmt.Sprint(pairs[i+1])
+	}
+	return fields
+}
@@ -0,0 +1,137 @@
+package gibrun
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// SelfTestStep is the outcome of a single self-test stage.
+type SelfTestStep struct {
+	// Name is "ping", "write", "read", "expire" or "delete".
+	Name string
+
+	// Duration is how long the stage took.
+	Duration time.Duration
+
+	// Err is the failure, nil if the stage passed.
+	Err error
+}
+
+// SelfTestResult is the structured outcome of SelfTest.
+type SelfTestResult struct {
+	// OK is true if every stage passed.
+	OK bool
+
+	// RoundTrip is the measured PING latency.
+	RoundTrip time.Duration
+
+	// Steps lists every stage in order. Stages after a failure are skipped.
+	Steps []SelfTestStep
+}
+
+// selfTestProbe is the value written and read back by SelfTest.
+type selfTestProbe struct {
+	Token string    `json:"token"`
+	At    time.Time `json:"at"`
+}
+
+// SelfTest writes, reads, expires and deletes a probe key, measuring
+// round-trip latency and verifying that the configured codec and
+// encryption round-trip correctly. Use it for boot-time diagnostics.
+// The returned error is the first failing stage, if any.
+//
+// Example:
+//
+//	result, err := app.SelfTest(ctx)
+//	if err != nil {
+//	    log.Fatalf("cache self-test failed: %v", err)
+//	}
+//	log.Printf("cache ok, rtt=%s", result.RoundTrip)
+func (c *Client) SelfTest(ctx context.Context) (*SelfTestResult, error) {
+	result := &SelfTestResult{}
+
+	token := make([]byte, 8)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+	key := "gibrun:selftest:" + hex.EncodeToString(token)
+	probe := selfTestProbe{Token: hex.EncodeToString(token), At: c.clock.Now().UTC()}
+	defer c.rdb.Del(context.WithoutCancel(ctx), key)
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"ping", func() error {
+			return c.rdb.Ping(ctx).Err()
+		}},
+		{"write", func() error {
+			return c.Gib(ctx, key).Value(probe).TTL(time.Minute).Exec()
+		}},
+		{"read", func() error {
+			var got selfTestProbe
+			found, err := c.Run(ctx, key).Bind(&got)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("probe key not found after write")
+			}
+			if got.Token != probe.Token || !got.At.Equal(probe.At) {
+				return fmt.Errorf("probe value did not round-trip: wrote %+v, read %+v", probe, got)
+			}
+			return nil
+		}},
+		{"expire", func() error {
+			// Checked via PTTL rather than by waiting, so a ManualClock
+			// client doesn't wait on the server clock
+			if err := c.rdb.PExpire(ctx, key, 50*time.Millisecond).Err(); err != nil {
+				return err
+			}
+			ttl, err := c.rdb.PTTL(ctx, key).Result()
+			if err != nil {
+				return err
+			}
+			if ttl <= 0 || ttl > 50*time.Millisecond {
+				return fmt.Errorf("probe key expiry not applied, PTTL is %v", ttl)
+			}
+			return nil
+		}},
+		{"delete", func() error {
+			if err := c.Gib(ctx, key).Value(probe).TTL(time.Minute).Exec(); err != nil {
+				return err
+			}
+			if err := c.Del(ctx, key); err != nil {
+				return err
+			}
+			exists, err := c.Exists(ctx, key)
+			if err != nil {
+				return err
+			}
+			if exists {
+				return fmt.Errorf("probe key still exists after delete")
+			}
+			return nil
+		}},
+	}
+
+	for _, step := range steps {
+		start := c.clock.Now()
+		err := step.fn()
+		s := SelfTestStep{Name: step.name, Duration: c.clock.Now().Sub(start), Err: err}
+		result.Steps = append(result.Steps, s)
+
+		if step.name == "ping" {
+			result.RoundTrip = s.Duration
+		}
+		if err != nil {
+			return result, fmt.Errorf("gibrun: self-test %s failed: %w", step.name, err)
+		}
+	}
+
+	result.OK = true
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package gibrun
+
+import (
+	"context"
+	"sync"
+)
+
+// Session gives read-your-writes consistency on a ReplicatedClient for
+// the lifetime of one request: keys written through the session are read
+// back from the primary, everything else still goes to replicas. Create
+// one per request; it is safe for concurrent use.
+type Session struct {
+	client *ReplicatedClient
+
+	mu      sync.RWMutex
+	written map[string]struct{}
+}
+
+// Session starts a read-your-writes session.
+//
+// Example:
+//
+//	s := app.Session()
+//	s.Gib(ctx, "user:123").Value(user).Exec()
+//	found, err := s.Run(ctx, "user:123").Bind(&user) // primary, never stale
+//	found, err = s.Run(ctx, "user:456").Bind(&other) // replica
+func (r *ReplicatedClient) Session() *Session {
+	return &Session{
+		client:  r,
+		written: make(map[string]struct{}),
+	}
+}
+
+// mark records keys as written in this session.
+func (s *Session) mark(keys ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range keys {
+		s.written[key] = struct{}{}
+	}
+}
+
+// reader returns the client that must serve reads of key.
+func (s *Session) reader(key string) *Client {
+	s.mu.RLock()
+	_, ok := s.written[key]
+	s.mu.RUnlock()
+	if ok {
+		return s.client.Primary()
+	}
+	return s.client.Replica()
+}
+
+// Gib starts a data storage operation on the primary and pins later
+// reads of key to the primary.
+func (s *Session) Gib(ctx context.Context, key string) *GibBuilder {
+	s.mark(key)
+	return s.client.Gib(ctx, key)
+}
+
+// Sprint starts an atomic operation on the primary and pins later reads
+// of key to the primary.
+func (s *Session) Sprint(ctx context.Context, key string) *SprintBuilder {
+	s.mark(key)
+	return s.client.Sprint(ctx, key)
+}
+
+// Del deletes keys on the primary and pins later reads of them to the
+// primary, so they don't reappear from a lagging replica.
+func (s *Session) Del(ctx context.Context, keys ...string) error {
+	s.mark(keys...)
+	return s.client.Del(ctx, keys...)
+}
+
+// Run starts a data retrieval operation, on the primary for keys written
+// in this session and on a replica otherwise.
+func (s *Session) Run(ctx context.Context, key string) *RunBuilder {
+	b := s.reader(key).Run(ctx, key)
+	b.writer = s.client.Primary()
+	return b
+}
+
+// Exists checks if a key exists, following the same routing as Run.
+func (s *Session) Exists(ctx context.Context, key string) (bool, error) {
+	return s.reader(key).Exists(ctx, key)
+}
+
+// Written returns the keys written in this session.
+func (s *Session) Written() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	keys := make([]string, 0, len(s.written))
+	for key := range s.written {
+		keys = append(keys, key)
+	}
+	return keys
+}
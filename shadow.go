@@ -0,0 +1,262 @@
+package gibrun
+
+import (
+	"bytes"
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Target is a gibrun client that can receive mirrored traffic or take
+// part in cross-deployment tooling. Both *Client and *ClusterClient satisfy it.
+type Target interface {
+	cmdable() redis.Cmdable
+	scanBatches(ctx context.Context, opts ScanOptions, fn func(keys []string) error) error
+	encoder() *encoding
+}
+
+func (c *Client) cmdable() redis.Cmdable        { return c.rdb }
+func (c *ClusterClient) cmdable() redis.Cmdable { return c.rdb }
+func (c *Client) encoder() *encoding            { return &c.enc }
+func (c *ClusterClient) encoder() *encoding     { return &c.enc }
+
+// ShadowWriteConfig configures mirroring of Gib writes to a second deployment.
+type ShadowWriteConfig struct {
+	// Target receives the mirrored writes (e.g. the new cluster).
+	Target Target
+
+	// Percent of writes to mirror, from 0 to 100.
+	Percent float64
+
+	// MaxInFlight bounds concurrent mirrored writes. Writes beyond it are
+	// dropped so the shadow never slows down the primary path.
+	// Default is 100.
+	MaxInFlight int
+
+	// Timeout bounds each mirrored write. Default is 1 second.
+	Timeout time.Duration
+
+	// OnError is called when a mirrored write fails or is dropped.
+	OnError func(key string, err error)
+}
+
+// shadowWriter mirrors writes asynchronously.
+type shadowWriter struct {
+	cfg ShadowWriteConfig
+	sem chan struct{}
+}
+
+// ShadowWrites mirrors a percentage of Gib writes to another deployment
+// asynchronously, to validate a target under real write traffic before
+// migrating reads. Call with a nil Target to stop mirroring.
+//
+// Example:
+//
+//	app.ShadowWrites(gibrun.ShadowWriteConfig{
+//	    Target:  newCluster,
+//	    Percent: 10,
+//	    OnError: func(key string, err error) { log.Printf("shadow %s: %v", key, err) },
+//	})
+func (c *Client) ShadowWrites(cfg ShadowWriteConfig) {
+	if cfg.Target == nil || cfg.Percent <= 0 {
+		c.shadowW.Store(nil)
+		return
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 100
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+	c.shadowW.Store(&shadowWriter{
+		cfg: cfg,
+		sem: make(chan struct{}, cfg.MaxInFlight),
+	})
+}
+
+// mirrorWrite sends a sampled copy of a completed write to the shadow target.
+func (c *Client) mirrorWrite(key string, data []byte, ttl time.Duration) {
+	sw := c.shadowW.Load()
+	if sw == nil || rand.Float64()*100 >= sw.cfg.Percent {
+		return
+	}
+
+	select {
+	case sw.sem <- struct{}{}:
+	default:
+		if sw.cfg.OnError != nil {
+			sw.cfg.OnError(key, ErrShadowDropped)
+		}
+		return
+	}
+
+	go func() {
+		defer func() { <-sw.sem }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), sw.cfg.Timeout)
+		defer cancel()
+
+		if err := sw.cfg.Target.cmdable().Set(ctx, key, data, ttl).Err(); err != nil && sw.cfg.OnError != nil {
+			sw.cfg.OnError(key, err)
+		}
+	}()
+}
+
+// ShadowReadConfig configures comparison reads against a second deployment.
+type ShadowReadConfig struct {
+	// Target is the deployment to verify (e.g. a replica or migrated cluster).
+	Target Target
+
+	// Percent of reads to shadow, from 0 to 100.
+	Percent float64
+
+	// MaxInFlight bounds concurrent shadow reads. Default is 100.
+	MaxInFlight int
+
+	// Timeout bounds each shadow comparison. Default is 1 second.
+	Timeout time.Duration
+
+	// CompareTTL also compares remaining TTLs.
+	CompareTTL bool
+
+	// TTLTolerance is the allowed TTL difference when CompareTTL is set.
+	// Default is 1 second.
+	TTLTolerance time.Duration
+
+	// OnMismatch is called when the target serves different data.
+	OnMismatch func(m ShadowMismatch)
+
+	// OnError is called when a shadow read fails or is dropped.
+	OnError func(key string, err error)
+}
+
+// ShadowMismatch describes a difference between primary and shadow reads.
+type ShadowMismatch struct {
+	// Key is the key that was read.
+	Key string
+
+	// Reason is "missing", "unexpected", "value" or "ttl".
+	Reason string
+
+	// PrimaryFound and ShadowFound report key presence on each side.
+	PrimaryFound bool
+	ShadowFound  bool
+
+	// PrimaryTTL and ShadowTTL are the remaining TTLs, set when CompareTTL is enabled.
+	PrimaryTTL time.Duration
+	ShadowTTL  time.Duration
+}
+
+// shadowReader compares sampled reads in the background.
+type shadowReader struct {
+	cfg ShadowReadConfig
+	sem chan struct{}
+}
+
+// ShadowReads issues a sampled copy of each Run against another deployment
+// in the background and reports mismatches, to verify a replica or a
+// post-migration cluster serves identical data. Call with a nil Target to stop.
+//
+// Example:
+//
+//	app.ShadowReads(gibrun.ShadowReadConfig{
+//	    Target:     newCluster,
+//	    Percent:    5,
+//	    CompareTTL: true,
+//	    OnMismatch: func(m gibrun.ShadowMismatch) {
+//	        log.Printf("shadow mismatch on %s: %s", m.Key, m.Reason)
+//	    },
+//	})
+func (c *Client) ShadowReads(cfg ShadowReadConfig) {
+	if cfg.Target == nil || cfg.Percent <= 0 {
+		c.shadowR.Store(nil)
+		return
+	}
+	if cfg.MaxInFlight <= 0 {
+		cfg.MaxInFlight = 100
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = time.Second
+	}
+	if cfg.TTLTolerance <= 0 {
+		cfg.TTLTolerance = time.Second
+	}
+	c.shadowR.Store(&shadowReader{
+		cfg: cfg,
+		sem: make(chan struct{}, cfg.MaxInFlight),
+	})
+}
+
+// shadowRead compares a completed primary read with the shadow target.
+// data holds the raw stored bytes, found reports whether the key existed.
+func (c *Client) shadowRead(key string, data []byte, found bool) {
+	sr := c.shadowR.Load()
+	if sr == nil || rand.Float64()*100 >= sr.cfg.Percent {
+		return
+	}
+
+	select {
+	case sr.sem <- struct{}{}:
+	default:
+		if sr.cfg.OnError != nil {
+			sr.cfg.OnError(key, ErrShadowDropped)
+		}
+		return
+	}
+
+	go func() {
+		defer func() { <-sr.sem }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), sr.cfg.Timeout)
+		defer cancel()
+
+		if err := c.compareShadow(ctx, sr, key, data, found); err != nil && sr.cfg.OnError != nil {
+			sr.cfg.OnError(key, err)
+		}
+	}()
+}
+
+func (c *Client) compareShadow(ctx context.Context, sr *shadowReader, key string, data []byte, found bool) error {
+	shadow := sr.cfg.Target.cmdable()
+
+	other, err := shadow.Get(ctx, key).Bytes()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	otherFound := err == nil
+
+	m := ShadowMismatch{Key: key, PrimaryFound: found, ShadowFound: otherFound}
+	switch {
+	case found && !otherFound:
+		m.Reason = "missing"
+	case !found && otherFound:
+		m.Reason = "unexpected"
+	case found && !bytes.Equal(data, other):
+		m.Reason = "value"
+	}
+
+	if m.Reason == "" && found && sr.cfg.CompareTTL {
+		m.PrimaryTTL, err = c.rdb.PTTL(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		m.ShadowTTL, err = shadow.PTTL(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		diff := m.PrimaryTTL - m.ShadowTTL
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > sr.cfg.TTLTolerance {
+			m.Reason = "ttl"
+		}
+	}
+
+	if m.Reason != "" && sr.cfg.OnMismatch != nil {
+		sr.cfg.OnMismatch(m)
+	}
+	return nil
+}
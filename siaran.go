@@ -0,0 +1,170 @@
+package gibrun
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SubscribeOptions configures a subscription, see SubscribeWith.
+type SubscribeOptions struct {
+	// Buffer bounds the messages waiting for the handler. When it is
+	// full, new messages are dropped and reported to OnError with
+	// ErrSubscriptionOverflow, so a slow handler can't stall the
+	// connection. Default is 100.
+	Buffer int
+
+	// OnError receives decode errors, handler errors and dropped
+	// messages. Errors are ignored when nil.
+	OnError func(channel string, err error)
+}
+
+// Subscription is a running subscription, see Subscribe, PSubscribe and
+// WatchExpirations.
+type Subscription struct {
+	ps   *redis.PubSub
+	done chan struct{}
+	stop func() bool
+
+	once sync.Once
+	err  error
+}
+
+// Publish marshals payload exactly like Gib and publishes it on channel
+// ("Siaran", a broadcast). Returns the number of subscribers that
+// received it.
+//
+// Example:
+//
+//	n, err := app.Publish(ctx, "prices", PriceUpdate{Symbol: "BTC", Price: 64000})
+func (c *Client) Publish(ctx context.Context, channel string, payload any) (int64, error) {
+	if payload == nil {
+		return 0, ErrNilValue
+	}
+	data, err := c.enc.marshal(payload, nil)
+	if err != nil {
+		return 0, err
+	}
+	return c.rdb.Publish(ctx, channel, data).Result()
+}
+
+// Subscribe decodes every message published on channel into a T and
+// calls handler with it, one message at a time, until ctx is canceled or
+// the subscription is closed. It returns once the subscription is
+// active. The connection reconnects and resubscribes on its own;
+// messages published while it is down are lost, as with any Redis
+// pub/sub.
+//
+// Example:
+//
+//	sub, err := gibrun.Subscribe(ctx, app, "prices", func(u PriceUpdate) error {
+//	    ticker.Update(u)
+//	    return nil
+//	})
+//	defer sub.Close()
+func Subscribe[T any](ctx context.Context, client *Client, channel string, handler func(msg T) error) (*Subscription, error) {
+	return SubscribeWith(ctx, client, channel, SubscribeOptions{}, handler)
+}
+
+// SubscribeWith is Subscribe with options.
+func SubscribeWith[T any](ctx context.Context, client *Client, channel string, opts SubscribeOptions, handler func(msg T) error) (*Subscription, error) {
+	return subscribe(ctx, client, client.rdb.Subscribe(ctx, channel), opts, func(_ string, msg T) error {
+		return handler(msg)
+	})
+}
+
+// PSubscribe is Subscribe for every channel matching pattern (a glob,
+// e.g. "invalidate:*"). The handler gets the concrete channel, so one
+// subscription can route by channel name. The subscription survives
+// Redis restarts and failovers: the connection is re-established and the
+// pattern subscribed again, without the application noticing beyond the
+// messages lost meanwhile.
+//
+// Example:
+//
+//	sub, err := gibrun.PSubscribe(ctx, app, "invalidate:*", func(channel string, keys []string) error {
+//	    switch strings.TrimPrefix(channel, "invalidate:") {
+//	    case "users":
+//	        userCache.Drop(keys...)
+//	    case "products":
+//	        productCache.Drop(keys...)
+//	    }
+//	    return nil
+//	})
+//	defer sub.Close()
+func PSubscribe[T any](ctx context.Context, client *Client, pattern string, handler func(channel string, msg T) error) (*Subscription, error) {
+	return PSubscribeWith(ctx, client, pattern, SubscribeOptions{}, handler)
+}
+
+// PSubscribeWith is PSubscribe with options.
+func PSubscribeWith[T any](ctx context.Context, client *Client, pattern string, opts SubscribeOptions, handler func(channel string, msg T) error) (*Subscription, error) {
+	return subscribe(ctx, client, client.rdb.PSubscribe(ctx, pattern), opts, handler)
+}
+
+// subscribe waits for ps to be active, then feeds its decoded messages
+// to handler through a bounded buffer.
+func subscribe[T any](ctx context.Context, client *Client, ps *redis.PubSub, opts SubscribeOptions, handler func(channel string, msg T) error) (*Subscription, error) {
+	if opts.Buffer <= 0 {
+		opts.Buffer = 100
+	}
+	onError := opts.OnError
+	if onError == nil {
+		onError = func(string, error) {}
+	}
+
+	if _, err := ps.Receive(ctx); err != nil {
+		ps.Close()
+		return nil, err
+	}
+
+	s := &Subscription{ps: ps, done: make(chan struct{})}
+	s.stop = context.AfterFunc(ctx, func() { s.close() })
+
+	buf := make(chan *redis.Message, opts.Buffer)
+	go func() {
+		defer close(buf)
+		for msg := range ps.Channel() {
+			select {
+			case buf <- msg:
+			default:
+				onError(msg.Channel, ErrSubscriptionOverflow)
+			}
+		}
+	}()
+
+	go func() {
+		defer close(s.done)
+		for msg := range buf {
+			var v T
+			if err := client.enc.unmarshal([]byte(msg.Payload), &v, nil); err != nil {
+				onError(msg.Channel, err)
+				continue
+			}
+			if err := handler(msg.Channel, v); err != nil {
+				onError(msg.Channel, err)
+			}
+		}
+	}()
+
+	return s, nil
+}
+
+// Close unsubscribes and waits for the handler to finish the messages
+// already buffered. Must not be called from the handler.
+func (s *Subscription) Close() error {
+	s.stop()
+	return s.close()
+}
+
+func (s *Subscription) close() error {
+	s.once.Do(func() { s.err = s.ps.Close() })
+	<-s.done
+	return s.err
+}
+
+// Done is closed once the subscription has stopped and every buffered
+// message was handled.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
@@ -3,6 +3,8 @@ package gibrun
 import (
 	"context"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
 // SprintBuilder provides a fluent API for atomic Redis operations.
@@ -11,6 +13,10 @@ type SprintBuilder struct {
 	ctx    context.Context
 	client *Client
 	key    string
+
+	// dedupToken makes counter updates idempotent, see DedupToken.
+	dedupToken  string
+	dedupWindow time.Duration
 }
 
 // Incr increments the value by 1 and returns the new value.
@@ -20,6 +26,9 @@ type SprintBuilder struct {
 //
 //	newCount, err := app.Sprint(ctx, "counter:visitors").Incr()
 func (b *SprintBuilder) Incr() (int64, error) {
+	if b.dedupToken != "" {
+		return b.dedupIncrBy(1)
+	}
 	return b.client.rdb.Incr(b.ctx, b.key).Result()
 }
 
@@ -30,9 +39,68 @@ func (b *SprintBuilder) Incr() (int64, error) {
 //
 //	newCount, err := app.Sprint(ctx, "counter:score").IncrBy(10)
 func (b *SprintBuilder) IncrBy(n int64) (int64, error) {
+	if b.dedupToken != "" {
+		return b.dedupIncrBy(n)
+	}
 	return b.client.rdb.IncrBy(b.ctx, b.key, n).Result()
 }
 
+// incrWithTTLScript increments the counter and sets its TTL if it has
+// none, so the window starts at the first increment and a crash can't
+// leave an immortal counter.
+//
+// KEYS[1] = counter
+// ARGV[1] = increment, ARGV[2] = TTL in milliseconds
+var incrWithTTLScript = redis.NewScript(`
+local n = redis.call('INCRBY', KEYS[1], ARGV[1])
+if redis.call('PTTL', KEYS[1]) == -1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return n
+`)
+
+// IncrWithTTL increments the value by 1 and, on the first increment,
+// sets it to expire after window - atomically, in one script. Later
+// increments keep the original expiry, giving fixed-window counters.
+//
+// Example:
+//
+//	hits, err := app.Sprint(ctx, "ratelimit:user:123").IncrWithTTL(time.Minute)
+func (b *SprintBuilder) IncrWithTTL(window time.Duration) (int64, error) {
+	return b.IncrByWithTTL(1, window)
+}
+
+// IncrByWithTTL is IncrWithTTL with a custom increment.
+func (b *SprintBuilder) IncrByWithTTL(n int64, window time.Duration) (int64, error) {
+	if !b.client.scripting(b.ctx) {
+		return b.incrWithTTLFallback(n, window)
+	}
+	return incrWithTTLScript.Run(b.ctx, b.client.rdb, []string{b.key}, n, window.Milliseconds()).Int64()
+}
+
+// incrWithTTLFallback mirrors incrWithTTLScript with WATCH/MULTI.
+func (b *SprintBuilder) incrWithTTLFallback(n int64, window time.Duration) (int64, error) {
+	var val int64
+	err := b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		ttl, err := tx.PTTL(b.ctx, b.key).Result()
+		if err != nil {
+			return err
+		}
+		var incr *redis.IntCmd
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			incr = pipe.IncrBy(b.ctx, b.key, n)
+			// -2 missing, -1 no expiry
+			if ttl < 0 {
+				pipe.PExpire(b.ctx, b.key, window)
+			}
+			return nil
+		})
+		val = incr.Val()
+		return err
+	}, b.key)
+	return val, err
+}
+
 // Decr decrements the value by 1 and returns the new value.
 // Creates the key with value -1 if it doesn't exist.
 //
@@ -40,6 +108,9 @@ func (b *SprintBuilder) IncrBy(n int64) (int64, error) {
 //
 //	newCount, err := app.Sprint(ctx, "counter:stock").Decr()
 func (b *SprintBuilder) Decr() (int64, error) {
+	if b.dedupToken != "" {
+		return b.dedupIncrBy(-1)
+	}
 	return b.client.rdb.Decr(b.ctx, b.key).Result()
 }
 
@@ -49,6 +120,9 @@ func (b *SprintBuilder) Decr() (int64, error) {
 //
 //	newCount, err := app.Sprint(ctx, "counter:balance").DecrBy(100)
 func (b *SprintBuilder) DecrBy(n int64) (int64, error) {
+	if b.dedupToken != "" {
+		return b.dedupIncrBy(-n)
+	}
 	return b.client.rdb.DecrBy(b.ctx, b.key, n).Result()
 }
 
@@ -63,14 +137,74 @@ func (b *SprintBuilder) IncrByFloat(n float64) (float64, error) {
 }
 
 // Get returns the current value as int64.
-// Returns 0 if the key doesn't exist.
+// Returns 0 if the key doesn't exist; connection and parse errors are
+// returned. Use GetOrErr to tell a missing key from a zero counter.
 func (b *SprintBuilder) Get() (int64, error) {
-	val, err := b.client.rdb.Get(b.ctx, b.key).Int64()
+	val, _, err := b.GetOrErr()
+	return val, err
+}
+
+// GetOrErr returns the current value and whether the key exists.
+// Returns (0, false, nil) if the key doesn't exist.
+//
+// Example:
+//
+//	n, exists, err := app.Sprint(ctx, "stats:visits").GetOrErr()
+func (b *SprintBuilder) GetOrErr() (int64, bool, error) {
+	return counterValue(b.client.rdb.Get(b.ctx, b.key))
+}
+
+// GetFloat returns the current value of a float counter, pairing with
+// IncrByFloat. Returns 0 if the key doesn't exist.
+//
+// Example:
+//
+//	price, err := app.Sprint(ctx, "price:btc").GetFloat()
+func (b *SprintBuilder) GetFloat() (float64, error) {
+	return floatCounterValue(b.client.rdb.Get(b.ctx, b.key))
+}
+
+// ResetAndGet returns the current value and zeroes the counter in one
+// atomic step, so increments racing the reset land in the next period
+// instead of being lost. Uses GETDEL on Redis 6.2+ and GETSET 0 on older
+// servers; either way the counter's TTL is dropped.
+// Returns 0 if the key doesn't exist.
+//
+// Example:
+//
+//	// periodic flusher
+//	n, err := app.Sprint(ctx, "stats:visits").ResetAndGet()
+//	db.AddVisits(n)
+func (b *SprintBuilder) ResetAndGet() (int64, error) {
+	var cmd *redis.StringCmd
+	if b.client.requireVersion(b.ctx, "GETDEL", Version{Major: 6, Minor: 2}) == nil {
+		cmd = b.client.rdb.GetDel(b.ctx, b.key)
+	} else {
+		cmd = b.client.rdb.GetSet(b.ctx, b.key, 0)
+	}
+	val, _, err := counterValue(cmd)
+	return val, err
+}
+
+// counterValue parses a GET reply holding a counter.
+func counterValue(cmd *redis.StringCmd) (int64, bool, error) {
+	val, err := cmd.Int64()
 	if err != nil {
-		// Key doesn't exist, return 0
+		if err == redis.Nil {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	return val, true, nil
+}
+
+// floatCounterValue parses a GET reply holding a float counter.
+func floatCounterValue(cmd *redis.StringCmd) (float64, error) {
+	val, err := cmd.Float64()
+	if err == redis.Nil {
 		return 0, nil
 	}
-	return val, nil
+	return val, err
 }
 
 // SetWithTTL sets the counter to a specific value with TTL.
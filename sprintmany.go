@@ -0,0 +1,92 @@
+package gibrun
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// SprintManyBuilder queues counter updates on many keys and sends them in
+// a single round trip. See Pipeline to mix counters with other writes.
+type SprintManyBuilder struct {
+	ctx    context.Context
+	client *Client
+	ops    []sprintManyOp
+}
+
+// sprintManyOp is a single queued counter update.
+type sprintManyOp struct {
+	key string
+	n   int64
+}
+
+// SprintMany starts a batch of counter updates.
+// Bumping a dozen counters per event costs one round trip instead of twelve.
+//
+// Example:
+//
+//	vals, err := app.SprintMany(ctx).
+//	    Incr("stats:events").
+//	    IncrBy("stats:bytes", 512).
+//	    Decr("stats:pending").
+//	    Exec()
+//	log.Println(vals["stats:events"])
+func (c *Client) SprintMany(ctx context.Context) *SprintManyBuilder {
+	return &SprintManyBuilder{
+		ctx:    ctx,
+		client: c,
+	}
+}
+
+// Incr queues incrementing key by 1.
+func (b *SprintManyBuilder) Incr(key string) *SprintManyBuilder {
+	return b.IncrBy(key, 1)
+}
+
+// IncrBy queues incrementing key by n.
+func (b *SprintManyBuilder) IncrBy(key string, n int64) *SprintManyBuilder {
+	b.ops = append(b.ops, sprintManyOp{key: key, n: n})
+	return b
+}
+
+// Decr queues decrementing key by 1.
+func (b *SprintManyBuilder) Decr(key string) *SprintManyBuilder {
+	return b.IncrBy(key, -1)
+}
+
+// DecrBy queues decrementing key by n.
+func (b *SprintManyBuilder) DecrBy(key string, n int64) *SprintManyBuilder {
+	return b.IncrBy(key, -n)
+}
+
+// Len returns the number of queued updates.
+func (b *SprintManyBuilder) Len() int {
+	return len(b.ops)
+}
+
+// Exec sends all queued updates in one pipelined round trip and returns
+// the new value of every key. A key updated more than once maps to its
+// value after the last update. Updates are not atomic as a group: on
+// error, some of them may have been applied.
+func (b *SprintManyBuilder) Exec() (map[string]int64, error) {
+	if len(b.ops) == 0 {
+		return map[string]int64{}, nil
+	}
+
+	cmds := make([]*redis.IntCmd, len(b.ops))
+	_, err := b.client.rdb.Pipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		for i, op := range b.ops {
+			cmds[i] = pipe.IncrBy(b.ctx, op.key, op.n)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make(map[string]int64, len(b.ops))
+	for i, op := range b.ops {
+		vals[op.key] = cmds[i].Val()
+	}
+	return vals, nil
+}
@@ -0,0 +1,142 @@
+package gibrun
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxStatsPrefixes bounds the number of tracked prefixes; keys beyond it
+// are counted under the "*" prefix.
+const maxStatsPrefixes = 256
+
+// PrefixStats reports cache effectiveness for keys sharing a prefix.
+// The prefix is the key up to and including its first ':' ("user:" for
+// "user:123"); keys without a ':' are counted under "".
+type PrefixStats struct {
+	Prefix string
+
+	// Hits and Misses count Run reads that found or didn't find the key.
+	Hits   int64
+	Misses int64
+
+	// BindErrors counts reads that failed, in Redis or while decoding.
+	BindErrors int64
+
+	// Writes and WriteErrors count Gib Exec calls.
+	Writes      int64
+	WriteErrors int64
+
+	// ReadLatency and WriteLatency are the average operation latencies.
+	ReadLatency  time.Duration
+	WriteLatency time.Duration
+}
+
+// HitRatio returns Hits / (Hits + Misses), or 0 before any read.
+func (s PrefixStats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Stats returns hit, miss, error and latency counters per key prefix,
+// sorted by prefix. Counters accumulate from client creation.
+//
+// Example:
+//
+//	for _, s := range app.Stats() {
+//	    log.Printf("%s hit ratio %.2f, avg read %v", s.Prefix, s.HitRatio(), s.ReadLatency)
+//	}
+func (c *Client) Stats() []PrefixStats {
+	return c.stats.snapshot()
+}
+
+// statsRecorder holds counters per prefix.
+type statsRecorder struct {
+	prefixes sync.Map // string -> *prefixCounters
+	count    atomic.Int64
+}
+
+type prefixCounters struct {
+	hits, misses, readErrors atomic.Int64
+	writes, writeErrors      atomic.Int64
+	readNanos, writeNanos    atomic.Int64
+}
+
+// counters returns the counters for key's prefix, creating them on first use.
+func (r *statsRecorder) counters(key string) *prefixCounters {
+	prefix := ""
+	if i := strings.IndexByte(key, ':'); i >= 0 {
+		prefix = key[:i+1]
+	}
+	if pc, ok := r.prefixes.Load(prefix); ok {
+		return pc.(*prefixCounters)
+	}
+	if r.count.Load() >= maxStatsPrefixes {
+		prefix = "*"
+	}
+	pc, loaded := r.prefixes.LoadOrStore(prefix, &prefixCounters{})
+	if !loaded {
+		r.count.Add(1)
+	}
+	return pc.(*prefixCounters)
+}
+
+// read records a read that took d.
+func (r *statsRecorder) read(key string, found bool, err error, d time.Duration) {
+	pc := r.counters(key)
+	switch {
+	case err != nil:
+		pc.readErrors.Add(1)
+	case found:
+		pc.hits.Add(1)
+	default:
+		pc.misses.Add(1)
+	}
+	pc.readNanos.Add(int64(d))
+}
+
+// bindError records a read that fetched data but failed to decode it.
+func (r *statsRecorder) bindError(key string) {
+	pc := r.counters(key)
+	pc.hits.Add(-1)
+	pc.readErrors.Add(1)
+}
+
+// write records a write that took d.
+func (r *statsRecorder) write(key string, err error, d time.Duration) {
+	pc := r.counters(key)
+	pc.writes.Add(1)
+	if err != nil {
+		pc.writeErrors.Add(1)
+	}
+	pc.writeNanos.Add(int64(d))
+}
+
+func (r *statsRecorder) snapshot() []PrefixStats {
+	var out []PrefixStats
+	r.prefixes.Range(func(k, v any) bool {
+		pc := v.(*prefixCounters)
+		s := PrefixStats{
+			Prefix:      k.(string),
+			Hits:        pc.hits.Load(),
+			Misses:      pc.misses.Load(),
+			BindErrors:  pc.readErrors.Load(),
+			Writes:      pc.writes.Load(),
+			WriteErrors: pc.writeErrors.Load(),
+		}
+		if reads := s.Hits + s.Misses + s.BindErrors; reads > 0 {
+			s.ReadLatency = time.Duration(pc.readNanos.Load() / reads)
+		}
+		if s.Writes > 0 {
+			s.WriteLatency = time.Duration(pc.writeNanos.Load() / s.Writes)
+		}
+		out = append(out, s)
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].Prefix < out[j].Prefix })
+	return out
+}
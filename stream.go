@@ -0,0 +1,377 @@
+package gibrun
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// streamField is the entry field holding the marshalled payload.
+const streamField = "data"
+
+// errStreamNoData marks entries not written by StreamBuilder.Add.
+var errStreamNoData = errors.New("gibrun: stream entry has no data field")
+
+// StreamBuilder provides a fluent API for Redis Streams: appending
+// events and consuming them through consumer groups.
+type StreamBuilder struct {
+	ctx    context.Context
+	client *Client
+	key    string
+	codec  Codec
+
+	// Trimming applied on Add and Trim
+	maxLen int64
+	maxAge time.Duration
+
+	// Consume settings
+	block         time.Duration
+	count         int64
+	claimIdle     time.Duration
+	maxDeliveries int64
+}
+
+// StreamMessage is a message handed to a StreamHandler.
+type StreamMessage struct {
+	// ID is the stream entry ID, e.g. "1718000000000-0".
+	ID string
+	// Data is the stored payload; use Bind to decode it.
+	Data []byte
+	// Deliveries counts how many times the message was handed out,
+	// starting at 1.
+	Deliveries int64
+
+	// values holds the raw entry fields.
+	values map[string]any
+
+	enc   *encoding
+	codec Codec
+}
+
+// Bind unmarshals the payload into dest.
+func (m StreamMessage) Bind(dest any) error {
+	if dest == nil {
+		return ErrNilPointer
+	}
+	return m.enc.unmarshal(m.Data, dest, m.codec)
+}
+
+// StreamHandler processes one message. Returning nil acknowledges it;
+// an error leaves it pending so it is redelivered after ClaimIdle.
+type StreamHandler func(ctx context.Context, msg StreamMessage) error
+
+// Stream starts a stream operation.
+//
+// Example:
+//
+//	orders := app.Stream(ctx, "events:orders").MaxLen(100000)
+//	id, err := orders.Add(OrderPlaced{ID: 42})
+//
+//	// in a worker, until ctx is canceled
+//	err = app.Stream(ctx, "events:orders").Consume("billing", hostname,
+//	    func(ctx context.Context, msg gibrun.StreamMessage) error {
+//	        var ev OrderPlaced
+//	        if err := msg.Bind(&ev); err != nil {
+//	            return err
+//	        }
+//	        return bill(ctx, ev)
+//	    })
+func (c *Client) Stream(ctx context.Context, key string) *StreamBuilder {
+	return &StreamBuilder{
+		ctx:           ctx,
+		client:        c,
+		key:           key,
+		block:         5 * time.Second,
+		count:         10,
+		claimIdle:     time.Minute,
+		maxDeliveries: 5,
+	}
+}
+
+// Codec overrides the client codec for this operation.
+func (b *StreamBuilder) Codec(c Codec) *StreamBuilder {
+	b.codec = c
+	return b
+}
+
+// MaxLen caps the stream at about n entries (MAXLEN ~), trimming the
+// oldest ones on Add.
+func (b *StreamBuilder) MaxLen(n int64) *StreamBuilder {
+	b.maxLen = n
+	return b
+}
+
+// MaxAge drops entries older than d (MINID ~) on Add.
+func (b *StreamBuilder) MaxAge(d time.Duration) *StreamBuilder {
+	b.maxAge = d
+	return b
+}
+
+// Block sets how long Consume waits for new messages per read.
+// Default is 5 seconds.
+func (b *StreamBuilder) Block(d time.Duration) *StreamBuilder {
+	b.block = d
+	return b
+}
+
+// Count sets how many messages Consume reads at once. Default is 10.
+func (b *StreamBuilder) Count(n int64) *StreamBuilder {
+	b.count = n
+	return b
+}
+
+// ClaimIdle sets how long a message may stay pending with a consumer
+// before Consume claims it, covering handler errors and crashed
+// consumers. Default is 1 minute; zero disables claiming.
+func (b *StreamBuilder) ClaimIdle(d time.Duration) *StreamBuilder {
+	b.claimIdle = d
+	return b
+}
+
+// MaxDeliveries sets how many times a message is handed out before it is
+// recorded in the client FailureLog (source "stream:<key>:<group>") and
+// acknowledged. Default is 5.
+func (b *StreamBuilder) MaxDeliveries(n int64) *StreamBuilder {
+	b.maxDeliveries = n
+	return b
+}
+
+// Add appends payload, marshalled exactly like Gib, and returns the
+// entry ID. Applies MaxLen and MaxAge trimming.
+func (b *StreamBuilder) Add(payload any) (string, error) {
+	if payload == nil {
+		return "", ErrNilValue
+	}
+	data, err := b.client.enc.marshal(payload, b.codec)
+	if err != nil {
+		return "", err
+	}
+
+	args := &redis.XAddArgs{
+		Stream: b.key,
+		Values: []any{streamField, data},
+	}
+	switch {
+	case b.maxLen > 0:
+		args.MaxLen, args.Approx = b.maxLen, true
+	case b.maxAge > 0:
+		args.MinID, args.Approx = b.minID(), true
+	}
+	return b.client.rdb.XAdd(b.ctx, args).Result()
+}
+
+// Trim applies MaxLen and MaxAge now and returns the number of entries
+// removed, for streams written by other producers.
+func (b *StreamBuilder) Trim() (int64, error) {
+	switch {
+	case b.maxLen > 0:
+		return b.client.rdb.XTrimMaxLenApprox(b.ctx, b.key, b.maxLen, 0).Result()
+	case b.maxAge > 0:
+		return b.client.rdb.XTrimMinIDApprox(b.ctx, b.key, b.minID(), 0).Result()
+	}
+	return 0, nil
+}
+
+// Len returns the number of entries in the stream.
+func (b *StreamBuilder) Len() (int64, error) {
+	return b.client.rdb.XLen(b.ctx, b.key).Result()
+}
+
+// CreateGroup creates a consumer group reading from start ("0" for the
+// whole stream, "$" for new entries only), creating the stream if needed.
+// Succeeds if the group already exists.
+func (b *StreamBuilder) CreateGroup(group, start string) error {
+	err := b.client.rdb.XGroupCreateMkStream(b.ctx, b.key, group, start).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+// Consume reads messages as consumer of group and runs handler for each
+// until the context is canceled, then returns nil. The group is created
+// at the start of the stream if it doesn't exist.
+//
+// Successful messages are acknowledged. Failed ones stay pending and
+// are claimed again, by this or another consumer, once idle for
+// ClaimIdle; after MaxDeliveries they go to the FailureLog. Transient
+// Redis errors are retried after a second; other errors stop the loop.
+func (b *StreamBuilder) Consume(group, consumer string, handler StreamHandler) error {
+	if err := b.CreateGroup(group, "0"); err != nil {
+		return err
+	}
+
+	var lastClaim time.Time
+	for b.ctx.Err() == nil {
+		var msgs []StreamMessage
+		var err error
+		if b.claimIdle > 0 && b.client.clock.Now().Sub(lastClaim) >= b.claimIdle/2 {
+			lastClaim = b.client.clock.Now()
+			msgs, err = b.claim(group, consumer)
+		}
+		if err == nil && len(msgs) == 0 {
+			msgs, err = b.read(group, consumer)
+		}
+		if err != nil {
+			if b.ctx.Err() != nil {
+				break
+			}
+			if !isTransient(err) {
+				return err
+			}
+			b.client.clock.Sleep(time.Second)
+			continue
+		}
+
+		for _, msg := range msgs {
+			if b.ctx.Err() != nil {
+				break
+			}
+			b.handle(group, msg, handler)
+		}
+	}
+	return nil
+}
+
+// read fetches new messages for consumer.
+func (b *StreamBuilder) read(group, consumer string) ([]StreamMessage, error) {
+	streams, err := b.client.rdb.XReadGroup(b.ctx, &redis.XReadGroupArgs{
+		Group:    group,
+		Consumer: consumer,
+		Streams:  []string{b.key, ">"},
+		Count:    b.count,
+		Block:    b.block,
+	}).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var msgs []StreamMessage
+	for _, s := range streams {
+		for _, m := range s.Messages {
+			msgs = append(msgs, b.message(m, 1))
+		}
+	}
+	return msgs, nil
+}
+
+// claim takes over messages idle for ClaimIdle. Uses XAUTOCLAIM on Redis
+// 6.2+ and XPENDING plus XCLAIM on older servers.
+func (b *StreamBuilder) claim(group, consumer string) ([]StreamMessage, error) {
+	var claimed []redis.XMessage
+	if b.client.requireVersion(b.ctx, "XAUTOCLAIM", Version{Major: 6, Minor: 2}) == nil {
+		var err error
+		claimed, _, err = b.client.rdb.XAutoClaim(b.ctx, &redis.XAutoClaimArgs{
+			Stream:   b.key,
+			Group:    group,
+			Consumer: consumer,
+			MinIdle:  b.claimIdle,
+			Start:    "0",
+			Count:    b.count,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		pending, err := b.client.rdb.XPendingExt(b.ctx, &redis.XPendingExtArgs{
+			Stream: b.key,
+			Group:  group,
+			Start:  "-",
+			End:    "+",
+			Count:  b.count * 10,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+		var ids []string
+		for _, p := range pending {
+			if p.Idle >= b.claimIdle && int64(len(ids)) < b.count {
+				ids = append(ids, p.ID)
+			}
+		}
+		if len(ids) == 0 {
+			return nil, nil
+		}
+		claimed, err = b.client.rdb.XClaim(b.ctx, &redis.XClaimArgs{
+			Stream:   b.key,
+			Group:    group,
+			Consumer: consumer,
+			MinIdle:  b.claimIdle,
+			Messages: ids,
+		}).Result()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(claimed) == 0 {
+		return nil, nil
+	}
+
+	// Claiming bumped the delivery counts, read them back
+	pending, err := b.client.rdb.XPendingExt(b.ctx, &redis.XPendingExtArgs{
+		Stream: b.key,
+		Group:  group,
+		Start:  claimed[0].ID,
+		End:    claimed[len(claimed)-1].ID,
+		Count:  int64(len(claimed)),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+	deliveries := make(map[string]int64, len(pending))
+	for _, p := range pending {
+		deliveries[p.ID] = p.RetryCount
+	}
+
+	msgs := make([]StreamMessage, 0, len(claimed))
+	for _, m := range claimed {
+		msgs = append(msgs, b.message(m, deliveries[m.ID]))
+	}
+	return msgs, nil
+}
+
+// handle runs handler on msg, acknowledging it on success or once it
+// has used up its deliveries.
+func (b *StreamBuilder) handle(group string, msg StreamMessage, handler StreamHandler) {
+	err := errStreamNoData
+	if msg.Data != nil {
+		err = handler(b.ctx, msg)
+	}
+	if err != nil && msg.Deliveries < b.maxDeliveries {
+		return
+	}
+	if err != nil {
+		source := "stream:" + b.key + ":" + group
+		err = fmt.Errorf("after %d deliveries: %w", msg.Deliveries, err)
+		b.client.Failures().Record(context.WithoutCancel(b.ctx), source, msg.ID, msg.Data, err)
+	}
+	b.client.rdb.XAck(context.WithoutCancel(b.ctx), b.key, group, msg.ID)
+}
+
+// message converts an entry into a StreamMessage.
+func (b *StreamBuilder) message(m redis.XMessage, deliveries int64) StreamMessage {
+	msg := StreamMessage{
+		ID:         m.ID,
+		Deliveries: max(deliveries, 1),
+		values:     m.Values,
+		enc:        &b.client.enc,
+		codec:      b.codec,
+	}
+	if s, ok := m.Values[streamField].(string); ok {
+		msg.Data = []byte(s)
+	}
+	return msg
+}
+
+// minID returns the MINID for MaxAge.
+func (b *StreamBuilder) minID() string {
+	return strconv.FormatInt(b.client.clock.Now().Add(-b.maxAge).UnixMilli(), 10)
+}
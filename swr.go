@@ -0,0 +1,72 @@
+package gibrun
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StaleWhileRevalidate serves cached values past their soft TTL while
+// refreshing them in the background, keeping p99 latency flat during
+// cache refreshes. The loader's TTL is the soft TTL; values are kept for
+// maxStale longer, and a read inside that stale window returns the old
+// value immediately and triggers one background reload per key. Misses
+// load synchronously, like OrElse.
+//
+// Example:
+//
+//	var page Page
+//	found, err := app.Run(ctx, "page:home").StaleWhileRevalidate(func(ctx context.Context) (any, time.Duration, error) {
+//	    p, err := renderHome(ctx)
+//	    return p, time.Minute, err
+//	}, 10*time.Minute).Bind(&page)
+func (b *RunBuilder) StaleWhileRevalidate(loader LoaderFunc, maxStale time.Duration) *RunBuilder {
+	b.loader = loader
+	b.maxStale = maxStale
+	return b
+}
+
+// bindSWR reads the value with its TTL, refreshing stale entries.
+func (b *RunBuilder) bindSWR(dest any) (bool, error) {
+	var get *redis.StringCmd
+	var pttl *redis.DurationCmd
+	_, err := b.client.rdb.Pipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(b.ctx, b.key)
+		pttl = pipe.PTTL(b.ctx, b.key)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return false, err
+	}
+
+	data, err := b.finishGet(get.Bytes())
+	if err != nil {
+		if err == redis.Nil {
+			if b.negativeHit {
+				return false, nil
+			}
+			return b.load(dest)
+		}
+		return false, err
+	}
+
+	if err := b.client.enc.unmarshal(data, dest, b.codec); err != nil {
+		return false, err
+	}
+
+	// Inside the stale window: serve now, refresh in the background
+	if ttl := pttl.Val(); ttl >= 0 && ttl < b.maxStale {
+		refresh := *b
+		refresh.ctx = context.WithoutCancel(b.ctx)
+		go refresh.reload()
+	}
+	return true, nil
+}
+
+// reload runs the loader and stores the result, deduplicated per key.
+func (b *RunBuilder) reload() {
+	b.client.flights.do(b.key, func() ([]byte, error) {
+		return b.loadAndStore()
+	})
+}
@@ -0,0 +1,196 @@
+package gibrun
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tagPrefix namespaces the tag sets holding member keys.
+const tagPrefix = "gibrun:tag:"
+
+// invalidateBatch is how many member keys one invalidation step deletes.
+const invalidateBatch = 500
+
+// invalidateScript deletes a batch of keys registered in a tag set and
+// removes them from the set, then deletes the chunks of chunked members.
+// Every key is passed in KEYS so the script declares all it touches.
+// Keys that already expired are simply dropped from the set.
+//
+// KEYS[1] = tag set, KEYS[2..n+1] = member keys, KEYS[n+2..] = chunk keys
+// ARGV[1] = n
+var invalidateScript = redis.NewScript(`
+local n = tonumber(ARGV[1])
+local deleted = 0
+for i = 2, n + 1 do
+  deleted = deleted + redis.call('DEL', KEYS[i])
+  redis.call('SREM', KEYS[1], KEYS[i])
+end
+for i = n + 2, #KEYS do
+  redis.call('DEL', KEYS[i])
+end
+return deleted
+`)
+
+// tagScript adds a key to a tag set and keeps the set alive at least as
+// long as its entries: a new set expires with its first entry, a later
+// entry with a longer TTL extends it, and an entry without TTL makes it
+// persistent.
+//
+// KEYS[1] = tag set
+// ARGV[1] = member key, ARGV[2] = entry TTL in milliseconds (0 for none)
+var tagScript = redis.NewScript(`
+local fresh = redis.call('EXISTS', KEYS[1]) == 0
+redis.call('SADD', KEYS[1], ARGV[1])
+local ttl = tonumber(ARGV[2])
+if ttl == 0 then
+  redis.call('PERSIST', KEYS[1])
+  return 1
+end
+local cur = redis.call('PTTL', KEYS[1])
+if fresh or (cur >= 0 and cur < ttl) then
+  redis.call('PEXPIRE', KEYS[1], ttl)
+end
+return 1
+`)
+
+// Tags registers the key under one or more tags at write time, so all
+// entries belonging to an entity can be invalidated together with
+// InvalidateTag instead of scanning.
+//
+// Example:
+//
+//	app.Gib(ctx, "page:dashboard:42").Value(page).Tags("user:42", "org:7").Exec()
+//	app.InvalidateTag(ctx, "org:7") // drops every entry tagged org:7
+func (b *GibBuilder) Tags(tags ...string) *GibBuilder {
+	b.tags = append(b.tags, tags...)
+	return b
+}
+
+// registerTags adds the key to its tag sets after a successful write.
+// Tag sets expire once none of their entries can still exist.
+func (b *GibBuilder) registerTags() error {
+	if len(b.tags) == 0 {
+		return nil
+	}
+	ttl, err := b.client.rdb.PTTL(b.ctx, b.key).Result()
+	if err != nil {
+		return err
+	}
+	// -2: the key is gone (e.g. ExpireAt in the past), nothing to tag
+	if ttl == -2 {
+		return nil
+	}
+	ms := max(ttl.Milliseconds(), 0)
+
+	for _, tag := range b.tags {
+		set := tagPrefix + tag
+		if !b.client.scripting(b.ctx) {
+			err = b.client.tagFallback(b.ctx, set, b.key, ms)
+		} else {
+			err = tagScript.Run(b.ctx, b.client.rdb, []string{set}, b.key, ms).Err()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tagFallback mirrors tagScript with WATCH/MULTI.
+func (c *Client) tagFallback(ctx context.Context, set, key string, ttlMs int64) error {
+	return c.watchRetry(ctx, func(tx *redis.Tx) error {
+		cur, err := tx.PTTL(ctx, set).Result()
+		if err != nil {
+			return err
+		}
+		ttl := time.Duration(ttlMs) * time.Millisecond
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SAdd(ctx, set, key)
+			switch {
+			case ttlMs == 0:
+				pipe.Persist(ctx, set)
+			case cur == -2 || (cur >= 0 && cur < ttl):
+				pipe.PExpire(ctx, set, ttl)
+			}
+			return nil
+		})
+		return err
+	}, set)
+}
+
+// InvalidateTag deletes every key written with the tag and returns how
+// many keys were deleted. Members are deleted in batches, each removed
+// from the tag set in the same atomic step.
+func (c *Client) InvalidateTag(ctx context.Context, tag string) (int64, error) {
+	set := tagPrefix + tag
+	members, err := c.rdb.SMembers(ctx, set).Result()
+	if err != nil {
+		return 0, err
+	}
+	if c.l1 != nil {
+		// The members aren't visible to the L1 hook, drop them explicitly
+		defer l1Hook{l1: c.l1, rdb: c.rdb}.invalidate(ctx, members)
+	}
+
+	var deleted int64
+	for i := 0; i < len(members); i += invalidateBatch {
+		batch := members[i:min(i+invalidateBatch, len(members))]
+		var n int64
+		if !c.scripting(ctx) {
+			n, err = c.invalidateFallback(ctx, set, batch)
+		} else {
+			var chunks []string
+			chunks, err = storedChunks(ctx, c.rdb, batch, 0)
+			if err != nil {
+				return deleted, err
+			}
+			keys := append(append([]string{set}, batch...), chunks...)
+			n, err = invalidateScript.Run(ctx, c.rdb, keys, len(batch)).Int64()
+		}
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+// invalidateFallback mirrors invalidateScript with WATCH/MULTI.
+func (c *Client) invalidateFallback(ctx context.Context, set string, members []string) (int64, error) {
+	var deleted int64
+	err := c.watchRetry(ctx, func(tx *redis.Tx) error {
+		chunks, err := storedChunks(ctx, tx, members, 0)
+		if err != nil {
+			return err
+		}
+		var dels []*redis.IntCmd
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			args := make([]any, len(members))
+			for i, m := range members {
+				dels = append(dels, pipe.Del(ctx, m))
+				args[i] = m
+			}
+			pipe.SRem(ctx, set, args...)
+			if len(chunks) > 0 {
+				pipe.Del(ctx, chunks...)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		deleted = 0
+		for _, d := range dels {
+			deleted += d.Val()
+		}
+		return nil
+	}, members...)
+	return deleted, err
+}
+
+// TagMembers returns the keys currently registered under a tag.
+func (c *Client) TagMembers(ctx context.Context, tag string) ([]string, error) {
+	return c.rdb.SMembers(ctx, tagPrefix+tag).Result()
+}
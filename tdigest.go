@@ -0,0 +1,129 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TDigestBuilder provides a fluent API for RedisBloom t-digest sketches:
+// compact value distributions that many instances can feed into, with
+// percentiles queried directly. Commands return ErrUnsupported without
+// the module.
+type TDigestBuilder struct {
+	ctx         context.Context
+	client      *Client
+	key         string
+	compression int64
+}
+
+// TDigest starts a t-digest operation.
+//
+// Example:
+//
+//	lat := app.TDigest(ctx, "latency:checkout")
+//	lat.Add(elapsed.Seconds())
+//
+//	p, err := lat.Quantiles(0.5, 0.99) // p50, p99
+func (c *Client) TDigest(ctx context.Context, key string) *TDigestBuilder {
+	return &TDigestBuilder{
+		ctx:         ctx,
+		client:      c,
+		key:         key,
+		compression: 100,
+	}
+}
+
+// Compression sets the accuracy/size trade-off when Add creates the
+// sketch. Default is 100; higher is more accurate and larger.
+func (b *TDigestBuilder) Compression(n int64) *TDigestBuilder {
+	b.compression = n
+	return b
+}
+
+// Add records values, creating the sketch on first use.
+func (b *TDigestBuilder) Add(values ...float64) error {
+	if len(values) == 0 {
+		return nil
+	}
+	args := []any{"TDIGEST.ADD", b.key}
+	for _, v := range values {
+		args = append(args, v)
+	}
+
+	err := b.client.rdb.Do(b.ctx, args...).Err()
+	if err != nil && strings.Contains(err.Error(), "does not exist") {
+		err = b.client.rdb.Do(b.ctx, "TDIGEST.CREATE", b.key, "COMPRESSION", b.compression).Err()
+		if err != nil && !strings.Contains(err.Error(), "already exists") {
+			return moduleError(err, "bf")
+		}
+		err = b.client.rdb.Do(b.ctx, args...).Err()
+	}
+	return moduleError(err, "bf")
+}
+
+// Quantile returns the estimated value at quantile q (0.99 for p99).
+// Returns NaN for an empty sketch.
+func (b *TDigestBuilder) Quantile(q float64) (float64, error) {
+	vals, err := b.Quantiles(q)
+	if err != nil {
+		return 0, err
+	}
+	return vals[0], nil
+}
+
+// Quantiles returns the estimated values at each quantile in qs, in one
+// round trip.
+func (b *TDigestBuilder) Quantiles(qs ...float64) ([]float64, error) {
+	if len(qs) == 0 {
+		return nil, nil
+	}
+	args := []any{"TDIGEST.QUANTILE", b.key}
+	for _, q := range qs {
+		args = append(args, q)
+	}
+	reply, err := b.client.rdb.Do(b.ctx, args...).Slice()
+	if err != nil {
+		return nil, moduleError(err, "bf")
+	}
+	return parseFloats(reply)
+}
+
+// MergeInto stores the merge of this sketch and others at dest,
+// replacing it, e.g. to roll per-instance sketches into a global one.
+func (b *TDigestBuilder) MergeInto(dest string, others ...string) error {
+	keys := append([]string{b.key}, others...)
+	args := []any{"TDIGEST.MERGE", dest, len(keys)}
+	for _, k := range keys {
+		args = append(args, k)
+	}
+	args = append(args, "OVERRIDE")
+	return moduleError(b.client.rdb.Do(b.ctx, args...).Err(), "bf")
+}
+
+// Reset empties the sketch, keeping its compression.
+func (b *TDigestBuilder) Reset() error {
+	return moduleError(b.client.rdb.Do(b.ctx, "TDIGEST.RESET", b.key).Err(), "bf")
+}
+
+// parseFloats reads a list of doubles, sent as strings in RESP2 ("nan",
+// "inf") and as doubles in RESP3.
+func parseFloats(reply []any) ([]float64, error) {
+	vals := make([]float64, len(reply))
+	for i, r := range reply {
+		switch v := r.(type) {
+		case float64:
+			vals[i] = v
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, err
+			}
+			vals[i] = f
+		default:
+			return nil, fmt.Errorf("gibrun: unexpected float reply %T", r)
+		}
+	}
+	return vals, nil
+}
@@ -0,0 +1,233 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Aggregation is a RedisTimeSeries aggregation type for downsampling and
+// range queries.
+type Aggregation string
+
+const (
+	AggAvg   Aggregation = "avg"
+	AggSum   Aggregation = "sum"
+	AggMin   Aggregation = "min"
+	AggMax   Aggregation = "max"
+	AggCount Aggregation = "count"
+	AggFirst Aggregation = "first"
+	AggLast  Aggregation = "last"
+)
+
+// Sample is one time series data point.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// TimeSeriesBuilder provides a fluent API for RedisTimeSeries, for
+// metric-like data such as sensor readings or price ticks on Redis Stack.
+// Commands return ErrUnsupported without the timeseries module.
+type TimeSeriesBuilder struct {
+	ctx       context.Context
+	client    *Client
+	key       string
+	retention time.Duration
+	labels    map[string]string
+}
+
+// TimeSeries starts a time series operation.
+//
+// Example:
+//
+//	temp := app.TimeSeries(ctx, "sensor:42:temp").
+//	    Retention(7*24*time.Hour).
+//	    Labels(map[string]string{"sensor": "42", "kind": "temp"})
+//	temp.Add(21.5)
+//	temp.Downsample("sensor:42:temp:hourly", gibrun.AggAvg, time.Hour)
+//
+//	lastDay, err := temp.Range(time.Now().Add(-24*time.Hour), time.Now())
+func (c *Client) TimeSeries(ctx context.Context, key string) *TimeSeriesBuilder {
+	return &TimeSeriesBuilder{
+		ctx:    ctx,
+		client: c,
+		key:    key,
+	}
+}
+
+// Retention sets how long samples are kept when the series is created.
+// Zero keeps them forever.
+func (b *TimeSeriesBuilder) Retention(d time.Duration) *TimeSeriesBuilder {
+	b.retention = d
+	return b
+}
+
+// Labels sets the labels used by MRange filters when the series is created.
+func (b *TimeSeriesBuilder) Labels(labels map[string]string) *TimeSeriesBuilder {
+	b.labels = labels
+	return b
+}
+
+// Create creates the series with its retention and labels. Succeeds if
+// it already exists. Add creates it on first use too; call Create to set
+// up Downsample rules before any sample arrives.
+func (b *TimeSeriesBuilder) Create() error {
+	return b.create(b.key, b.retention)
+}
+
+// Add appends value timestamped with the client clock.
+func (b *TimeSeriesBuilder) Add(value float64) error {
+	return b.AddAt(b.client.clock.Now(), value)
+}
+
+// AddAt appends value at t, millisecond precision. Adding to an existing
+// timestamp overwrites it.
+func (b *TimeSeriesBuilder) AddAt(t time.Time, value float64) error {
+	args := []any{"TS.ADD", b.key, t.UnixMilli(), value, "ON_DUPLICATE", "LAST"}
+	args = append(args, b.createArgs(b.retention)...)
+	return moduleError(b.client.rdb.Do(b.ctx, args...).Err(), "timeseries")
+}
+
+// Range returns the samples between from and to, inclusive.
+func (b *TimeSeriesBuilder) Range(from, to time.Time) ([]Sample, error) {
+	reply, err := b.client.rdb.Do(b.ctx, "TS.RANGE", b.key, from.UnixMilli(), to.UnixMilli()).Slice()
+	if err != nil {
+		return nil, moduleError(err, "timeseries")
+	}
+	return parseSamples(reply)
+}
+
+// RangeAggregated returns the samples between from and to folded into
+// buckets of the given width.
+func (b *TimeSeriesBuilder) RangeAggregated(from, to time.Time, agg Aggregation, bucket time.Duration) ([]Sample, error) {
+	reply, err := b.client.rdb.Do(b.ctx, "TS.RANGE", b.key, from.UnixMilli(), to.UnixMilli(),
+		"AGGREGATION", string(agg), bucket.Milliseconds()).Slice()
+	if err != nil {
+		return nil, moduleError(err, "timeseries")
+	}
+	return parseSamples(reply)
+}
+
+// Downsample creates dest with the same labels and a rule that
+// continuously aggregates this series into it in buckets of the given
+// width. The destination keeps its samples forever, so long-term
+// trends outlive the raw retention. Succeeds if the rule already exists.
+func (b *TimeSeriesBuilder) Downsample(dest string, agg Aggregation, bucket time.Duration) error {
+	if err := b.Create(); err != nil {
+		return err
+	}
+	if err := b.create(dest, 0); err != nil {
+		return err
+	}
+	err := b.client.rdb.Do(b.ctx, "TS.CREATERULE", b.key, dest, "AGGREGATION", string(agg), bucket.Milliseconds()).Err()
+	if err != nil && strings.Contains(err.Error(), "already has") {
+		return nil
+	}
+	return moduleError(err, "timeseries")
+}
+
+// create runs TS.CREATE for key, ignoring an existing series.
+func (b *TimeSeriesBuilder) create(key string, retention time.Duration) error {
+	args := append([]any{"TS.CREATE", key}, b.createArgs(retention)...)
+	err := b.client.rdb.Do(b.ctx, args...).Err()
+	if err != nil && strings.Contains(err.Error(), "already exists") {
+		return nil
+	}
+	return moduleError(err, "timeseries")
+}
+
+// createArgs renders the RETENTION and LABELS arguments.
+func (b *TimeSeriesBuilder) createArgs(retention time.Duration) []any {
+	var args []any
+	if retention > 0 {
+		args = append(args, "RETENTION", retention.Milliseconds())
+	}
+	if len(b.labels) > 0 {
+		args = append(args, "LABELS")
+		for k, v := range b.labels {
+			args = append(args, k, v)
+		}
+	}
+	return args
+}
+
+// TimeSeriesMRange returns the samples between from and to of every
+// series matching the label filters (TS.MRANGE), by key.
+//
+// Example:
+//
+//	temps, err := app.TimeSeriesMRange(ctx, from, to, "kind=temp")
+func (c *Client) TimeSeriesMRange(ctx context.Context, from, to time.Time, filters ...string) (map[string][]Sample, error) {
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("gibrun: TimeSeriesMRange needs at least one filter")
+	}
+	args := []any{"TS.MRANGE", from.UnixMilli(), to.UnixMilli(), "FILTER"}
+	for _, f := range filters {
+		args = append(args, f)
+	}
+	reply, err := c.rdb.Do(ctx, args...).Result()
+	if err != nil {
+		return nil, moduleError(err, "timeseries")
+	}
+
+	// RESP2 replies [[key, labels, samples], ...], RESP3 {key: [labels, ..., samples]}
+	series := make(map[string][]any)
+	switch r := reply.(type) {
+	case []any:
+		for _, item := range r {
+			if parts, ok := item.([]any); ok && len(parts) >= 2 {
+				series[fmt.Sprint(parts[0])] = parts[1:]
+			}
+		}
+	case map[any]any:
+		for k, v := range r {
+			if parts, ok := v.([]any); ok {
+				series[fmt.Sprint(k)] = parts
+			}
+		}
+	default:
+		return nil, fmt.Errorf("gibrun: unexpected TS.MRANGE reply %T", reply)
+	}
+
+	out := make(map[string][]Sample, len(series))
+	for key, parts := range series {
+		raw, _ := parts[len(parts)-1].([]any)
+		samples, err := parseSamples(raw)
+		if err != nil {
+			return nil, err
+		}
+		out[key] = samples
+	}
+	return out, nil
+}
+
+// parseSamples reads [[timestamp, value], ...]. Values are strings in
+// RESP2 and doubles in RESP3.
+func parseSamples(reply []any) ([]Sample, error) {
+	samples := make([]Sample, 0, len(reply))
+	for _, item := range reply {
+		pair, ok := item.([]any)
+		if !ok || len(pair) != 2 {
+			return nil, fmt.Errorf("gibrun: unexpected time series sample %v", item)
+		}
+		ts, _ := pair[0].(int64)
+		var value float64
+		switch v := pair[1].(type) {
+		case float64:
+			value = v
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, err
+			}
+			value = f
+		default:
+			return nil, fmt.Errorf("gibrun: unexpected time series value %T", v)
+		}
+		samples = append(samples, Sample{Time: time.UnixMilli(ts), Value: value})
+	}
+	return samples, nil
+}
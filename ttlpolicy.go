@@ -0,0 +1,74 @@
+package gibrun
+
+import (
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TTLPolicy sets expiry rules for every key under a prefix, letting
+// platform teams enforce expiry hygiene centrally.
+//
+// Example:
+//
+//	app := gibrun.New(gibrun.Config{
+//	    Addr: "localhost:6379",
+//	    TTLPolicies: []gibrun.TTLPolicy{
+//	        {Prefix: "session:", Default: 30 * time.Minute, Max: 24 * time.Hour},
+//	        {Prefix: "page:", Default: time.Minute, Jitter: 10 * time.Second},
+//	    },
+//	})
+type TTLPolicy struct {
+	// Prefix selects the keys the policy applies to.
+	// When several policies match, the longest prefix wins.
+	Prefix string
+
+	// Default is applied when Gib callers omit TTL().
+	Default time.Duration
+
+	// Max caps any TTL for matching keys. Zero means no cap.
+	Max time.Duration
+
+	// Jitter adds a random duration in [0, Jitter) to Default TTLs to
+	// spread expirations and avoid thundering herds. TTLs set by callers
+	// are kept exact.
+	Jitter time.Duration
+}
+
+// ttlPolicies is a prefix-sorted policy set.
+type ttlPolicies []TTLPolicy
+
+// newTTLPolicies sorts policies so the longest prefix is matched first.
+func newTTLPolicies(policies []TTLPolicy) ttlPolicies {
+	if len(policies) == 0 {
+		return nil
+	}
+	sorted := make(ttlPolicies, len(policies))
+	copy(sorted, policies)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return len(sorted[i].Prefix) > len(sorted[j].Prefix)
+	})
+	return sorted
+}
+
+// apply returns the effective TTL for key given the caller's TTL.
+func (p ttlPolicies) apply(key string, ttl time.Duration) time.Duration {
+	for _, policy := range p {
+		if !strings.HasPrefix(key, policy.Prefix) {
+			continue
+		}
+
+		if ttl <= 0 {
+			ttl = policy.Default
+			if ttl > 0 && policy.Jitter > 0 {
+				ttl += time.Duration(rand.Int63n(int64(policy.Jitter)))
+			}
+		}
+		if policy.Max > 0 && (ttl <= 0 || ttl > policy.Max) {
+			ttl = policy.Max
+		}
+		return ttl
+	}
+	return ttl
+}
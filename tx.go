@@ -0,0 +1,183 @@
+package gibrun
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TxBuilder runs optimistic MULTI/EXEC transactions guarded by WATCH.
+type TxBuilder struct {
+	ctx     context.Context
+	client  *Client
+	keys    []string
+	retries int
+}
+
+// TxOps is passed to the transaction function. Reads run immediately on
+// the watched connection; writes are queued and sent in MULTI/EXEC when
+// the function returns nil.
+type TxOps struct {
+	ctx    context.Context
+	client *Client
+	tx     *redis.Tx
+	writes []func(pipe redis.Pipeliner)
+	err    error
+}
+
+// Tx starts an optimistic transaction.
+//
+// Example:
+//
+//	err := app.Tx(ctx).Watch("account:1", "account:2").Run(func(tx *gibrun.TxOps) error {
+//	    var from, to Account
+//	    if _, err := tx.Bind("account:1", &from); err != nil {
+//	        return err
+//	    }
+//	    if _, err := tx.Bind("account:2", &to); err != nil {
+//	        return err
+//	    }
+//	    from.Balance -= 10
+//	    to.Balance += 10
+//	    tx.Gib("account:1", from, 0)
+//	    tx.Gib("account:2", to, 0)
+//	    return nil
+//	})
+func (c *Client) Tx(ctx context.Context) *TxBuilder {
+	return &TxBuilder{
+		ctx:     ctx,
+		client:  c,
+		retries: 3,
+	}
+}
+
+// Watch sets the keys whose modification aborts the transaction.
+func (b *TxBuilder) Watch(keys ...string) *TxBuilder {
+	b.keys = append(b.keys, keys...)
+	return b
+}
+
+// Retries sets how many times the function is re-run after a WATCH
+// conflict. Default is 3.
+func (b *TxBuilder) Retries(n int) *TxBuilder {
+	b.retries = n
+	return b
+}
+
+// Run executes fn and commits its queued writes atomically. If a watched
+// key changes before EXEC, fn is run again from scratch; after the last
+// retry ErrTxConflict is returned. Errors returned by fn abort without
+// writing anything.
+func (b *TxBuilder) Run(fn func(tx *TxOps) error) error {
+	for attempt := 0; attempt <= b.retries; attempt++ {
+		err := b.client.rdb.Watch(b.ctx, func(tx *redis.Tx) error {
+			ops := &TxOps{ctx: b.ctx, client: b.client, tx: tx}
+			if err := fn(ops); err != nil {
+				return err
+			}
+			if ops.err != nil {
+				return ops.err
+			}
+			if len(ops.writes) == 0 {
+				return nil
+			}
+			_, err := tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+				for _, w := range ops.writes {
+					w(pipe)
+				}
+				return nil
+			})
+			return err
+		}, b.keys...)
+
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return ErrTxConflict
+}
+
+// Bind reads key into dest on the watched connection.
+// Returns (true, nil) if found, (false, nil) if the key doesn't exist.
+func (t *TxOps) Bind(key string, dest any) (bool, error) {
+	if dest == nil {
+		return false, ErrNilPointer
+	}
+
+	data, err := t.tx.Get(t.ctx, key).Bytes()
+	if err == nil && isNegative(data) {
+		err = redis.Nil
+	}
+	if err != nil {
+		if err == redis.Nil {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := t.client.enc.unmarshal(data, dest, nil); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Int reads an integer counter. Missing keys read as 0.
+func (t *TxOps) Int(key string) (int64, error) {
+	n, err := t.tx.Get(t.ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// Gib queues storing value under key with an optional TTL.
+// Values are marshalled exactly like Gib.
+func (t *TxOps) Gib(key string, value any, ttl time.Duration) *TxOps {
+	if value == nil {
+		t.err = ErrNilValue
+		return t
+	}
+	if err := t.client.validate(key, value); err != nil {
+		t.err = err
+		return t
+	}
+	if err := t.client.guardWrite(key); err != nil {
+		t.err = err
+		return t
+	}
+	data, err := t.client.enc.marshal(value, nil)
+	if err != nil {
+		t.err = err
+		return t
+	}
+	ttl = t.client.ttl.apply(key, ttl)
+	t.writes = append(t.writes, func(pipe redis.Pipeliner) {
+		pipe.Set(t.ctx, key, data, ttl)
+	})
+	return t
+}
+
+// Del queues deleting keys.
+func (t *TxOps) Del(keys ...string) *TxOps {
+	t.writes = append(t.writes, func(pipe redis.Pipeliner) {
+		pipe.Del(t.ctx, keys...)
+	})
+	return t
+}
+
+// IncrBy queues incrementing a counter by n.
+func (t *TxOps) IncrBy(key string, n int64) *TxOps {
+	t.writes = append(t.writes, func(pipe redis.Pipeliner) {
+		pipe.IncrBy(t.ctx, key, n)
+	})
+	return t
+}
+
+// Expire queues setting a key TTL.
+func (t *TxOps) Expire(key string, ttl time.Duration) *TxOps {
+	t.writes = append(t.writes, func(pipe redis.Pipeliner) {
+		pipe.PExpire(t.ctx, key, ttl)
+	})
+	return t
+}
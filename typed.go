@@ -0,0 +1,34 @@
+package gibrun
+
+import (
+	"context"
+	"time"
+)
+
+// Get reads key and decodes it into a T, the generic counterpart of
+// Run().Bind. Returns (value, true, nil) on a hit and the zero value with
+// false on a miss.
+//
+// Example:
+//
+//	user, found, err := gibrun.Get[User](ctx, app, "user:123")
+func Get[T any](ctx context.Context, client *Client, key string) (T, bool, error) {
+	var v T
+	found, err := client.Run(ctx, key).Bind(&v)
+	if err != nil || !found {
+		var zero T
+		return zero, false, err
+	}
+	return v, true, nil
+}
+
+// Set stores v under key with an optional TTL, the generic counterpart of
+// Gib().Value().TTL().Exec(). A zero ttl means the data will persist
+// indefinitely unless a TTL policy provides a default.
+//
+// Example:
+//
+//	err := gibrun.Set(ctx, app, "user:123", user, time.Hour)
+func Set[T any](ctx context.Context, client *Client, key string, v T, ttl time.Duration) error {
+	return client.Gib(ctx, key).Value(v).TTL(ttl).Exec()
+}
@@ -0,0 +1,58 @@
+package gibrun
+
+import "context"
+
+// UniqueBuilder provides a fluent API for HyperLogLog unique counting.
+// Counts are estimates (standard error 0.81%) but each key takes at most
+// 12KB no matter how many distinct items are added. Items are encoded
+// like Koalisi members.
+type UniqueBuilder struct {
+	ctx    context.Context
+	client *Client
+	key    string
+}
+
+// Unique starts a unique counting operation.
+//
+// Example:
+//
+//	app.Unique(ctx, "visitors:2024-06-01").Add(userID)
+//	today, err := app.Unique(ctx, "visitors:2024-06-01").Count()
+//
+//	err = app.Unique(ctx, "visitors:2024-06-01").
+//	    MergeInto("visitors:2024-w22", "visitors:2024-06-02", "visitors:2024-06-03")
+func (c *Client) Unique(ctx context.Context, key string) *UniqueBuilder {
+	return &UniqueBuilder{
+		ctx:    ctx,
+		client: c,
+		key:    key,
+	}
+}
+
+// Add records items and reports whether the estimate changed.
+func (b *UniqueBuilder) Add(items ...any) (bool, error) {
+	vals, err := b.client.enc.members(items, nil)
+	if err != nil {
+		return false, err
+	}
+	n, err := b.client.rdb.PFAdd(b.ctx, b.key, vals...).Result()
+	return n == 1, err
+}
+
+// Count returns the estimated number of distinct items.
+// Returns 0 if the key doesn't exist.
+func (b *UniqueBuilder) Count() (int64, error) {
+	return b.client.rdb.PFCount(b.ctx, b.key).Result()
+}
+
+// CountWith returns the estimated number of distinct items across this
+// key and others, without storing the union.
+func (b *UniqueBuilder) CountWith(others ...string) (int64, error) {
+	return b.client.rdb.PFCount(b.ctx, append([]string{b.key}, others...)...).Result()
+}
+
+// MergeInto stores the union of this key and others at dest, keeping
+// whatever dest already counted.
+func (b *UniqueBuilder) MergeInto(dest string, others ...string) error {
+	return b.client.rdb.PFMerge(b.ctx, dest, append([]string{b.key}, others...)...).Err()
+}
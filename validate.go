@@ -0,0 +1,204 @@
+package gibrun
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ConfigError describes a single invalid configuration field.
+// Validate joins all problems found, so callers see every issue at once.
+type ConfigError struct {
+	// Field is the name of the offending field.
+	Field string
+	// Problem explains what is wrong and how to fix it.
+	Problem string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("gibrun: invalid %s: %s", e.Field, e.Problem)
+}
+
+// Validate checks the configuration and returns all problems found,
+// joined with errors.Join, or nil if the configuration is usable.
+//
+// Example:
+//
+//	if err := cfg.Validate(); err != nil {
+//	    log.Fatalf("redis config: %v", err)
+//	}
+func (c Config) Validate() error {
+	var errs []error
+
+	if err := validateAddr("Addr", c.Addr); err != nil {
+		errs = append(errs, err)
+	}
+	if c.DB < 0 {
+		errs = append(errs, &ConfigError{Field: "DB", Problem: fmt.Sprintf("must be >= 0, got %d", c.DB)})
+	}
+	if c.RequireVersion != "" {
+		if _, err := ParseVersion(c.RequireVersion); err != nil {
+			errs = append(errs, &ConfigError{Field: "RequireVersion", Problem: fmt.Sprintf("%q is not a version like 6.2", c.RequireVersion)})
+		}
+	}
+	for i, p := range c.TTLPolicies {
+		field := fmt.Sprintf("TTLPolicies[%d]", i)
+		if p.Default < 0 || p.Max < 0 || p.Jitter < 0 {
+			errs = append(errs, &ConfigError{Field: field, Problem: "durations must not be negative"})
+		}
+		if p.Max > 0 && p.Default > p.Max {
+			errs = append(errs, &ConfigError{Field: field, Problem: fmt.Sprintf("Default %s exceeds Max %s", p.Default, p.Max)})
+		}
+	}
+	if c.ChunkSize < 0 {
+		errs = append(errs, &ConfigError{Field: "ChunkSize", Problem: fmt.Sprintf("must be >= 0, got %d", c.ChunkSize)})
+	}
+	if c.Encryption != nil {
+		if _, key, err := c.Encryption.CurrentKey(); err != nil {
+			errs = append(errs, &ConfigError{Field: "Encryption", Problem: err.Error()})
+		} else if _, err := newGCM(key); err != nil {
+			errs = append(errs, &ConfigError{Field: "Encryption", Problem: "current key must be 16, 24 or 32 bytes"})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the cluster configuration and returns all problems found.
+func (c ClusterConfig) Validate() error {
+	var errs []error
+
+	if len(c.Addrs) == 0 {
+		errs = append(errs, &ConfigError{Field: "Addrs", Problem: "at least one node address is required"})
+	}
+	for i, addr := range c.Addrs {
+		if err := validateAddr(fmt.Sprintf("Addrs[%d]", i), addr); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.MaxRedirects < 0 {
+		errs = append(errs, &ConfigError{Field: "MaxRedirects", Problem: fmt.Sprintf("must be >= 0, got %d", c.MaxRedirects)})
+	}
+	if c.RouteByLatency && c.RouteRandomly {
+		errs = append(errs, &ConfigError{Field: "RouteByLatency", Problem: "conflicts with RouteRandomly, pick one routing strategy"})
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the rate limit configuration and returns all problems found.
+func (c RateLimitConfig) Validate() error {
+	var errs []error
+
+	if c.Rate <= 0 {
+		errs = append(errs, &ConfigError{Field: "Rate", Problem: fmt.Sprintf("must be > 0, got %d", c.Rate)})
+	}
+	if c.Window < time.Second {
+		errs = append(errs, &ConfigError{Field: "Window", Problem: fmt.Sprintf("must be at least 1s, got %s", c.Window)})
+	}
+	if c.BurstSize < 0 {
+		errs = append(errs, &ConfigError{Field: "BurstSize", Problem: fmt.Sprintf("must be >= 0, got %d", c.BurstSize)})
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the job queue configuration and returns all problems found.
+func (c KerjaConfig) Validate() error {
+	var errs []error
+
+	if c.Name == "" {
+		errs = append(errs, &ConfigError{Field: "Name", Problem: "is required"})
+	}
+	if c.Visibility < 0 || c.RetryDelay < 0 || c.PollInterval < 0 {
+		errs = append(errs, &ConfigError{Field: "KerjaConfig", Problem: "durations must not be negative"})
+	}
+	if c.Visibility > 0 && c.Visibility < 3*time.Millisecond {
+		// The heartbeat ticks every Visibility/3
+		errs = append(errs, &ConfigError{Field: "Visibility", Problem: fmt.Sprintf("must be at least 3ms, got %v", c.Visibility)})
+	}
+	if c.MaxAttempts < 0 {
+		errs = append(errs, &ConfigError{Field: "MaxAttempts", Problem: fmt.Sprintf("must be >= 0, got %d", c.MaxAttempts)})
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the outbox configuration and returns all problems found.
+func (c OutboxConfig) Validate() error {
+	var errs []error
+
+	if c.Stream == "" {
+		errs = append(errs, &ConfigError{Field: "Stream", Problem: "is required"})
+	}
+	if c.PollInterval < 0 || c.Lease < 0 || c.RetryDelay < 0 {
+		errs = append(errs, &ConfigError{Field: "OutboxConfig", Problem: "durations must not be negative"})
+	}
+	if c.Lease > 0 && c.Lease < 3*time.Millisecond {
+		// The publish heartbeat ticks every Lease/3
+		errs = append(errs, &ConfigError{Field: "Lease", Problem: fmt.Sprintf("must be at least 3ms, got %v", c.Lease)})
+	}
+	if c.BatchSize < 0 {
+		errs = append(errs, &ConfigError{Field: "BatchSize", Problem: fmt.Sprintf("must be >= 0, got %d", c.BatchSize)})
+	}
+	if c.MaxAttempts < 0 {
+		errs = append(errs, &ConfigError{Field: "MaxAttempts", Problem: fmt.Sprintf("must be >= 0, got %d", c.MaxAttempts)})
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks the event bus configuration and returns all problems found.
+func (c EventBusConfig) Validate() error {
+	var errs []error
+
+	if c.Stream == "" {
+		errs = append(errs, &ConfigError{Field: "Stream", Problem: "is required"})
+	}
+	if c.MaxLen < 0 {
+		errs = append(errs, &ConfigError{Field: "MaxLen", Problem: fmt.Sprintf("must be >= 0, got %d", c.MaxLen)})
+	}
+
+	return errors.Join(errs...)
+}
+
+// Open validates the configuration and creates a new Client.
+// Unlike New, configuration mistakes are reported up front instead of
+// surfacing at the first command.
+//
+// Example:
+//
+//	app, err := gibrun.Open(gibrun.Config{Addr: "localhost:6379"})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func Open(cfg Config) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return New(cfg), nil
+}
+
+// OpenCluster validates the configuration and creates a new ClusterClient.
+func OpenCluster(cfg ClusterConfig) (*ClusterClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return NewCluster(cfg), nil
+}
+
+// validateAddr checks a host:port address. Unix socket paths are accepted.
+func validateAddr(field, addr string) error {
+	if addr == "" {
+		return &ConfigError{Field: field, Problem: "address is empty, expected host:port"}
+	}
+	if strings.HasPrefix(addr, "/") {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return &ConfigError{Field: field, Problem: fmt.Sprintf("%q is not host:port", addr)}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package gibrun
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// ValidateFunc checks a write before it reaches Redis. Returning an
+// error rejects the write; Exec reports it wrapped in ErrValidation.
+type ValidateFunc func(key string, value any) error
+
+// AddValidator registers a validator that runs before every Gib Exec,
+// MGib Exec and Atomic Gib, so platform teams can enforce key-naming
+// conventions and payload limits centrally.
+//
+// Example:
+//
+//	app.AddValidator(gibrun.KeyPattern(regexp.MustCompile(`^[a-z]+:[\w:-]+$`)))
+//	app.AddValidator(gibrun.MaxValueSize(512 * 1024))
+func (c *Client) AddValidator(fn ValidateFunc) {
+	c.validatorsMu.Lock()
+	defer c.validatorsMu.Unlock()
+	c.validators = append(c.validators, fn)
+}
+
+// validate runs all registered validators for a write.
+func (c *Client) validate(key string, value any) error {
+	c.validatorsMu.RLock()
+	validators := c.validators
+	c.validatorsMu.RUnlock()
+
+	for _, fn := range validators {
+		if err := fn(key, value); err != nil {
+			return fmt.Errorf("%w: key %q: %w", ErrValidation, key, err)
+		}
+	}
+	return nil
+}
+
+// KeyPattern returns a validator requiring keys to match re.
+func KeyPattern(re *regexp.Regexp) ValidateFunc {
+	return func(key string, _ any) error {
+		if !re.MatchString(key) {
+			return fmt.Errorf("does not match %s", re)
+		}
+		return nil
+	}
+}
+
+// MaxValueSize returns a validator rejecting values larger than n bytes.
+// Strings and byte slices are measured directly, other values by their
+// JSON size, which approximates the size under most codecs.
+func MaxValueSize(n int) ValidateFunc {
+	return func(_ string, value any) error {
+		var size int
+		switch v := value.(type) {
+		case string:
+			size = len(v)
+		case []byte:
+			size = len(v)
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil
+			}
+			size = len(data)
+		}
+		if size > n {
+			return fmt.Errorf("value is %d bytes, limit is %d", size, n)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package gibrun
+
+import (
+	"bytes"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// casScript writes a versioned value only if the stored version matches.
+// The version lives in an envelope in front of the payload:
+// envelope header | decimal version | "\n" | payload.
+// Missing and unversioned keys count as version 0.
+//
+// KEYS[1] = key
+// ARGV[1] = expected version, ARGV[2] = payload, ARGV[3] = envelope header,
+// ARGV[4] = TTL in milliseconds (0 = none)
+var casScript = redis.NewScript(`
+local cur = redis.call('GET', KEYS[1])
+local v = 0
+if cur and string.sub(cur, 1, 4) == ARGV[3] then
+  local nl = string.find(cur, "\n", 5, true)
+  if nl then
+    v = tonumber(string.sub(cur, 5, nl - 1)) or 0
+  end
+end
+if v ~= tonumber(ARGV[1]) then
+  return -1
+end
+local nv = v + 1
+local payload = ARGV[3] .. nv .. "\n" .. ARGV[2]
+if tonumber(ARGV[4]) > 0 then
+  redis.call('SET', KEYS[1], payload, 'PX', ARGV[4])
+else
+  redis.call('SET', KEYS[1], payload)
+end
+return nv
+`)
+
+// versionHeader is the envelope header for versioned values.
+var versionHeader = string(envelopeMagic) + string(envelopeVersioned)
+
+// IfVersion makes Exec an optimistic compare-and-set: the write only
+// succeeds if the stored version equals n, and bumps it to n+1.
+// Missing or unversioned keys are version 0. When another writer got
+// there first, Exec returns ErrVersionConflict.
+//
+// Read the current version with Run().BindVersion.
+//
+// Example:
+//
+//	var cart Cart
+//	version, _, _ := app.Run(ctx, "cart:42").BindVersion(&cart)
+//	cart.Items = append(cart.Items, item)
+//	err := app.Gib(ctx, "cart:42").Value(cart).IfVersion(version).Exec()
+//	if errors.Is(err, gibrun.ErrVersionConflict) {
+//	    // reload and retry
+//	}
+func (b *GibBuilder) IfVersion(n int64) *GibBuilder {
+	b.ifVersion = &n
+	return b
+}
+
+// execVersioned runs the compare-and-set script.
+func (b *GibBuilder) execVersioned(data []byte) error {
+	ttl := b.client.ttl.apply(b.key, b.ttl)
+	if !b.client.scripting(b.ctx) {
+		return b.versionedFallback(data, ttl)
+	}
+	res, err := casScript.Run(b.ctx, b.client.rdb, []string{b.key},
+		*b.ifVersion, data, versionHeader, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+	if res < 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// versionedFallback mirrors casScript with WATCH/MULTI.
+func (b *GibBuilder) versionedFallback(data []byte, ttl time.Duration) error {
+	return b.client.watchRetry(b.ctx, func(tx *redis.Tx) error {
+		cur, err := tx.Get(b.ctx, b.key).Bytes()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		v, _ := splitVersion(cur)
+		if v != *b.ifVersion {
+			return ErrVersionConflict
+		}
+
+		payload := append([]byte(versionHeader+strconv.FormatInt(v+1, 10)+"\n"), data...)
+		_, err = tx.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(b.ctx, b.key, payload, ttl)
+			return nil
+		})
+		return err
+	}, b.key)
+}
+
+// BindVersion is like Bind but also returns the stored version written
+// by IfVersion. Unversioned values report version 0.
+func (b *RunBuilder) BindVersion(dest any) (int64, bool, error) {
+	if dest == nil {
+		return 0, false, ErrNilPointer
+	}
+
+	data, err := b.get()
+	if err != nil {
+		if err == redis.Nil {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+
+	version, _ := splitVersion(data)
+	if err := b.client.enc.unmarshal(data, dest, b.codec); err != nil {
+		return 0, false, err
+	}
+	return version, true, nil
+}
+
+// splitVersion strips a version envelope, returning the version and payload.
+// Data without a version envelope is returned as version 0.
+func splitVersion(data []byte) (int64, []byte) {
+	if envelopeKind(data) != envelopeVersioned {
+		return 0, data
+	}
+	nl := bytes.IndexByte(data[envelopeHeaderLen:], '\n')
+	if nl < 0 {
+		return 0, data
+	}
+	v, err := strconv.ParseInt(string(data[envelopeHeaderLen:envelopeHeaderLen+nl]), 10, 64)
+	if err != nil {
+		return 0, data
+	}
+	return v, data[envelopeHeaderLen+nl+1:]
+}
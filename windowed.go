@@ -0,0 +1,136 @@
+package gibrun
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// defaultWindowKeep is how many buckets Windowed keeps by default.
+const defaultWindowKeep = 60
+
+// BucketCount is one bucket of a windowed counter.
+type BucketCount struct {
+	// Start is when the bucket begins.
+	Start time.Time
+	// Count is the bucket total, 0 for buckets without increments.
+	Count int64
+}
+
+// WindowedSprint is a counter split into time buckets, see Windowed.
+type WindowedSprint struct {
+	b      *SprintBuilder
+	bucket time.Duration
+	keep   int
+	// err reports an invalid bucket from every call.
+	err error
+}
+
+// Windowed turns the counter into per-bucket keys ("name:1m0s:<unix ms>"),
+// each expiring once it falls out of the kept range (60 buckets unless
+// Keep says otherwise). Query recent totals with SumLast and Series for
+// cheap requests-per-minute style metrics. Buckets shorter than a
+// millisecond are rejected by every call.
+//
+// Example:
+//
+//	rpm := app.Sprint(ctx, "api:requests").Windowed(time.Minute)
+//	rpm.Incr()
+//	lastHour, err := rpm.SumLast(60)
+func (b *SprintBuilder) Windowed(bucket time.Duration) *WindowedSprint {
+	w := &WindowedSprint{b: b, bucket: bucket, keep: defaultWindowKeep}
+	if bucket < time.Millisecond {
+		w.err = &ConfigError{Field: "bucket", Problem: "must be at least 1ms"}
+	}
+	return w
+}
+
+// Keep sets how many buckets are retained.
+func (w *WindowedSprint) Keep(n int) *WindowedSprint {
+	w.keep = n
+	return w
+}
+
+// Incr adds 1 to the current bucket and returns the bucket total.
+func (w *WindowedSprint) Incr() (int64, error) {
+	return w.IncrBy(1)
+}
+
+// IncrBy adds n to the current bucket and returns the bucket total.
+func (w *WindowedSprint) IncrBy(n int64) (int64, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	b := w.b
+	key := w.key(w.start(b.client.clock.Now()))
+
+	var incr *redis.IntCmd
+	_, err := b.client.rdb.TxPipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.IncrBy(b.ctx, key, n)
+		pipe.PExpire(b.ctx, key, w.bucket*time.Duration(w.keep+1))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+// SumLast returns the total of the last n buckets, including the current one.
+func (w *WindowedSprint) SumLast(n int) (int64, error) {
+	series, err := w.Series(n)
+	if err != nil {
+		return 0, err
+	}
+	var sum int64
+	for _, s := range series {
+		sum += s.Count
+	}
+	return sum, nil
+}
+
+// Series returns the last n buckets, oldest first, ending with the
+// current one. Uses a single MGET.
+func (w *WindowedSprint) Series(n int) ([]BucketCount, error) {
+	if w.err != nil {
+		return nil, w.err
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+	b := w.b
+	current := w.start(b.client.clock.Now())
+
+	series := make([]BucketCount, n)
+	keys := make([]string, n)
+	for i := range series {
+		series[i].Start = current.Add(-time.Duration(n-1-i) * w.bucket)
+		keys[i] = w.key(series[i].Start)
+	}
+
+	vals, err := b.client.rdb.MGet(b.ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, v := range vals {
+		s, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if series[i].Count, err = strconv.ParseInt(s, 10, 64); err != nil {
+			return nil, err
+		}
+	}
+	return series, nil
+}
+
+// start returns the beginning of the bucket holding t.
+func (w *WindowedSprint) start(t time.Time) time.Time {
+	return t.Truncate(w.bucket)
+}
+
+// key returns the bucket key for a bucket start.
+func (w *WindowedSprint) key(start time.Time) string {
+	return w.b.key + ":" + w.bucket.String() + ":" + strconv.FormatInt(start.UnixMilli(), 10)
+}
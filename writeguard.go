@@ -0,0 +1,86 @@
+package gibrun
+
+import (
+	"sync"
+	"time"
+)
+
+// WriteGuardConfig detects single keys being overwritten at pathological
+// rates, surfacing accidental hot-write loops before they saturate a
+// shard. Counting is per process.
+type WriteGuardConfig struct {
+	// MaxRate is the number of writes per second to one key considered
+	// pathological, e.g. 1000.
+	MaxRate int
+
+	// Window is the counting window. Default is 1 second.
+	Window time.Duration
+
+	// Throttle rejects writes above the rate with ErrWriteThrottled.
+	// By default writes go through and only OnHotKey is called.
+	Throttle bool
+
+	// OnHotKey is called once per window when a key exceeds the rate.
+	OnHotKey func(key string, writesPerSec float64)
+}
+
+// writeGuard counts writes per key in fixed windows.
+type writeGuard struct {
+	cfg   WriteGuardConfig
+	clock Clock
+	limit int
+
+	mu     sync.Mutex
+	start  time.Time
+	counts map[string]int
+}
+
+func newWriteGuard(cfg WriteGuardConfig, clock Clock) *writeGuard {
+	if cfg.Window <= 0 {
+		cfg.Window = time.Second
+	}
+	limit := int(float64(cfg.MaxRate) * cfg.Window.Seconds())
+	if limit < 1 {
+		limit = 1
+	}
+	return &writeGuard{
+		cfg:    cfg,
+		clock:  clock,
+		limit:  limit,
+		start:  clock.Now(),
+		counts: make(map[string]int),
+	}
+}
+
+// check counts a write to key, reporting and optionally rejecting it
+// when the key is hot.
+func (g *writeGuard) check(key string) error {
+	g.mu.Lock()
+	now := g.clock.Now()
+	if now.Sub(g.start) >= g.cfg.Window {
+		g.start = now
+		g.counts = make(map[string]int, len(g.counts))
+	}
+	g.counts[key]++
+	n := g.counts[key]
+	g.mu.Unlock()
+
+	if n <= g.limit {
+		return nil
+	}
+	if n == g.limit+1 && g.cfg.OnHotKey != nil {
+		g.cfg.OnHotKey(key, float64(n)/g.cfg.Window.Seconds())
+	}
+	if g.cfg.Throttle {
+		return ErrWriteThrottled
+	}
+	return nil
+}
+
+// guardWrite runs the write guard for key, if configured.
+func (c *Client) guardWrite(key string) error {
+	if c.writeGuard == nil {
+		return nil
+	}
+	return c.writeGuard.check(key)
+}
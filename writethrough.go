@@ -0,0 +1,156 @@
+package gibrun
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// WritePolicy defines how write-through handles failures on either side.
+type WritePolicy int
+
+const (
+	// WriteDBFirst persists first and only caches on success. If the cache
+	// write then fails, the key is deleted so readers fall back to the store.
+	// This is the default.
+	WriteDBFirst WritePolicy = iota
+
+	// WriteCacheFirst caches first and persists afterwards. A persist
+	// failure is returned but the cache keeps the new value.
+	WriteCacheFirst
+
+	// WriteBothOrRollback caches first, then persists; if persisting fails
+	// the previous cache value (and its TTL) is restored. Only plain string
+	// values can be rolled back: AsHash, AsJSON and values stored in
+	// chunks, before or after the write, fail with ErrUnsupported.
+	WriteBothOrRollback
+)
+
+// WriteThrough writes to the backing store together with the cache,
+// following the policy set with WritePolicy (default WriteDBFirst).
+//
+// Example:
+//
+//	err := app.Gib(ctx, "user:123").
+//	    Value(user).
+//	    TTL(time.Hour).
+//	    WriteThrough(func(ctx context.Context) error { return db.SaveUser(ctx, user) }).
+//	    Exec()
+func (b *GibBuilder) WriteThrough(persist func(ctx context.Context) error) *GibBuilder {
+	b.persist = persist
+	return b
+}
+
+// WritePolicy sets the failure policy used by WriteThrough.
+func (b *GibBuilder) WritePolicy(p WritePolicy) *GibBuilder {
+	b.policy = p
+	return b
+}
+
+// execWriteThrough coordinates the cache write and the persister.
+func (b *GibBuilder) execWriteThrough() error {
+	switch b.policy {
+	case WriteCacheFirst:
+		if err := b.store(); err != nil {
+			return err
+		}
+		if err := b.persist(b.ctx); err != nil {
+			return fmt.Errorf("gibrun: write-through persist failed (cache updated): %w", err)
+		}
+		return nil
+
+	case WriteBothOrRollback:
+		if err := b.checkRollback(); err != nil {
+			return err
+		}
+		old, oldTTL, existed, err := b.snapshot()
+		if err != nil {
+			return err
+		}
+		if err := b.store(); err != nil {
+			return err
+		}
+		if err := b.persist(b.ctx); err != nil {
+			if rbErr := b.rollback(old, oldTTL, existed); rbErr != nil {
+				return fmt.Errorf("gibrun: write-through persist failed: %w (rollback failed: %v)", err, rbErr)
+			}
+			return fmt.Errorf("gibrun: write-through persist failed (cache rolled back): %w", err)
+		}
+		return nil
+
+	default:
+		if err := b.persist(b.ctx); err != nil {
+			return err
+		}
+		if err := b.store(); err != nil {
+			// Don't leave a stale entry behind the new persisted value
+			b.client.rdb.Del(context.WithoutCancel(b.ctx), b.key)
+			return fmt.Errorf("gibrun: write-through cache write failed (persisted): %w", err)
+		}
+		return nil
+	}
+}
+
+// checkRollback rejects writes that snapshot and rollback can't restore
+// with a plain GET and SET.
+func (b *GibBuilder) checkRollback() error {
+	if b.asHash || b.asJSON {
+		return fmt.Errorf("%w: WriteBothOrRollback can't be combined with AsHash or AsJSON", ErrUnsupported)
+	}
+	if b.client.chunkSize <= 0 || b.negative || b.appendMode {
+		return nil
+	}
+	data, err := b.client.enc.marshalSchema(b.value, b.codec, b.schema)
+	if err != nil {
+		return err
+	}
+	if len(data) > b.client.chunkSize {
+		return fmt.Errorf("%w: WriteBothOrRollback can't roll back chunked values", ErrUnsupported)
+	}
+	return nil
+}
+
+// snapshot captures the raw stored value and TTL for rollback.
+func (b *GibBuilder) snapshot() ([]byte, time.Duration, bool, error) {
+	var getCmd *redis.StringCmd
+	var ttlCmd *redis.DurationCmd
+	_, err := b.client.rdb.Pipelined(b.ctx, func(pipe redis.Pipeliner) error {
+		getCmd = pipe.Get(b.ctx, b.key)
+		ttlCmd = pipe.PTTL(b.ctx, b.key)
+		return nil
+	})
+	if isWrongType(err) {
+		return nil, 0, false, fmt.Errorf("%w: WriteBothOrRollback can only roll back string values", ErrUnsupported)
+	}
+	if err != nil && err != redis.Nil {
+		return nil, 0, false, err
+	}
+
+	old, err := getCmd.Bytes()
+	if err == redis.Nil {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if envelopeKind(old) == envelopeChunked {
+		return nil, 0, false, fmt.Errorf("%w: WriteBothOrRollback can't roll back chunked values", ErrUnsupported)
+	}
+
+	ttl := ttlCmd.Val()
+	if ttl < 0 {
+		ttl = 0
+	}
+	return old, ttl, true, nil
+}
+
+// rollback restores a snapshot taken before the cache write.
+func (b *GibBuilder) rollback(old []byte, ttl time.Duration, existed bool) error {
+	ctx := context.WithoutCancel(b.ctx)
+	if !existed {
+		return b.client.rdb.Del(ctx, b.key).Err()
+	}
+	return b.client.rdb.Set(ctx, b.key, old, ttl).Err()
+}